@@ -0,0 +1,62 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go-galaxy/inter"
+)
+
+// Downtime is a validator's downtime as reported by the abft namespace.
+type Downtime struct {
+	OfflineBlocks idx.Block
+	OfflineTime   inter.Timestamp
+}
+
+type rpcDowntime struct {
+	OfflineBlocks hexutil.Uint64 `json:"offlineBlocks"`
+	OfflineTime   hexutil.Uint64 `json:"offlineTime"`
+}
+
+// GetDowntime returns validator's downtime.
+func (ec *Client) GetDowntime(ctx context.Context, validatorID idx.ValidatorID) (*Downtime, error) {
+	var raw rpcDowntime
+	err := ec.c.CallContext(ctx, &raw, "abft_getDowntime", hexutil.Uint64(validatorID))
+	if err != nil {
+		return nil, err
+	}
+	return &Downtime{
+		OfflineBlocks: idx.Block(raw.OfflineBlocks),
+		OfflineTime:   inter.Timestamp(raw.OfflineTime),
+	}, nil
+}
+
+// GetEpochUptime returns validator's epoch uptime in nanoseconds.
+func (ec *Client) GetEpochUptime(ctx context.Context, validatorID idx.ValidatorID) (inter.Timestamp, error) {
+	var raw hexutil.Uint64
+	err := ec.c.CallContext(ctx, &raw, "abft_getEpochUptime", hexutil.Uint64(validatorID))
+	if err != nil {
+		return 0, err
+	}
+	return inter.Timestamp(raw), nil
+}
+
+// GetOriginatedEpochFee returns validator's originated epoch fee.
+func (ec *Client) GetOriginatedEpochFee(ctx context.Context, validatorID idx.ValidatorID) (*big.Int, error) {
+	var raw *hexutil.Big
+	err := ec.c.CallContext(ctx, &raw, "abft_getOriginatedEpochFee", hexutil.Uint64(validatorID))
+	if err != nil {
+		return nil, err
+	}
+	return toBig(raw), nil
+}
+
+func toBig(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v.ToInt()
+}
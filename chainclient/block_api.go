@@ -0,0 +1,98 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-galaxy/inter"
+)
+
+// HeaderExt is a block header with the chain-specific fields, which are lost
+// by types.Header decoding: the original block hash and the time in nanoseconds.
+// ReceiptsRoot and Bloom are filled only if the node has RPCBlockExt enabled.
+type HeaderExt struct {
+	Number       idx.Block
+	Hash         common.Hash
+	ParentHash   common.Hash
+	Root         common.Hash
+	TxHash       common.Hash
+	ReceiptsRoot common.Hash
+	Bloom        types.Bloom
+	Coinbase     common.Address
+	GasUsed      uint64
+	Time         inter.Timestamp
+	BaseFee      *big.Int
+	Size         uint64
+}
+
+type rpcHeaderExt struct {
+	Number       hexutil.Uint64  `json:"number"`
+	Hash         common.Hash     `json:"hash"`
+	ParentHash   common.Hash     `json:"parentHash"`
+	Root         common.Hash     `json:"stateRoot"`
+	TxHash       common.Hash     `json:"transactionsRoot"`
+	ReceiptsRoot *common.Hash    `json:"receiptsRoot"`
+	Bloom        *types.Bloom    `json:"logsBloom"`
+	Coinbase     common.Address  `json:"miner"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	TimeNano     hexutil.Uint64  `json:"timestampNano"`
+	BaseFee      *hexutil.Big    `json:"baseFeePerGas"`
+	Size         *hexutil.Uint64 `json:"size"`
+}
+
+func (r *rpcHeaderExt) toHeaderExt() *HeaderExt {
+	h := &HeaderExt{
+		Number:     idx.Block(r.Number),
+		Hash:       r.Hash,
+		ParentHash: r.ParentHash,
+		Root:       r.Root,
+		TxHash:     r.TxHash,
+		Coinbase:   r.Coinbase,
+		GasUsed:    uint64(r.GasUsed),
+		Time:       inter.Timestamp(r.TimeNano),
+	}
+	if r.ReceiptsRoot != nil {
+		h.ReceiptsRoot = *r.ReceiptsRoot
+	}
+	if r.Bloom != nil {
+		h.Bloom = *r.Bloom
+	}
+	if r.BaseFee != nil {
+		h.BaseFee = r.BaseFee.ToInt()
+	}
+	if r.Size != nil {
+		h.Size = uint64(*r.Size)
+	}
+	return h
+}
+
+// HeaderExtByNumber returns a block header with chain-specific fields from the current canonical chain.
+// If number is nil, the latest known header is returned.
+func (ec *Client) HeaderExtByNumber(ctx context.Context, number *big.Int) (*HeaderExt, error) {
+	var raw *rpcHeaderExt
+	err := ec.c.CallContext(ctx, &raw, "eth_getHeaderByNumber", toBlockNumArg(number))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return raw.toHeaderExt(), nil
+}
+
+// HeaderExtByHash returns the block header with chain-specific fields with the given hash.
+func (ec *Client) HeaderExtByHash(ctx context.Context, hash common.Hash) (*HeaderExt, error) {
+	var raw *rpcHeaderExt
+	err := ec.c.CallContext(ctx, &raw, "eth_getHeaderByHash", hash)
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return raw.toHeaderExt(), nil
+}
@@ -0,0 +1,155 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/integration/makegenesis"
+)
+
+func TestClientDagAndBlocks(t *testing.T) {
+	require := require.New(t)
+	client := startFakenet(t)
+	ctx := context.Background()
+
+	waitForBlock(t, client, 1)
+
+	header, err := client.HeaderExtByNumber(ctx, big.NewInt(1))
+	require.NoError(err)
+	require.Equal(idx.Block(1), header.Number)
+	require.NotZero(header.Time)
+
+	byHash, err := client.HeaderExtByHash(ctx, header.Hash)
+	require.NoError(err)
+	require.Equal(header, byHash)
+
+	_, err = client.HeaderExtByHash(ctx, common.Hash{1})
+	require.Equal(ethereum.NotFound, err)
+
+	var heads hash.Events
+	for i := 0; i < 300 && len(heads) == 0; i++ {
+		heads, err = client.GetHeads(ctx, big.NewInt(-1))
+		require.NoError(err)
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NotEmpty(heads)
+
+	event, err := client.GetEvent(ctx, heads[0])
+	require.NoError(err)
+	require.Equal(heads[0], event.ID())
+
+	stats, err := client.GetEpochStats(ctx, nil)
+	require.NoError(err)
+	require.Equal(idx.Epoch(1), stats.Epoch)
+	require.NotNil(stats.TotalFee)
+}
+
+func TestClientAbft(t *testing.T) {
+	require := require.New(t)
+	client := startFakenet(t)
+	ctx := context.Background()
+
+	downtime, err := client.GetDowntime(ctx, 1)
+	require.NoError(err)
+	require.Equal(idx.Block(0), downtime.OfflineBlocks)
+
+	_, err = client.GetEpochUptime(ctx, 1)
+	require.NoError(err)
+
+	fee, err := client.GetOriginatedEpochFee(ctx, 1)
+	require.NoError(err)
+	require.NotNil(fee)
+}
+
+func TestClientSfc(t *testing.T) {
+	require := require.New(t)
+	client := startFakenet(t)
+	ctx := context.Background()
+	validator := makegenesis.GetFakeValidators(1).Map()[1]
+
+	_, err := client.GetStaker(ctx, 100, false)
+	require.Equal(ethereum.NotFound, err)
+
+	_, err = client.GetDelegation(ctx, common.Address{1}, 1, false)
+	require.Equal(ethereum.NotFound, err)
+
+	ids, err := client.GetStakerIDs(ctx)
+	require.NoError(err)
+	stakers, err := client.GetStakers(ctx, true)
+	require.NoError(err)
+	require.Equal(len(ids), len(stakers))
+	for i, s := range stakers {
+		require.Equal(ids[i], s.ID)
+		require.NotNil(s.Metrics)
+	}
+
+	delegations, err := client.GetDelegationsByAddress(ctx, validator.Address, true)
+	require.NoError(err)
+	for _, d := range delegations {
+		require.Equal(validator.Address, d.Address)
+		require.NotNil(d.ClaimedRewards)
+	}
+
+	_, err = client.GetStakerDowntime(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetValidationScore(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetOriginationScore(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetStakerPoI(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetStakerClaimedRewards(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetStakerDelegationsClaimedRewards(ctx, 1)
+	require.NoError(err)
+	_, err = client.GetDelegationClaimedRewards(ctx, validator.Address, 1)
+	require.NoError(err)
+}
+
+func TestClientSubscriptions(t *testing.T) {
+	require := require.New(t)
+	client := startFakenet(t)
+	ctx := context.Background()
+
+	heads := make(chan *HeaderExt, 1)
+	sub, err := client.SubscribeNewHeadsExt(ctx, heads)
+	require.NoError(err)
+	defer sub.Unsubscribe()
+
+	txs := make(chan common.Hash, 1)
+	txSub, err := client.SubscribeNewPendingTransactions(ctx, txs)
+	require.NoError(err)
+	txSub.Unsubscribe()
+
+	select {
+	case h := <-heads:
+		require.NotZero(h.Number)
+		require.NotEqual(common.Hash{}, h.Hash)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(30 * time.Second):
+		t.Fatal("no new heads")
+	}
+}
+
+func waitForBlock(t *testing.T, client *Client, n uint64) {
+	for i := 0; i < 300; i++ {
+		current, err := client.BlockNumber(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current >= n {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("block %d isn't reached", n)
+}
@@ -0,0 +1,119 @@
+package chainclient
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/abft"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/kvdb/leveldb"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy/genesisstore"
+	"go-galaxy/gossip"
+	"go-galaxy/gossip/emitter"
+	"go-galaxy/integration"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter/validatorpk"
+	"go-galaxy/utils"
+	"go-galaxy/valkeystore"
+	"go-galaxy/vecmt"
+)
+
+// startFakenet starts an in-process fakenet node of the single validator
+// and returns the client attached to it.
+func startFakenet(t *testing.T) *Client {
+	genStore := makegenesis.FakeGenesisStore(2, 1, utils.ToUnit(1000000000), utils.ToUnit(5000000))
+	genesis := integration.InputGenesis{
+		Hash: genStore.Hash(),
+		Read: func(store *genesisstore.Store) error {
+			buf := bytes.NewBuffer(nil)
+			err := genStore.Export(buf)
+			if err != nil {
+				return err
+			}
+			return store.Import(buf)
+		},
+		Close: func() error {
+			return nil
+		},
+	}
+	cfg := integration.Configs{
+		Galaxy:        gossip.DefaultConfig(cachescale.Identity),
+		GalaxyStore:   gossip.LiteStoreConfig(),
+		Lachesis:      abft.DefaultConfig(),
+		LachesisStore: abft.LiteStoreConfig(),
+		VectorClock:   vecmt.LiteConfig(),
+	}
+	engine, dagIndex, gdb, cdb, _, blockProc := integration.MakeEngine(leveldb.NewProducer(t.TempDir(), cache16mb), genesis, cfg)
+
+	stack, err := node.New(&node.Config{
+		P2P: p2p.Config{
+			NoDiscovery: true,
+			MaxPeers:    0,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTxPool := func(reader evmcore.StateReader) gossip.TxPool {
+		txPoolCfg := evmcore.DefaultTxPoolConfig
+		txPoolCfg.Journal = ""
+		return evmcore.NewTxPool(txPoolCfg, reader.Config(), reader)
+	}
+	svc, err := gossip.NewService(stack, cfg.Galaxy, gdb, blockProc, engine, dagIndex, newTxPool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const validatorID = idx.ValidatorID(1)
+	emitterCfg := emitter.FakeConfig(1)
+	emitterCfg.Validator.ID = validatorID
+	emitterCfg.Validator.PubKey = makegenesis.GetFakeValidators(1).Map()[validatorID].PubKey
+	valKeystore := valkeystore.NewDefaultMemKeystore()
+	err = valKeystore.Add(emitterCfg.Validator.PubKey, crypto.FromECDSA(makegenesis.FakeKey(validatorID)), validatorpk.FakePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = valKeystore.Unlock(emitterCfg.Validator.PubKey, validatorpk.FakePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.RegisterEmitter(emitter.NewEmitter(emitterCfg, svc.EmitterWorld(valkeystore.NewSigner(valKeystore))))
+
+	err = engine.Bootstrap(svc.GetConsensusCallbacks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack.RegisterAPIs(svc.APIs())
+	stack.RegisterProtocols(svc.Protocols())
+	stack.RegisterLifecycle(svc)
+
+	err = stack.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpcClient, err := stack.Attach()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		rpcClient.Close()
+		_ = stack.Close()
+		gdb.Close()
+		_ = cdb.Close()
+	})
+
+	return NewClient(rpcClient)
+}
+
+func cache16mb(string) int {
+	return 16 * opt.MiB
+}
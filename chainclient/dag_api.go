@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -64,19 +65,45 @@ func (ec *Client) GetHeads(ctx context.Context, epoch *big.Int) (hash.Events, er
 	return inter.HexToEventIDs(raw), nil
 }
 
+// EpochStats is the epoch statistics as returned by the dag namespace.
+type EpochStats struct {
+	Epoch                 idx.Epoch
+	Start                 inter.Timestamp
+	End                   inter.Timestamp
+	TotalFee              *big.Int
+	TotalBaseRewardWeight *big.Int
+	TotalTxRewardWeight   *big.Int
+}
+
+type rpcEpochStats struct {
+	Epoch                 hexutil.Uint64 `json:"epoch"`
+	Start                 hexutil.Uint64 `json:"start"`
+	End                   hexutil.Uint64 `json:"end"`
+	TotalFee              *hexutil.Big   `json:"totalFee"`
+	TotalBaseRewardWeight *hexutil.Big   `json:"totalBaseRewardWeight"`
+	TotalTxRewardWeight   *hexutil.Big   `json:"totalTxRewardWeight"`
+}
+
 // GetEpochStats returns epoch statistics.
 // * When epoch is -2 the statistics for latest epoch is returned.
 // * When epoch is -1 the statistics for latest sealed epoch is returned.
-func (ec *Client) GetEpochStats(ctx context.Context, epoch *big.Int) (map[string]interface{}, error) {
-	var raw map[string]interface{}
-	err := ec.c.CallContext(ctx, &raw, "dag_getEpochStats", toBlockNumArg(epoch))
+func (ec *Client) GetEpochStats(ctx context.Context, epoch *big.Int) (*EpochStats, error) {
+	var raw *rpcEpochStats
+	err := ec.c.CallContext(ctx, &raw, "deam_getEpochStats", toBlockNumArg(epoch))
 	if err != nil {
 		return nil, err
-	} else if len(raw) == 0 {
+	} else if raw == nil {
 		return nil, ethereum.NotFound
 	}
 
-	return raw, nil
+	return &EpochStats{
+		Epoch:                 idx.Epoch(raw.Epoch),
+		Start:                 inter.Timestamp(raw.Start),
+		End:                   inter.Timestamp(raw.End),
+		TotalFee:              toBig(raw.TotalFee),
+		TotalBaseRewardWeight: toBig(raw.TotalBaseRewardWeight),
+		TotalTxRewardWeight:   toBig(raw.TotalTxRewardWeight),
+	}, nil
 }
 
 func toBlockNumArg(number *big.Int) string {
@@ -0,0 +1,345 @@
+package chainclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go-galaxy/inter"
+)
+
+// Verbosity levels of the sfc namespace queries.
+const (
+	sfcVerbosityIDs     = 0
+	sfcVerbosityBase    = 1
+	sfcVerbosityMetrics = 2
+)
+
+// Staker is an SFC staker as returned by the sfc namespace.
+type Staker struct {
+	ID               idx.ValidatorID
+	Address          common.Address
+	TotalStake       *big.Int
+	Stake            *big.Int
+	DelegatedMe      *big.Int
+	IsValidator      bool
+	IsActive         bool
+	IsCheater        bool
+	IsOffline        bool
+	CreatedEpoch     idx.Epoch
+	CreatedTime      inter.Timestamp
+	DeactivatedEpoch idx.Epoch
+	DeactivatedTime  inter.Timestamp
+
+	// Metrics are filled only if requested.
+	Metrics *StakerMetrics
+}
+
+// StakerMetrics are the SFC staker's metrics.
+type StakerMetrics struct {
+	MissedBlocks              idx.Block
+	Downtime                  inter.Timestamp
+	PoI                       *big.Int
+	BaseRewardWeight          *big.Int
+	TxRewardWeight            *big.Int
+	ValidationScore           *big.Int
+	OriginationScore          *big.Int
+	ClaimedRewards            *big.Int
+	DelegationsClaimedRewards *big.Int
+}
+
+// Delegation is an SFC delegation as returned by the sfc namespace.
+type Delegation struct {
+	Address          common.Address
+	ToStakerID       idx.ValidatorID
+	Amount           *big.Int
+	CreatedEpoch     idx.Epoch
+	CreatedTime      inter.Timestamp
+	DeactivatedEpoch idx.Epoch
+	DeactivatedTime  inter.Timestamp
+
+	// ClaimedRewards is filled only if metrics are requested.
+	ClaimedRewards *big.Int
+}
+
+// RewardWeights are the staker's reward weights.
+type RewardWeights struct {
+	BaseRewardWeight *big.Int
+	TxRewardWeight   *big.Int
+}
+
+type rpcStaker struct {
+	ID               hexutil.Uint64 `json:"id"`
+	Address          common.Address `json:"address"`
+	TotalStake       *hexutil.Big   `json:"totalStake"`
+	Stake            *hexutil.Big   `json:"stake"`
+	DelegatedMe      *hexutil.Big   `json:"delegatedMe"`
+	IsValidator      bool           `json:"isValidator"`
+	IsActive         bool           `json:"isActive"`
+	IsCheater        bool           `json:"isCheater"`
+	IsOffline        bool           `json:"isOffline"`
+	CreatedEpoch     hexutil.Uint64 `json:"createdEpoch"`
+	CreatedTime      hexutil.Uint64 `json:"createdTime"`
+	DeactivatedEpoch hexutil.Uint64 `json:"deactivatedEpoch"`
+	DeactivatedTime  hexutil.Uint64 `json:"deactivatedTime"`
+
+	MissedBlocks              *hexutil.Uint64 `json:"missedBlocks"`
+	Downtime                  *hexutil.Uint64 `json:"downtime"`
+	PoI                       *hexutil.Big    `json:"poi"`
+	BaseRewardWeight          *hexutil.Big    `json:"baseRewardWeight"`
+	TxRewardWeight            *hexutil.Big    `json:"txRewardWeight"`
+	ValidationScore           *hexutil.Big    `json:"validationScore"`
+	OriginationScore          *hexutil.Big    `json:"originationScore"`
+	ClaimedRewards            *hexutil.Big    `json:"claimedRewards"`
+	DelegationsClaimedRewards *hexutil.Big    `json:"delegationsClaimedRewards"`
+}
+
+func (r *rpcStaker) toStaker() *Staker {
+	s := &Staker{
+		ID:               idx.ValidatorID(r.ID),
+		Address:          r.Address,
+		TotalStake:       toBig(r.TotalStake),
+		Stake:            toBig(r.Stake),
+		DelegatedMe:      toBig(r.DelegatedMe),
+		IsValidator:      r.IsValidator,
+		IsActive:         r.IsActive,
+		IsCheater:        r.IsCheater,
+		IsOffline:        r.IsOffline,
+		CreatedEpoch:     idx.Epoch(r.CreatedEpoch),
+		CreatedTime:      inter.Timestamp(r.CreatedTime),
+		DeactivatedEpoch: idx.Epoch(r.DeactivatedEpoch),
+		DeactivatedTime:  inter.Timestamp(r.DeactivatedTime),
+	}
+	if r.MissedBlocks != nil {
+		s.Metrics = &StakerMetrics{
+			MissedBlocks:              idx.Block(*r.MissedBlocks),
+			PoI:                       toBig(r.PoI),
+			BaseRewardWeight:          toBig(r.BaseRewardWeight),
+			TxRewardWeight:            toBig(r.TxRewardWeight),
+			ValidationScore:           toBig(r.ValidationScore),
+			OriginationScore:          toBig(r.OriginationScore),
+			ClaimedRewards:            toBig(r.ClaimedRewards),
+			DelegationsClaimedRewards: toBig(r.DelegationsClaimedRewards),
+		}
+		if r.Downtime != nil {
+			s.Metrics.Downtime = inter.Timestamp(*r.Downtime)
+		}
+	}
+	return s
+}
+
+type rpcDelegation struct {
+	Address          common.Address `json:"address"`
+	ToStakerID       hexutil.Uint64 `json:"toStakerID"`
+	Amount           *hexutil.Big   `json:"amount"`
+	CreatedEpoch     hexutil.Uint64 `json:"createdEpoch"`
+	CreatedTime      hexutil.Uint64 `json:"createdTime"`
+	DeactivatedEpoch hexutil.Uint64 `json:"deactivatedEpoch"`
+	DeactivatedTime  hexutil.Uint64 `json:"deactivatedTime"`
+	ClaimedRewards   *hexutil.Big   `json:"claimedRewards"`
+}
+
+func (r *rpcDelegation) toDelegation() *Delegation {
+	d := &Delegation{
+		Address:          r.Address,
+		ToStakerID:       idx.ValidatorID(r.ToStakerID),
+		Amount:           toBig(r.Amount),
+		CreatedEpoch:     idx.Epoch(r.CreatedEpoch),
+		CreatedTime:      inter.Timestamp(r.CreatedTime),
+		DeactivatedEpoch: idx.Epoch(r.DeactivatedEpoch),
+		DeactivatedTime:  inter.Timestamp(r.DeactivatedTime),
+	}
+	if r.ClaimedRewards != nil {
+		d.ClaimedRewards = r.ClaimedRewards.ToInt()
+	}
+	return d
+}
+
+func sfcVerbosity(withMetrics bool) hexutil.Uint64 {
+	if withMetrics {
+		return sfcVerbosityMetrics
+	}
+	return sfcVerbosityBase
+}
+
+// GetStaker returns SFC staker's info.
+// Staker's metrics are included if withMetrics is true.
+func (ec *Client) GetStaker(ctx context.Context, stakerID idx.ValidatorID, withMetrics bool) (*Staker, error) {
+	var raw *rpcStaker
+	err := ec.c.CallContext(ctx, &raw, "sfc_getStaker", hexutil.Uint64(stakerID), sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return raw.toStaker(), nil
+}
+
+// GetStakerByAddress returns SFC staker's info by address.
+// Staker's metrics are included if withMetrics is true.
+func (ec *Client) GetStakerByAddress(ctx context.Context, address common.Address, withMetrics bool) (*Staker, error) {
+	var raw *rpcStaker
+	err := ec.c.CallContext(ctx, &raw, "sfc_getStakerByAddress", address, sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return raw.toStaker(), nil
+}
+
+// GetStakerIDs returns IDs of all the SFC stakers.
+func (ec *Client) GetStakerIDs(ctx context.Context) ([]idx.ValidatorID, error) {
+	var raw []hexutil.Uint64
+	err := ec.c.CallContext(ctx, &raw, "sfc_getStakers", hexutil.Uint64(sfcVerbosityIDs))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]idx.ValidatorID, len(raw))
+	for i, id := range raw {
+		ids[i] = idx.ValidatorID(id)
+	}
+	return ids, nil
+}
+
+// GetStakers returns SFC stakers info.
+// Stakers' metrics are included if withMetrics is true.
+func (ec *Client) GetStakers(ctx context.Context, withMetrics bool) ([]*Staker, error) {
+	var raw []*rpcStaker
+	err := ec.c.CallContext(ctx, &raw, "sfc_getStakers", sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	}
+	stakers := make([]*Staker, len(raw))
+	for i, s := range raw {
+		stakers[i] = s.toStaker()
+	}
+	return stakers, nil
+}
+
+// GetDelegation returns SFC delegation info.
+// Claimed rewards are included if withMetrics is true.
+func (ec *Client) GetDelegation(ctx context.Context, address common.Address, stakerID idx.ValidatorID, withMetrics bool) (*Delegation, error) {
+	var raw *rpcDelegation
+	err := ec.c.CallContext(ctx, &raw, "sfc_getDelegation", address, hexutil.Uint64(stakerID), sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return raw.toDelegation(), nil
+}
+
+// GetDelegatorsOf returns addresses of SFC delegators who delegated to a staker.
+func (ec *Client) GetDelegatorsOf(ctx context.Context, stakerID idx.ValidatorID) ([]common.Address, error) {
+	var raw []common.Address
+	err := ec.c.CallContext(ctx, &raw, "sfc_getDelegationsOf", hexutil.Uint64(stakerID), hexutil.Uint64(sfcVerbosityIDs))
+	return raw, err
+}
+
+// GetDelegationsOf returns SFC delegations who delegated to a staker.
+// Claimed rewards are included if withMetrics is true.
+func (ec *Client) GetDelegationsOf(ctx context.Context, stakerID idx.ValidatorID, withMetrics bool) ([]*Delegation, error) {
+	var raw []*rpcDelegation
+	err := ec.c.CallContext(ctx, &raw, "sfc_getDelegationsOf", hexutil.Uint64(stakerID), sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	}
+	return toDelegations(raw), nil
+}
+
+// GetDelegationsByAddress returns SFC delegations by address.
+// Claimed rewards are included if withMetrics is true.
+func (ec *Client) GetDelegationsByAddress(ctx context.Context, address common.Address, withMetrics bool) ([]*Delegation, error) {
+	var raw []*rpcDelegation
+	err := ec.c.CallContext(ctx, &raw, "sfc_getDelegationsByAddress", address, sfcVerbosity(withMetrics))
+	if err != nil {
+		return nil, err
+	}
+	return toDelegations(raw), nil
+}
+
+func toDelegations(raw []*rpcDelegation) []*Delegation {
+	dd := make([]*Delegation, len(raw))
+	for i, d := range raw {
+		dd[i] = d.toDelegation()
+	}
+	return dd
+}
+
+// GetStakerDowntime returns staker's downtime as reported by the sfc namespace.
+func (ec *Client) GetStakerDowntime(ctx context.Context, stakerID idx.ValidatorID) (*Downtime, error) {
+	var raw struct {
+		MissedBlocks hexutil.Uint64 `json:"missedBlocks"`
+		Downtime     hexutil.Uint64 `json:"downtime"`
+	}
+	err := ec.c.CallContext(ctx, &raw, "sfc_getDowntime", hexutil.Uint64(stakerID))
+	if err != nil {
+		return nil, err
+	}
+	return &Downtime{
+		OfflineBlocks: idx.Block(raw.MissedBlocks),
+		OfflineTime:   inter.Timestamp(raw.Downtime),
+	}, nil
+}
+
+// GetRewardWeights returns staker's reward weights.
+func (ec *Client) GetRewardWeights(ctx context.Context, stakerID idx.ValidatorID) (*RewardWeights, error) {
+	var raw *struct {
+		BaseRewardWeight *hexutil.Big `json:"baseRewardWeight"`
+		TxRewardWeight   *hexutil.Big `json:"txRewardWeight"`
+	}
+	err := ec.c.CallContext(ctx, &raw, "sfc_getRewardWeights", hexutil.Uint64(stakerID))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+	return &RewardWeights{
+		BaseRewardWeight: toBig(raw.BaseRewardWeight),
+		TxRewardWeight:   toBig(raw.TxRewardWeight),
+	}, nil
+}
+
+// GetValidationScore returns staker's ValidationScore.
+func (ec *Client) GetValidationScore(ctx context.Context, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getValidationScore", hexutil.Uint64(stakerID))
+}
+
+// GetOriginationScore returns staker's OriginationScore.
+func (ec *Client) GetOriginationScore(ctx context.Context, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getOriginationScore", hexutil.Uint64(stakerID))
+}
+
+// GetStakerPoI returns staker's PoI.
+func (ec *Client) GetStakerPoI(ctx context.Context, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getStakerPoI", hexutil.Uint64(stakerID))
+}
+
+// GetStakerClaimedRewards returns sum of claimed rewards in past, by this staker.
+func (ec *Client) GetStakerClaimedRewards(ctx context.Context, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getStakerClaimedRewards", hexutil.Uint64(stakerID))
+}
+
+// GetStakerDelegationsClaimedRewards returns sum of claimed rewards in past, by this delegations of this staker.
+func (ec *Client) GetStakerDelegationsClaimedRewards(ctx context.Context, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getStakerDelegationsClaimedRewards", hexutil.Uint64(stakerID))
+}
+
+// GetDelegationClaimedRewards returns sum of claimed rewards in past, by this delegation.
+func (ec *Client) GetDelegationClaimedRewards(ctx context.Context, address common.Address, stakerID idx.ValidatorID) (*big.Int, error) {
+	return ec.callBig(ctx, "sfc_getDelegationClaimedRewards", address, hexutil.Uint64(stakerID))
+}
+
+func (ec *Client) callBig(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	var raw *hexutil.Big
+	err := ec.c.CallContext(ctx, &raw, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return toBig(raw), nil
+}
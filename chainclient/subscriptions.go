@@ -0,0 +1,48 @@
+package chainclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// SubscribeNewPendingTransactions subscribes to notifications about hashes of transactions
+// which are added into the node's transaction pool.
+func (ec *Client) SubscribeNewPendingTransactions(ctx context.Context, ch chan<- common.Hash) (ethereum.Subscription, error) {
+	return ec.c.EthSubscribe(ctx, ch, "newPendingTransactions")
+}
+
+// SubscribeNewHeadsExt subscribes to notifications about the new blocks.
+// Unlike SubscribeNewHead, it delivers headers with chain-specific fields.
+func (ec *Client) SubscribeNewHeadsExt(ctx context.Context, ch chan<- *HeaderExt) (ethereum.Subscription, error) {
+	heads := make(chan *types.Header)
+	sub, err := ec.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case h := <-heads:
+				header, err := ec.HeaderExtByNumber(ctx, h.Number)
+				if err != nil {
+					return err
+				}
+				select {
+				case ch <- header:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
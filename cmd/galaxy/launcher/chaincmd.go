@@ -47,7 +47,29 @@ Events are fully verified by default, unless overridden by --check=false flag.`,
 				Description: `
     galaxy import evm
 
-The import command imports EVM storage (trie nodes, code, preimages) from files.`,
+The import command imports EVM storage (trie nodes, code, preimages) from files.
+Files produced by 'galaxy export evm' are detected by the file header, the state trie
+is regenerated from the accounts, storage and code, and the state root is verified.`,
+			},
+			{
+				Action:    utils.MigrateFlags(importBlocks),
+				Name:      "blocks",
+				Usage:     "Import block and epoch records",
+				ArgsUsage: "<filename> (<filename 2> ... <filename N>)",
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    galaxy import blocks
+
+The import command imports LLR block and epoch records from files.
+Records are applied without re-running consensus. LLR votes of the file are checked
+like the votes received from peers, and every record is checked against the LLR voting result.
+The import fails if some records aren't decided by the votes, e.g. if the file starts
+after the history known to the node.
+
+The command also back-fills the history missing after snap sync from files produced
+by 'galaxy export blocks', as an alternative to downloading it from peers with --history.backfill.`,
 			},
 		},
 	}
@@ -72,6 +94,62 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last epoch to write. If the file ends with .gz, the output will
 be gzipped
+`,
+			},
+			{
+				Name:      "blocks",
+				Usage:     "Export block and epoch records",
+				ArgsUsage: "<filename> [<blockFrom> <blockTo>]",
+				Action:    utils.MigrateFlags(exportBlocks),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    galaxy export blocks
+
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last block to write. Records of epochs within the range and
+LLR votes for the records are written too. If the file ends
+with .gz, the output will be gzipped
+`,
+			},
+			{
+				Name:      "evm",
+				Usage:     "Export EVM state",
+				ArgsUsage: "<filename> [<root>]",
+				Action:    utils.MigrateFlags(exportEvm),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    galaxy export evm
+
+Requires a first argument of the file to write to.
+Optional second argument is the state root to export, the latest
+finalized state root is exported by default. The state is read
+from the EVM snapshot, which must be available for the root.
+Accounts, storage and code are written into a versioned compressed
+file with a checksum, which is imported by 'galaxy import evm'.
+`,
+			},
+			{
+				Name:      "dag",
+				Usage:     "Export events DAG for visualisation",
+				ArgsUsage: "<epochFrom> [<epochTo>]",
+				Action:    utils.MigrateFlags(exportDag),
+				Flags: []cli.Flag{
+					DataDirFlag,
+					DagFormatFlag,
+					DagValidatorsFlag,
+				},
+				Description: `
+    galaxy export dag --format=dot|json <epochFrom> [<epochTo>]
+
+Writes events of the epochs range to stdout as a Graphviz graph or as JSON.
+Every event is exported with its creator, seq, lamport, frame and median time,
+roots and Atroposes are marked. Edges are the parent references.
+Use --validators to export only events of the specified validators.
 `,
 			},
 		},
@@ -93,6 +171,49 @@ be gzipped
     galaxy check evm
 
 Checks EVM storage roots and code hashes
+`,
+			},
+		},
+	}
+	indexCommand = cli.Command{
+		Name:     "index",
+		Usage:    "Build blockchain indexes",
+		Category: "MISCELLANEOUS COMMANDS",
+
+		Subcommands: []cli.Command{
+			{
+				Name:      "addresses",
+				Usage:     "Index transactions by their senders and recipients",
+				ArgsUsage: "[<blockFrom> <blockTo>]",
+				Action:    utils.MigrateFlags(indexAddresses),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    galaxy index addresses [<blockFrom> <blockTo>]
+
+Builds the address transactions index for the blocks which were processed
+before enabling AddressIndex in the config. All the blocks are indexed by default,
+optional arguments control the first and last block to index.
+The node must be stopped while the command runs.
+`,
+			},
+			{
+				Name:      "tokens",
+				Usage:     "Index ERC-20/721/1155 token transfers and balances",
+				ArgsUsage: "[<blockFrom> <blockTo>]",
+				Action:    utils.MigrateFlags(indexTokens),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    galaxy index tokens [<blockFrom> <blockTo>]
+
+Builds the token transfers index for the blocks which were processed
+before enabling TokenIndex in the config, using the logs index.
+All the blocks are indexed by default, optional arguments control the first and last block to index.
+Balances are exact only if all the blocks since the tokens deployment are indexed.
+The node must be stopped while the command runs.
 `,
 			},
 		},
@@ -30,8 +30,7 @@ import (
 	"go-galaxy/gossip/emitter"
 	"go-galaxy/gossip/gasprice"
 	"go-galaxy/integration"
-	"go-galaxy/integration/makegenesis"
-	futils "go-galaxy/utils"
+	"go-galaxy/rpcauth"
 	"go-galaxy/vecmt"
 )
 
@@ -89,12 +88,37 @@ var (
 		Value: gossip.DefaultConfig(cachescale.Identity).RPCTxFeeCap,
 	}
 
+	RPCAuthAddrFlag = cli.StringFlag{
+		Name:  "rpc.auth.addr",
+		Usage: "Authenticated HTTP-RPC and WS-RPC server listening interface (clients, anonymous access and call costs are configured in the RPCAuth section of the config file)",
+	}
+	RPCAuthPortFlag = cli.IntFlag{
+		Name:  "rpc.auth.port",
+		Usage: "Authenticated HTTP-RPC and WS-RPC server listening port",
+		Value: rpcauth.DefaultPort,
+	}
+
 	SyncModeFlag = cli.StringFlag{
 		Name:  "syncmode",
 		Usage: `Blockchain sync mode ("full" or "snap")`,
 		Value: "full",
 	}
 
+	HistoryBackfillFlag = cli.BoolFlag{
+		Name:  "history.backfill",
+		Usage: "Download historical blocks, receipts and txs missing after snap sync from peers in background",
+	}
+
+	FreezerFlag = cli.BoolFlag{
+		Name:  "freezer",
+		Usage: "Move final events, blocks and receipts into append-only files of the ancient directory (default = <datadir>/ancient)",
+	}
+
+	EvmPruningPeriodFlag = cli.DurationFlag{
+		Name:  "prune.online",
+		Usage: "Period of the online pruning of the stale EVM state while the node runs (0 = disabled)",
+	}
+
 	AllowedGalaxyGenesisHashes = map[uint64]hash.Hash{
 		galaxy.MainNetworkID: hash.HexToHash("0xc87401a30d0eee3c51048bf3bdffd489a596f69c09f04995cafd30a2d1d0c413"),
 		galaxy.TestNetworkID: hash.HexToHash("0x4d0d8fcc0d764a41ff9f011606d8a620ad66fd45f2a4532d33d61bee6672a8af"),
@@ -130,7 +154,9 @@ type config struct {
 	Lachesis      abft.Config
 	LachesisStore abft.StoreConfig
 	VectorClock   vecmt.IndexConfig
+	RPCAuth       rpcauth.Config
 	cachescale    cachescale.Func
+	configFile    string // loaded config file, empty if none
 }
 
 func (c *config) AppConfigs() integration.Configs {
@@ -173,21 +199,15 @@ func getGalaxyGenesis(ctx *cli.Context) integration.InputGenesis {
 		if err != nil {
 			log.Crit("Invalid flag", "flag", FakeNetFlag.Name, "err", err)
 		}
-		fakeGenesisStore := makegenesis.FakeGenesisStore(2, num, futils.ToUnit(1000000000), futils.ToUnit(5000000))
-		genesis = integration.InputGenesis{
-			Hash: fakeGenesisStore.Hash(),
-			Read: func(store *genesisstore.Store) error {
-				buf := bytes.NewBuffer(nil)
-				err = fakeGenesisStore.Export(buf)
-				if err != nil {
-					return err
-				}
-				return store.Import(buf)
-			},
-			Close: func() error {
-				return nil
-			},
+		genesisCfg, err := fakeGenesisConfigWithFlags(ctx, defaultFakeGenesisConfig(num))
+		if err != nil {
+			utils.Fatalf("Invalid fakenet genesis: %v", err)
+		}
+		fakeGenesisStore, err := makeFakeGenesisStore(genesisCfg)
+		if err != nil {
+			utils.Fatalf("Invalid fakenet genesis: %v", err)
 		}
+		genesis = memInputGenesis(fakeGenesisStore)
 	case ctx.GlobalIsSet(GenesisFlag.Name):
 		genesisPath := ctx.GlobalString(GenesisFlag.Name)
 
@@ -211,6 +231,24 @@ func getGalaxyGenesis(ctx *cli.Context) integration.InputGenesis {
 	return genesis
 }
 
+// memInputGenesis makes the input genesis, which copies the in-memory genesis store
+func memInputGenesis(genStore *genesisstore.Store) integration.InputGenesis {
+	return integration.InputGenesis{
+		Hash: genStore.Hash(),
+		Read: func(store *genesisstore.Store) error {
+			buf := bytes.NewBuffer(nil)
+			err := genStore.Export(buf)
+			if err != nil {
+				return err
+			}
+			return store.Import(buf)
+		},
+		Close: func() error {
+			return nil
+		},
+	}
+}
+
 func setBootnodes(ctx *cli.Context, urls []string, cfg *node.Config) {
 	cfg.P2P.BootstrapNodesV5 = []*enode.Node{}
 	for _, url := range urls {
@@ -286,6 +324,31 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) {
 	}
 }
 
+func setRPCAuth(ctx *cli.Context, cfg *rpcauth.Config) {
+	if ctx.GlobalIsSet(RPCAuthAddrFlag.Name) {
+		cfg.Host = ctx.GlobalString(RPCAuthAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCAuthPortFlag.Name) {
+		cfg.Port = ctx.GlobalInt(RPCAuthPortFlag.Name)
+	}
+}
+
+// setFreezer enables the freezer if it's requested by the flags, or if the default ancient directory exists.
+// Once enabled, the freezer stays enabled, because the DB refers to the frozen data.
+func setFreezer(ctx *cli.Context, cfg *gossip.FreezerConfig, datadir string) {
+	if ctx.GlobalIsSet(utils.AncientFlag.Name) {
+		cfg.Dir = ctx.GlobalString(utils.AncientFlag.Name)
+	}
+	if cfg.Dir != "" || datadir == "" {
+		return
+	}
+	// the directory is outside of chaindata, which contains only DBs
+	dir := path.Join(datadir, "ancient")
+	if _, err := os.Stat(dir); ctx.GlobalBool(FreezerFlag.Name) || err == nil {
+		cfg.Dir = dir
+	}
+}
+
 func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config, error) {
 	cfg := src
 
@@ -303,6 +366,12 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config,
 		}
 		cfg.AllowSnapsync = ctx.GlobalString(SyncModeFlag.Name) == "snap"
 	}
+	if ctx.GlobalIsSet(HistoryBackfillFlag.Name) {
+		cfg.HistoryBackfill = ctx.GlobalBool(HistoryBackfillFlag.Name)
+	}
+	if ctx.GlobalIsSet(EvmPruningPeriodFlag.Name) {
+		cfg.EvmPruningPeriod = ctx.GlobalDuration(EvmPruningPeriodFlag.Name)
+	}
 
 	return cfg, nil
 }
@@ -352,6 +421,7 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		Lachesis:      abft.DefaultConfig(),
 		LachesisStore: abft.DefaultStoreConfig(cacheRatio),
 		VectorClock:   vecmt.DefaultConfig(cacheRatio),
+		RPCAuth:       rpcauth.DefaultConfig(),
 		cachescale:    cacheRatio,
 	}
 
@@ -367,6 +437,7 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		if err := loadAllConfigs(file, &cfg); err != nil {
 			return &cfg, err
 		}
+		cfg.configFile = file
 	}
 
 	// Apply flags (high priority)
@@ -380,6 +451,8 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		return nil, err
 	}
 	cfg.Node = nodeConfigWithFlags(ctx, cfg.Node)
+	setRPCAuth(ctx, &cfg.RPCAuth)
+	setFreezer(ctx, &cfg.GalaxyStore.Freezer, cfg.Node.DataDir)
 
 	err = setValidator(ctx, &cfg.Emitter)
 	if err != nil {
@@ -393,6 +466,9 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 	if err := cfg.Galaxy.Validate(); err != nil {
 		return nil, err
 	}
+	if err := cfg.RPCAuth.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
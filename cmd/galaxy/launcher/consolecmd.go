@@ -18,8 +18,11 @@ package launcher
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 
@@ -28,6 +31,8 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
 	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/cmd/galaxy/launcher/web3ext"
 )
 
 var (
@@ -87,17 +92,7 @@ func localConsole(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("Failed to attach to the inproc galaxy: %v", err)
 	}
-	config := console.Config{
-		DataDir: utils.MakeDataDir(ctx),
-		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
-		Client:  client,
-		Preload: utils.MakeConsolePreloads(ctx),
-	}
-
-	console, err := console.New(config)
-	if err != nil {
-		utils.Fatalf("Failed to start the JavaScript console: %v", err)
-	}
+	console := newConsole(ctx, client)
 	defer console.Stop(false)
 
 	// If only a short execution was requested, evaluate and return
@@ -128,17 +123,7 @@ func remoteConsole(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("Unable to attach to remote galaxy: %v", err)
 	}
-	config := console.Config{
-		DataDir: utils.MakeDataDir(ctx),
-		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
-		Client:  client,
-		Preload: utils.MakeConsolePreloads(ctx),
-	}
-
-	console, err := console.New(config)
-	if err != nil {
-		utils.Fatalf("Failed to start the JavaScript console: %v", err)
-	}
+	console := newConsole(ctx, client)
 	defer console.Stop(false)
 
 	if script := ctx.GlobalString(utils.ExecFlag.Name); script != "" {
@@ -153,6 +138,75 @@ func remoteConsole(ctx *cli.Context) error {
 	return nil
 }
 
+// newConsole starts a JavaScript console with the extensions of galaxy specific namespaces
+// which are served by the node.
+func newConsole(ctx *cli.Context, client *rpc.Client) *console.Console {
+	extensions, cleanup, err := writeConsoleExtensions(client)
+	if err != nil {
+		utils.Fatalf("Failed to prepare the JavaScript console extensions: %v", err)
+	}
+	// extensions are loaded on the console start
+	defer cleanup()
+
+	config := console.Config{
+		DataDir: utils.MakeDataDir(ctx),
+		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
+		Client:  client,
+		Preload: append(extensions, utils.MakeConsolePreloads(ctx)...),
+	}
+
+	c, err := console.New(config)
+	if err != nil {
+		utils.Fatalf("Failed to start the JavaScript console: %v", err)
+	}
+	return c
+}
+
+// writeConsoleExtensions writes the extensions of galaxy specific namespaces served by the node into
+// a temporary directory, because the console preloads JS only from files. Returns the files to preload
+// and the function which removes them.
+func writeConsoleExtensions(client *rpc.Client) (files []string, cleanup func(), err error) {
+	apis, err := client.SupportedModules()
+	if err != nil {
+		return nil, nil, fmt.Errorf("api modules: %v", err)
+	}
+	names := make([]string, 0, len(web3ext.Modules))
+	for api := range apis {
+		if _, ok := web3ext.Modules[api]; ok {
+			names = append(names, api)
+		}
+	}
+	if len(names) == 0 {
+		return nil, func() {}, nil
+	}
+	sort.Strings(names)
+
+	dir, err := ioutil.TempDir("", "galaxy-console")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() {
+		_ = os.RemoveAll(dir)
+	}
+	write := func(name, src string) error {
+		file := filepath.Join(dir, name+".js")
+		files = append(files, file)
+		return ioutil.WriteFile(file, []byte(src), 0600)
+	}
+	// formatters are used by all the extensions
+	if err := write("formatters", web3ext.FormattersJs); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	for _, name := range names {
+		if err := write(name, web3ext.Modules[name]); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+	return files, cleanup, nil
+}
+
 // dialRPC returns a RPC client which connects to the given endpoint.
 // The check for empty endpoint implements the defaulting logic
 // for "galaxy attach" and "galaxy monitor" with no argument.
@@ -182,17 +236,7 @@ func ephemeralConsole(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("Failed to attach to the inproc galaxy: %v", err)
 	}
-	config := console.Config{
-		DataDir: utils.MakeDataDir(ctx),
-		DocRoot: ctx.GlobalString(utils.JSpathFlag.Name),
-		Client:  client,
-		Preload: utils.MakeConsolePreloads(ctx),
-	}
-
-	console, err := console.New(config)
-	if err != nil {
-		utils.Fatalf("Failed to start the JavaScript console: %v", err)
-	}
+	console := newConsole(ctx, client)
 	defer console.Stop(false)
 
 	// Evaluate each of the specified JavaScript files
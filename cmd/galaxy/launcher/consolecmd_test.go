@@ -1,6 +1,7 @@
 package launcher
 
 import (
+	"bytes"
 	"crypto/rand"
 	"math/big"
 	"os"
@@ -11,9 +12,13 @@ import (
 	"testing"
 	"time"
 
+	"go-galaxy/cmd/galaxy/launcher/web3ext"
 	"go-galaxy/integration/makegenesis"
 
+	"github.com/ethereum/go-ethereum/console"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -135,6 +140,74 @@ To exit, press ctrl-d
 	attach.ExpectExit()
 }
 
+// Tests that the extensions of galaxy specific namespaces are loaded by the console
+// and call the in-process node.
+func TestConsoleExtensions(t *testing.T) {
+	require := require.New(t)
+
+	svc, _ := makeTestService(t)
+
+	// starts the console over the in-process RPC server of the namespaces
+	startConsole := func(namespaces ...string) (*console.Console, *bytes.Buffer, []string) {
+		srv := rpc.NewServer()
+		for _, api := range svc.APIs() {
+			for _, ns := range namespaces {
+				if api.Namespace == ns {
+					require.NoError(srv.RegisterName(api.Namespace, api.Service))
+				}
+			}
+		}
+		client := rpc.DialInProc(srv)
+		t.Cleanup(client.Close)
+
+		preload, cleanup, err := writeConsoleExtensions(client)
+		require.NoError(err)
+		t.Cleanup(cleanup)
+
+		printer := new(bytes.Buffer)
+		c, err := console.New(console.Config{
+			DataDir: t.TempDir(),
+			DocRoot: t.TempDir(),
+			Client:  client,
+			Printer: printer,
+			Preload: preload,
+		})
+		require.NoError(err)
+		t.Cleanup(func() {
+			_ = c.Stop(false)
+		})
+		return c, printer, preload
+	}
+	evaluate := func(c *console.Console, printer *bytes.Buffer, statement string) string {
+		printer.Reset()
+		c.Evaluate(statement)
+		return strings.TrimSpace(printer.String())
+	}
+
+	c, printer, preload := startConsole("eth", "deam", "dag", "abft", "sfc")
+	// formatters and all the extensions
+	require.Len(preload, 1+len(web3ext.Modules))
+	for _, tc := range []struct {
+		statement string
+		output    string
+	}{
+		{"deam.currentEpoch", "2"},
+		{"deam.getRules().Dag.MaxParents", "10"},
+		{"dag.getHeads().length", "0"},
+		{"abft.getDowntime(1).offlineBlocks", "0"},
+		{"sfc.getStaker(1, 0).id", "1"},
+		{"sfc.getStakers(0).length", "1"},
+	} {
+		require.Equal(tc.output, evaluate(c, printer, tc.statement), tc.statement)
+	}
+
+	// extensions of namespaces which aren't served aren't loaded
+	c, printer, preload = startConsole("deam")
+	require.Len(preload, 2)
+	require.Equal("2", evaluate(c, printer, "deam.currentEpoch"))
+	require.Equal(`"undefined"`, evaluate(c, printer, "typeof sfc"))
+}
+
 // trulyRandInt generates a crypto random integer used by the console tests to
 // not clash network ports with other tests running cocurrently.
 func trulyRandInt(lo, hi int) int {
@@ -0,0 +1,175 @@
+package launcher
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/gossip/emitter"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	"go-galaxy/inter/validatorpk"
+)
+
+var (
+	DevnetValidatorsFlag = cli.IntFlag{
+		Name:  "devnet.validators",
+		Usage: "Number of validator nodes",
+		Value: 3,
+	}
+	DevnetBlockPeriodFlag = cli.DurationFlag{
+		Name:  "devnet.blockperiod",
+		Usage: "Interval between blocks when there are no transactions",
+		Value: time.Second,
+	}
+	DevnetHTTPPortFlag = cli.IntFlag{
+		Name:  "devnet.http.port",
+		Usage: "HTTP-RPC port of the first node, the next nodes listen on the following ports",
+		Value: DefaultHTTPPort,
+	}
+
+	devnetCommand = cli.Command{
+		Action:   utils.MigrateFlags(devnet),
+		Name:     "devnet",
+		Usage:    "Run a local network of multiple validators in one process",
+		Category: "MISCELLANEOUS COMMANDS",
+		Flags: []cli.Flag{
+			DataDirFlag,
+			CacheFlag,
+			DevnetValidatorsFlag,
+			DevnetBlockPeriodFlag,
+			DevnetHTTPPortFlag,
+			FakeNetAccountsFlag,
+			FakeNetRulesFlag,
+			FakeNetStakesFlag,
+			FakeNetEpochFlag,
+			FakeNetTimeFlag,
+		},
+		Description: `
+    galaxy devnet --devnet.validators 4 --fakenet.accounts 0x239fA7623354eC26520dE878B52f13Fe84b06971:1000
+
+Starts the validator nodes of a fake network in one process. The nodes are connected
+to each other over the loopback interface, and every node serves HTTP-RPC on its own port.
+The chain is kept in the --datadir if specified, otherwise in a temporary directory,
+which is removed on exit. Genesis is customized with the same --fakenet.* flags as fakenet.`,
+	}
+)
+
+// devnetNodeConfig makes config of the i-th devnet node out of the common config
+func devnetNodeConfig(base config, datadir string, id idx.ValidatorID, num idx.Validator, blockPeriod time.Duration, httpPort int) *config {
+	cfg := base
+	// derived configs can't be reloaded from the config file
+	cfg.configFile = ""
+	cfg.Node.DataDir = datadir
+	cfg.Node.P2P.ListenAddr = "127.0.0.1:0"
+	cfg.Node.P2P.NoDiscovery = true
+	cfg.Node.P2P.DiscoveryV5 = false
+	cfg.Node.P2P.BootstrapNodes = nil
+	cfg.Node.P2P.BootstrapNodesV5 = nil
+	cfg.Node.P2P.NAT = nil
+	cfg.Node.HTTPHost = "127.0.0.1"
+	cfg.Node.HTTPPort = httpPort
+	cfg.Node.WSHost = ""
+
+	if base.GalaxyStore.Freezer.Dir != "" {
+		cfg.GalaxyStore.Freezer.Dir = path.Join(datadir, "ancient")
+	}
+
+	cfg.Emitter = emitter.FakeConfig(num)
+	cfg.Emitter.EmitIntervals.Max = blockPeriod
+	if num > 1 {
+		cfg.Emitter.EmitIntervals.DoublesignProtection = blockPeriod / 2
+	}
+	cfg.Emitter.Validator.ID = id
+	cfg.Emitter.Validator.PubKey = makegenesis.GetFakeValidators(num).Map()[id].PubKey
+	cfg.Emitter.PrevEmittedEventFile.Path = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("last-%d", id)))
+	return &cfg
+}
+
+// devnetGenesisConfig makes the genesis config of the devnet out of the flags
+func devnetGenesisConfig(ctx *cli.Context, num idx.Validator, blockPeriod time.Duration) (fakeGenesisConfig, error) {
+	genesisCfg := defaultFakeGenesisConfig(num)
+	genesisCfg.Rules.Blocks.MaxEmptyBlockSkipPeriod = inter.Timestamp(blockPeriod)
+	return fakeGenesisConfigWithFlags(ctx, genesisCfg)
+}
+
+func devnet(ctx *cli.Context) error {
+	if args := ctx.Args(); len(args) > 0 {
+		return fmt.Errorf("invalid command: %q", args[0])
+	}
+	num := idx.Validator(ctx.Int(DevnetValidatorsFlag.Name))
+	if num == 0 {
+		return fmt.Errorf("--%s should be positive", DevnetValidatorsFlag.Name)
+	}
+	blockPeriod := ctx.Duration(DevnetBlockPeriodFlag.Name)
+	if blockPeriod <= 0 {
+		return fmt.Errorf("--%s should be positive", DevnetBlockPeriodFlag.Name)
+	}
+	genesisCfg, err := devnetGenesisConfig(ctx, num, blockPeriod)
+	if err != nil {
+		return err
+	}
+	genStore, err := makeFakeGenesisStore(genesisCfg)
+	if err != nil {
+		return err
+	}
+
+	datadir := ctx.GlobalString(DataDirFlag.Name)
+	if !ctx.GlobalIsSet(DataDirFlag.Name) {
+		datadir, err = ioutil.TempDir("", "galaxy-devnet")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(datadir)
+	}
+
+	base, err := mayMakeAllConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	stacks := make([]*node.Node, 0, num)
+	for i := idx.Validator(0); i < num; i++ {
+		id := idx.ValidatorID(i + 1)
+		cfg := devnetNodeConfig(*base, path.Join(datadir, fmt.Sprintf("node%d", id)), id, num, blockPeriod, ctx.Int(DevnetHTTPPortFlag.Name)+int(i))
+		stack, _, nodeClose := makeNodeWithKey(ctx, cfg, memInputGenesis(genStore), makegenesis.FakeKey(id), []string{validatorpk.FakePassword})
+		defer nodeClose()
+		if err := stack.Start(); err != nil {
+			return fmt.Errorf("failed to start node %d: %v", id, err)
+		}
+		stacks = append(stacks, stack)
+		log.Info("Started devnet node", "validator", id, "http", stack.HTTPEndpoint(), "enode", stack.Server().Self().URLv4())
+	}
+
+	// connect all the nodes to each other
+	for i, a := range stacks {
+		for _, b := range stacks[i+1:] {
+			a.Server().AddPeer(b.Server().Self())
+		}
+	}
+	for _, acc := range genesisCfg.Accounts {
+		log.Info("Prefunded account", "address", acc.Address, "balance", acc.Balance)
+	}
+	for _, val := range makegenesis.GetFakeValidators(num) {
+		log.Info("Validator account", "id", val.ID, "address", val.Address, "key", common.Bytes2Hex(crypto.FromECDSA(makegenesis.FakeKey(val.ID))))
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down devnet...")
+	return nil
+}
@@ -0,0 +1,75 @@
+package launcher
+
+import (
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/inter"
+	futils "go-galaxy/utils"
+)
+
+func devnetTestContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("devnet", flag.ContinueOnError)
+	for _, f := range devnetCommand.Flags {
+		f.Apply(set)
+	}
+	require.NoError(t, set.Parse(args))
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestMakeDevnetGenesis(t *testing.T) {
+	require := require.New(t)
+
+	addr := common.HexToAddress("0x239fA7623354eC26520dE878B52f13Fe84b06971")
+	ctx := devnetTestContext(t,
+		"--fakenet.accounts", addr.Hex()+":1000",
+		"--fakenet.rules", `{"Dag":{"MaxParents":5}}`)
+	genesisCfg, err := devnetGenesisConfig(ctx, 2, 2*time.Second)
+	require.NoError(err)
+	genStore, err := makeFakeGenesisStore(genesisCfg)
+	require.NoError(err)
+
+	rules := genStore.GetRules()
+	require.Equal(inter.Timestamp(2*time.Second), rules.Blocks.MaxEmptyBlockSkipPeriod)
+	require.Equal(idx.Event(5), rules.Dag.MaxParents)
+	require.Equal(futils.ToUnit(1000), genStore.GetEvmAccount(addr).Balance)
+	// 2 validators and the prefunded account
+	supply := new(big.Int).Add(futils.ToUnit(2*1000000000), futils.ToUnit(1000))
+	require.Equal(supply, genStore.GetMetadata().TotalSupply)
+
+	// the block period may be overridden by the rules
+	ctx = devnetTestContext(t, "--fakenet.rules", `{"Blocks":{"MaxEmptyBlockSkipPeriod":3000000000}}`)
+	genesisCfg, err = devnetGenesisConfig(ctx, 2, 2*time.Second)
+	require.NoError(err)
+	genStore, err = makeFakeGenesisStore(genesisCfg)
+	require.NoError(err)
+	require.Equal(inter.Timestamp(3*time.Second), genStore.GetRules().Blocks.MaxEmptyBlockSkipPeriod)
+
+	// the accounts may be listed in a file, one per line
+	accountsFile := filepath.Join(t.TempDir(), "accounts")
+	require.NoError(ioutil.WriteFile(accountsFile, []byte(addr.Hex()+":1000\n"+addr.Hex()+":1\n"), 0600))
+	ctx = devnetTestContext(t, "--fakenet.accounts", accountsFile)
+	genesisCfg, err = devnetGenesisConfig(ctx, 2, time.Second)
+	require.NoError(err)
+	require.Len(genesisCfg.Accounts, 2)
+	require.Equal(futils.ToUnit(1), genesisCfg.Accounts[1].Balance)
+
+	ctx = devnetTestContext(t, "--fakenet.rules", `{"Dag":`)
+	genesisCfg, err = devnetGenesisConfig(ctx, 2, time.Second)
+	require.NoError(err)
+	_, err = makeFakeGenesisStore(genesisCfg)
+	require.Error(err)
+
+	ctx = devnetTestContext(t, "--fakenet.accounts", "0x01:1")
+	_, err = devnetGenesisConfig(ctx, 2, time.Second)
+	require.Error(err)
+}
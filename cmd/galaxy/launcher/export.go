@@ -21,14 +21,35 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"go-galaxy/gossip"
+	"go-galaxy/gossip/evmstore"
 	"go-galaxy/integration"
+	"go-galaxy/inter"
+	"go-galaxy/inter/ier"
 )
 
 var (
 	eventsFileHeader  = hexutils.HexToBytes("7e995678")
 	eventsFileVersion = hexutils.HexToBytes("00010001")
+	blocksFileHeader  = hexutils.HexToBytes("7e995679")
+	blocksFileVersion = hexutils.HexToBytes("00010001")
+	evmFileHeader     = hexutils.HexToBytes("7e99567a")
+	evmFileVersion    = hexutils.HexToBytes("00010001")
 )
 
+const (
+	blockRecordItem uint8 = 1
+	epochRecordItem uint8 = 2
+	blockVotesItem  uint8 = 3
+	epochVoteItem   uint8 = 4
+)
+
+// recordsFileItem is an item of the blocks file, which is either an ibr.LlrIdxFullBlockRecord,
+// an ier.LlrIdxFullEpochRecord, an inter.LlrSignedBlockVotes or an inter.LlrSignedEpochVote
+type recordsFileItem struct {
+	Kind   uint8
+	Record rlp.RawValue
+}
+
 // statsReportLimit is the time limit during import and export after which we
 // always print out progress. This avoids the user wondering what's going on.
 const statsReportLimit = 8 * time.Second
@@ -93,6 +114,242 @@ func exportEvents(ctx *cli.Context) error {
 	return nil
 }
 
+func exportBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cfg.cachescale)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	from := idx.Block(1)
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return err
+		}
+		from = idx.Block(n)
+	}
+	to := gdb.GetLatestBlockIndex()
+	if len(ctx.Args()) > 2 {
+		n, err := strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		if err != nil {
+			return err
+		}
+		to = idx.Block(n)
+	}
+
+	log.Info("Exporting block records to file", "file", fn, "from", from, "to", to)
+	// Write header and version
+	_, err = writer.Write(append(blocksFileHeader, blocksFileVersion...))
+	if err != nil {
+		return err
+	}
+	err = exportBlocksTo(writer, gdb, from, to)
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+
+	return nil
+}
+
+// exportBlocksTo writes block records of the blocks range and epoch records of the epochs
+// which the blocks belong to, along with the LLR votes for them.
+// Items are ordered so that a fresh node is able to decide every record by the votes preceding it:
+// epoch votes and the record of epoch N+1 go before the block votes and block records of epoch N,
+// as the block votes may be signed by validators of the next epoch.
+func exportBlocksTo(w io.Writer, gdb *gossip.Store, from, to idx.Block) (err error) {
+	start, reported := time.Now(), time.Time{}
+
+	var (
+		blocks int
+		epochs int
+		votes  int
+		last   idx.Block
+	)
+	writeItem := func(kind uint8, record rlp.RawValue) error {
+		return rlp.Encode(w, &recordsFileItem{
+			Kind:   kind,
+			Record: record,
+		})
+	}
+	// writeEpoch writes epoch votes and epoch record of the epoch, returns the record if it's known
+	writeEpoch := func(epoch idx.Epoch) (*ier.LlrFullEpochRecord, error) {
+		gdb.ForEachEpochVoteRLP(epoch, func(ev rlp.RawValue) bool {
+			err = writeItem(epochVoteItem, ev)
+			votes++
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		er := gdb.GetFullEpochRecord(epoch)
+		if er == nil {
+			return nil, nil
+		}
+		encoded, err := rlp.EncodeToBytes(&ier.LlrIdxFullEpochRecord{
+			LlrFullEpochRecord: *er,
+			Idx:                epoch,
+		})
+		if err != nil {
+			return nil, err
+		}
+		epochs++
+		return er, writeItem(epochRecordItem, encoded)
+	}
+	// writeBlockVotes writes block votes of the epoch which overlap with the blocks range
+	writeBlockVotes := func(epoch idx.Epoch, from, to idx.Block) error {
+		gdb.IterateOverlappingBlockVotesRLP(append(epoch.Bytes(), from.Bytes()...), func(key []byte, raw rlp.RawValue) bool {
+			if idx.BytesToEpoch(key[:4]) != epoch {
+				return false
+			}
+			var bvs inter.LlrSignedBlockVotes
+			err = rlp.DecodeBytes(raw, &bvs)
+			if err != nil {
+				return false
+			}
+			if bvs.Val.Start > to {
+				return true
+			}
+			err = writeItem(blockVotesItem, raw)
+			votes++
+			return err == nil
+		})
+		return err
+	}
+
+	firstEpoch := gdb.FindBlockEpoch(from)
+	lastEpoch := gdb.FindBlockEpoch(to)
+	if _, err = writeEpoch(firstEpoch); err != nil {
+		return err
+	}
+	for epoch := firstEpoch; epoch <= lastEpoch; epoch++ {
+		var next *ier.LlrFullEpochRecord
+		next, err = writeEpoch(epoch + 1)
+		if err != nil {
+			return err
+		}
+		// epoch N+1 is sealed by the last block of epoch N
+		end := to
+		if next != nil && next.BlockState.LastBlock.Idx < end {
+			end = next.BlockState.LastBlock.Idx
+		}
+		if end < from {
+			continue
+		}
+		err = writeBlockVotes(epoch, from, end)
+		if err != nil {
+			return err
+		}
+		gdb.IterateFullBlockRecordsRLP(from, func(n idx.Block, br rlp.RawValue) bool {
+			if n > end {
+				return false
+			}
+			err = writeItem(blockRecordItem, br)
+			if err != nil {
+				return false
+			}
+			blocks++
+			last = n
+			if blocks%100 == 1 && time.Since(reported) >= statsReportLimit {
+				log.Info("Exporting block records", "last", last, "blocks", blocks, "epochs", epochs, "votes", votes, "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		from = end + 1
+	}
+	log.Info("Exported block records", "last", last, "blocks", blocks, "epochs", epochs, "votes", votes, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func exportEvm(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cfg.cachescale)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	root := common.Hash(gdb.GetBlockState().FinalizedStateRoot)
+	if len(ctx.Args()) > 1 {
+		root, err = parseRoot(ctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+	}
+
+	evmStore := gdb.EvmStore()
+	err = evmStore.GenerateEvmSnapshot(root, false, false)
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "err", err)
+		return err
+	}
+
+	// Open the file handle, the file is always compressed
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	log.Info("Exporting EVM state to file", "file", fn, "root", root)
+	// Write header and version
+	_, err = fh.Write(append(evmFileHeader, evmFileVersion...))
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(fh)
+	defer writer.Close()
+
+	start, reported := time.Now(), time.Time{}
+	stats, err := evmStore.ExportEvmFlat(writer, root, func(stats evmstore.FlatEvmStats) {
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Exporting EVM state", "accounts", stats.Accounts, "slots", stats.Slots, "codes", stats.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	})
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	log.Info("Exported EVM state", "root", root, "accounts", stats.Accounts, "slots", stats.Slots, "codes", stats.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
 func checkStateInitialized(rawProducer kvdb.IterableDBProducer) error {
 	names := rawProducer.Names()
 	if len(names) == 0 {
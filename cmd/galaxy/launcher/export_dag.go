@@ -0,0 +1,274 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/kvdb"
+	"github.com/deamchain/deam-v2-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/gossip"
+	"go-galaxy/integration"
+	"go-galaxy/inter"
+)
+
+var (
+	DagFormatFlag = cli.StringFlag{
+		Name:  "format",
+		Usage: `Output format of the events DAG ("dot" or "json")`,
+		Value: "dot",
+	}
+	DagValidatorsFlag = cli.StringFlag{
+		Name:  "validators",
+		Usage: "Comma separated list of validator IDs to export events of (all validators if empty)",
+	}
+)
+
+// dagNode is an event of the exported DAG.
+type dagNode struct {
+	ID           string          `json:"id"`
+	Epoch        idx.Epoch       `json:"epoch"`
+	Creator      idx.ValidatorID `json:"creator"`
+	Seq          idx.Event       `json:"seq"`
+	Lamport      idx.Lamport     `json:"lamport"`
+	Frame        idx.Frame       `json:"frame"`
+	CreationTime inter.Timestamp `json:"creationTime"`
+	MedianTime   inter.Timestamp `json:"medianTime"`
+	Root         bool            `json:"root"`
+	AtroposOf    *idx.Block      `json:"atroposOf,omitempty"`
+}
+
+// dagEdge is a parent reference of the exported DAG.
+type dagEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	SelfParent bool   `json:"selfParent"`
+}
+
+type dagGraph struct {
+	Nodes []dagNode `json:"nodes"`
+	Edges []dagEdge `json:"edges"`
+}
+
+func exportDag(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	from, to, err := parseEpochRange(ctx.Args())
+	if err != nil {
+		return err
+	}
+	validators, err := parseValidatorIDs(ctx.String(DagValidatorsFlag.Name))
+	if err != nil {
+		return err
+	}
+	format := ctx.String(DagFormatFlag.Name)
+	if format != "dot" && format != "json" {
+		utils.Fatalf("--%s must be either 'dot' or 'json'", DagFormatFlag.Name)
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cfg.cachescale)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	graph := collectDag(gdb, rawProducer, from, to, validators)
+	log.Info("Exporting events DAG", "from", from, "to", to, "events", len(graph.Nodes), "format", format)
+
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+	if format == "json" {
+		return writeDagJSON(writer, graph)
+	}
+	return writeDagDot(writer, graph)
+}
+
+func parseEpochRange(args cli.Args) (from, to idx.Epoch, err error) {
+	n, err := strconv.ParseUint(args.Get(0), 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	from = idx.Epoch(n)
+	to = from
+	if len(args) > 1 {
+		n, err = strconv.ParseUint(args.Get(1), 10, 32)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = idx.Epoch(n)
+	}
+	if to < from {
+		return 0, 0, fmt.Errorf("epochTo %d is lower than epochFrom %d", to, from)
+	}
+	return from, to, nil
+}
+
+func parseValidatorIDs(s string) (map[idx.ValidatorID]bool, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	ids := make(map[idx.ValidatorID]bool)
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator ID %q: %v", part, err)
+		}
+		ids[idx.ValidatorID(n)] = true
+	}
+	return ids, nil
+}
+
+// collectDag gathers events of the epochs range, their parents and consensus markers.
+// Roots are taken from the lachesis store if epoch DB still exists, and are derived from frames otherwise.
+// Atropos markers are taken from the blocks index.
+func collectDag(gdb *gossip.Store, rawProducer kvdb.IterableDBProducer, from, to idx.Epoch, validators map[idx.ValidatorID]bool) *dagGraph {
+	graph := &dagGraph{
+		Nodes: []dagNode{},
+		Edges: []dagEdge{},
+	}
+	included := make(map[hash.Event]bool)
+	frames := make(map[hash.Event]idx.Frame)
+
+	for epoch := from; epoch <= to; epoch++ {
+		roots := readLachesisRoots(rawProducer, epoch)
+		gdb.ForEachEpochEvent(epoch, func(e *inter.EventPayload) bool {
+			frames[e.ID()] = e.Frame()
+			if validators != nil && !validators[e.Creator()] {
+				return true
+			}
+			node := dagNode{
+				ID:           e.ID().Hex(),
+				Epoch:        e.Epoch(),
+				Creator:      e.Creator(),
+				Seq:          e.Seq(),
+				Lamport:      e.Lamport(),
+				Frame:        e.Frame(),
+				CreationTime: e.CreationTime(),
+				MedianTime:   e.MedianTime(),
+				AtroposOf:    gdb.GetBlockIndex(e.ID()),
+			}
+			if roots != nil {
+				node.Root = roots[e.ID()]
+			} else if sp := e.SelfParent(); sp == nil {
+				node.Root = true
+			} else {
+				node.Root = e.Frame() > frames[*sp]
+			}
+			graph.Nodes = append(graph.Nodes, node)
+			included[e.ID()] = true
+
+			for _, p := range e.Parents() {
+				if !included[p] {
+					continue
+				}
+				graph.Edges = append(graph.Edges, dagEdge{
+					From:       e.ID().Hex(),
+					To:         p.Hex(),
+					SelfParent: e.IsSelfParent(p),
+				})
+			}
+			return true
+		})
+	}
+	return graph
+}
+
+// readLachesisRoots returns roots of the epoch from the lachesis epoch DB, or nil if it doesn't exist.
+func readLachesisRoots(rawProducer kvdb.IterableDBProducer, epoch idx.Epoch) map[hash.Event]bool {
+	name := fmt.Sprintf("lachesis-%d", epoch)
+	exists := false
+	for _, n := range rawProducer.Names() {
+		if n == name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return nil
+	}
+	db, err := rawProducer.OpenDB(name)
+	if err != nil {
+		log.Warn("Failed to open lachesis epoch DB", "epoch", epoch, "err", err)
+		return nil
+	}
+	defer db.Close()
+
+	// see abft.Store, roots table key is frame|validatorID|eventID
+	const rootKeySize = 4 + 4 + 32
+	roots := make(map[hash.Event]bool)
+	it := table.New(db, []byte("r")).NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) != rootKeySize {
+			continue
+		}
+		roots[hash.BytesToEvent(it.Key()[rootKeySize-32:])] = true
+	}
+	return roots
+}
+
+func writeDagJSON(w io.Writer, graph *dagGraph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(graph)
+}
+
+func writeDagDot(w io.Writer, graph *dagGraph) error {
+	byCreator := make(map[idx.ValidatorID][]dagNode)
+	creators := make([]idx.ValidatorID, 0)
+	for _, n := range graph.Nodes {
+		if _, ok := byCreator[n.Creator]; !ok {
+			creators = append(creators, n.Creator)
+		}
+		byCreator[n.Creator] = append(byCreator[n.Creator], n)
+	}
+
+	var err error
+	printf := func(format string, a ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+	printf("digraph DAG {\n")
+	printf("\trankdir=BT;\n")
+	printf("\tnode [shape=ellipse, fontsize=10];\n")
+	for _, creator := range creators {
+		printf("\tsubgraph cluster_%d {\n", creator)
+		printf("\t\tlabel=\"validator %d\";\n", creator)
+		for _, n := range byCreator[creator] {
+			attrs := fmt.Sprintf("label=\"%d-%d\\nframe=%d lamport=%d\\nmedian=%d\"", n.Creator, n.Seq, n.Frame, n.Lamport, n.MedianTime)
+			if n.Root {
+				attrs += ", shape=box"
+			}
+			if n.AtroposOf != nil {
+				attrs += fmt.Sprintf(", style=filled, fillcolor=gold, xlabel=\"atropos of block %d\"", *n.AtroposOf)
+			}
+			printf("\t\t\"%s\" [%s];\n", n.ID, attrs)
+		}
+		printf("\t}\n")
+	}
+	for _, e := range graph.Edges {
+		if e.SelfParent {
+			printf("\t\"%s\" -> \"%s\" [weight=10];\n", e.From, e.To)
+		} else {
+			printf("\t\"%s\" -> \"%s\" [style=dashed, color=gray];\n", e.From, e.To)
+		}
+	}
+	printf("}\n")
+	return err
+}
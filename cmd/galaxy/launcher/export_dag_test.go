@@ -0,0 +1,73 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDagWriters(t *testing.T) {
+	require := require.New(t)
+
+	block := idx.Block(5)
+	graph := &dagGraph{
+		Nodes: []dagNode{
+			{ID: "0xa", Epoch: 1, Creator: 1, Seq: 1, Lamport: 1, Frame: 1, Root: true},
+			{ID: "0xb", Epoch: 1, Creator: 2, Seq: 1, Lamport: 1, Frame: 1, Root: true},
+			{ID: "0xc", Epoch: 1, Creator: 1, Seq: 2, Lamport: 2, Frame: 1, AtroposOf: &block},
+		},
+		Edges: []dagEdge{
+			{From: "0xc", To: "0xa", SelfParent: true},
+			{From: "0xc", To: "0xb"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(writeDagJSON(buf, graph))
+	decoded := &dagGraph{}
+	require.NoError(json.Unmarshal(buf.Bytes(), decoded))
+	require.Equal(graph, decoded)
+
+	buf.Reset()
+	require.NoError(writeDagDot(buf, graph))
+	dot := buf.String()
+	require.True(strings.HasPrefix(dot, "digraph DAG {"))
+	require.Contains(dot, "subgraph cluster_1")
+	require.Contains(dot, "subgraph cluster_2")
+	require.Contains(dot, `"0xc" -> "0xa" [weight=10];`)
+	require.Contains(dot, `"0xc" -> "0xb" [style=dashed, color=gray];`)
+	require.Contains(dot, "atropos of block 5")
+	require.Equal(2, strings.Count(dot, "shape=box"))
+}
+
+func TestExportDagArgs(t *testing.T) {
+	require := require.New(t)
+
+	from, to, err := parseEpochRange([]string{"3"})
+	require.NoError(err)
+	require.Equal(idx.Epoch(3), from)
+	require.Equal(idx.Epoch(3), to)
+
+	from, to, err = parseEpochRange([]string{"3", "5"})
+	require.NoError(err)
+	require.Equal(idx.Epoch(3), from)
+	require.Equal(idx.Epoch(5), to)
+
+	_, _, err = parseEpochRange([]string{"5", "3"})
+	require.Error(err)
+
+	ids, err := parseValidatorIDs("")
+	require.NoError(err)
+	require.Nil(ids)
+
+	ids, err = parseValidatorIDs("1, 3")
+	require.NoError(err)
+	require.Equal(map[idx.ValidatorID]bool{1: true, 3: true}, ids)
+
+	_, err = parseValidatorIDs("1,x")
+	require.Error(err)
+}
@@ -3,13 +3,21 @@ package launcher
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
 	cli "gopkg.in/urfave/cli.v1"
 
+	"go-galaxy/galaxy"
+	"go-galaxy/galaxy/genesis"
+	"go-galaxy/galaxy/genesisstore"
 	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	futils "go-galaxy/utils"
 )
 
 // FakeNetFlag enables special testnet, where validators are automatically created
@@ -18,6 +26,184 @@ var FakeNetFlag = cli.StringFlag{
 	Usage: "'n/N' - sets coinbase as fake n-th key from genesis of N validators.",
 }
 
+var (
+	FakeNetAccountsFlag = cli.StringFlag{
+		Name:  "fakenet.accounts",
+		Usage: "Comma-separated list (or path to file with one item per line) of prefunded accounts of fakenet genesis in <address>:<balance> format, balance is in whole tokens, e.g. '0x239fA7623354eC26520dE878B52f13Fe84b06971:1000'",
+	}
+	FakeNetRulesFlag = cli.StringFlag{
+		Name:  "fakenet.rules",
+		Usage: "JSON (or path to JSON file) with fakenet rules to override, e.g. '{\"Epochs\":{\"MaxEpochGas\":1500000000}}'",
+	}
+	FakeNetStakesFlag = cli.StringFlag{
+		Name:  "fakenet.stakes",
+		Usage: "Comma-separated list of fakenet validators stakes in whole tokens, one per validator",
+	}
+	FakeNetEpochFlag = cli.UintFlag{
+		Name:  "fakenet.epoch",
+		Usage: "First epoch of fakenet genesis",
+		Value: 2,
+	}
+	FakeNetTimeFlag = cli.Int64Flag{
+		Name:  "fakenet.time",
+		Usage: "Time of fakenet genesis in unix seconds",
+		Value: makegenesis.FakeGenesisTime.Unix(),
+	}
+)
+
+// fakeAccount is a prefunded account of fakenet genesis
+type fakeAccount struct {
+	Address common.Address
+	Balance *big.Int
+}
+
+// fakeGenesisConfig is the customization of fakenet genesis
+type fakeGenesisConfig struct {
+	FirstEpoch idx.Epoch
+	Time       inter.Timestamp
+	Balance    *big.Int
+	Stakes     []*big.Int
+	Accounts   []fakeAccount
+	Rules      galaxy.Rules
+	RulesDiffs [][]byte
+}
+
+func defaultFakeGenesisConfig(num idx.Validator) fakeGenesisConfig {
+	stakes := make([]*big.Int, num)
+	for i := range stakes {
+		stakes[i] = futils.ToUnit(5000000)
+	}
+	return fakeGenesisConfig{
+		FirstEpoch: 2,
+		Time:       makegenesis.FakeGenesisTime,
+		Balance:    futils.ToUnit(1000000000),
+		Stakes:     stakes,
+		Rules:      galaxy.FakeNetRules(),
+	}
+}
+
+// fakeGenesisConfigWithFlags applies the --fakenet.* flags
+func fakeGenesisConfigWithFlags(ctx *cli.Context, cfg fakeGenesisConfig) (fakeGenesisConfig, error) {
+	if ctx.GlobalIsSet(FakeNetEpochFlag.Name) {
+		cfg.FirstEpoch = idx.Epoch(ctx.GlobalUint(FakeNetEpochFlag.Name))
+		if cfg.FirstEpoch == 0 {
+			return cfg, fmt.Errorf("--%s should be positive", FakeNetEpochFlag.Name)
+		}
+	}
+	if ctx.GlobalIsSet(FakeNetTimeFlag.Name) {
+		cfg.Time = inter.FromUnix(ctx.GlobalInt64(FakeNetTimeFlag.Name))
+	}
+	if ctx.GlobalIsSet(FakeNetStakesFlag.Name) {
+		stakes, err := parseTokens(strings.Split(ctx.GlobalString(FakeNetStakesFlag.Name), ","))
+		if err != nil {
+			return cfg, fmt.Errorf("invalid --%s: %v", FakeNetStakesFlag.Name, err)
+		}
+		if len(stakes) != len(cfg.Stakes) {
+			return cfg, fmt.Errorf("--%s should contain %d stakes, one per validator", FakeNetStakesFlag.Name, len(cfg.Stakes))
+		}
+		cfg.Stakes = stakes
+	}
+	if list := strings.TrimSpace(ctx.GlobalString(FakeNetAccountsFlag.Name)); list != "" {
+		if !strings.HasPrefix(list, "0x") {
+			data, err := ioutil.ReadFile(list)
+			if err != nil {
+				return cfg, err
+			}
+			list = strings.ReplaceAll(string(data), "\n", ",")
+		}
+		accounts, err := parseFakeAccounts(list)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid --%s: %v", FakeNetAccountsFlag.Name, err)
+		}
+		cfg.Accounts = append(cfg.Accounts, accounts...)
+	}
+	if rules := strings.TrimSpace(ctx.GlobalString(FakeNetRulesFlag.Name)); rules != "" {
+		diff := []byte(rules)
+		if !strings.HasPrefix(rules, "{") {
+			var err error
+			diff, err = ioutil.ReadFile(rules)
+			if err != nil {
+				return cfg, err
+			}
+		}
+		cfg.RulesDiffs = append(cfg.RulesDiffs, diff)
+	}
+	return cfg, nil
+}
+
+// makeFakeGenesisStore makes fakenet genesis
+func makeFakeGenesisStore(cfg fakeGenesisConfig) (*genesisstore.Store, error) {
+	for _, stake := range cfg.Stakes {
+		if stake.Sign() <= 0 {
+			return nil, fmt.Errorf("validator stake should be positive")
+		}
+	}
+	genStore := makegenesis.FakeGenesisStoreWithStakes(cfg.FirstEpoch, cfg.Time, cfg.Balance, cfg.Stakes)
+
+	rules := cfg.Rules
+	for _, diff := range cfg.RulesDiffs {
+		var err error
+		rules, err = galaxy.UpdateRules(rules, diff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules: %v", err)
+		}
+	}
+	genStore.SetRules(rules)
+
+	metadata := genStore.GetMetadata()
+	for _, acc := range cfg.Accounts {
+		prev := genStore.GetEvmAccount(acc.Address)
+		if prev.Balance != nil {
+			metadata.TotalSupply.Sub(metadata.TotalSupply, prev.Balance)
+		}
+		genStore.SetEvmAccount(acc.Address, genesis.Account{
+			Code:    prev.Code,
+			Balance: acc.Balance,
+			Nonce:   prev.Nonce,
+		})
+		metadata.TotalSupply.Add(metadata.TotalSupply, acc.Balance)
+	}
+	genStore.SetMetadata(metadata)
+	return genStore, nil
+}
+
+// parseFakeAccounts parses comma-separated list of <address>:<balance> pairs, balance is in whole tokens
+func parseFakeAccounts(s string) ([]fakeAccount, error) {
+	var accounts []fakeAccount
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+			return nil, fmt.Errorf("invalid account %q, use <address>:<balance> format", item)
+		}
+		balance, err := parseTokens(parts[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance of account %s: %v", parts[0], err)
+		}
+		accounts = append(accounts, fakeAccount{
+			Address: common.HexToAddress(parts[0]),
+			Balance: balance[0],
+		})
+	}
+	return accounts, nil
+}
+
+// parseTokens parses amounts in whole tokens
+func parseTokens(ss []string) ([]*big.Int, error) {
+	res := make([]*big.Int, len(ss))
+	for i, s := range ss {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+		if !ok || v.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount %q", s)
+		}
+		res[i] = v.Mul(v, futils.ToUnit(1))
+	}
+	return res, nil
+}
+
 func getFakeValidatorKey(ctx *cli.Context) *ecdsa.PrivateKey {
 	id, _, err := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 	if err != nil || id == 0 {
@@ -1,17 +1,23 @@
 package launcher
 
 import (
+	"math/big"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
 
 	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
 	"go-galaxy/inter/validatorpk"
+	futils "go-galaxy/utils"
 )
 
 func TestFakeNetFlag_NonValidator(t *testing.T) {
@@ -116,3 +122,53 @@ func readFakeValidator(fakenet string) *validatorpk.PubKey {
 		Type: validatorpk.Types.Secp256k1,
 	}
 }
+
+func TestParseFakeAccounts(t *testing.T) {
+	require := require.New(t)
+
+	accounts, err := parseFakeAccounts("")
+	require.NoError(err)
+	require.Empty(accounts)
+
+	accounts, err = parseFakeAccounts("0x239fA7623354eC26520dE878B52f13Fe84b06971:1000, 0x0000000000000000000000000000000000000001:0")
+	require.NoError(err)
+	require.Equal([]fakeAccount{
+		{common.HexToAddress("0x239fA7623354eC26520dE878B52f13Fe84b06971"), futils.ToUnit(1000)},
+		{common.HexToAddress("0x0000000000000000000000000000000000000001"), new(big.Int)},
+	}, accounts)
+
+	for _, s := range []string{"0x239fA7623354eC26520dE878B52f13Fe84b06971", "0x01:1", "0x239fA7623354eC26520dE878B52f13Fe84b06971:1e3", "0x239fA7623354eC26520dE878B52f13Fe84b06971:-1"} {
+		_, err = parseFakeAccounts(s)
+		require.Error(err, s)
+	}
+}
+
+func TestMakeFakeGenesisStore(t *testing.T) {
+	require := require.New(t)
+
+	addr := common.HexToAddress("0x239fA7623354eC26520dE878B52f13Fe84b06971")
+	cfg := defaultFakeGenesisConfig(2)
+	cfg.FirstEpoch = 5
+	cfg.Time = inter.FromUnix(1700000000)
+	cfg.Stakes = []*big.Int{futils.ToUnit(1000000), futils.ToUnit(3000000)}
+	cfg.Accounts = []fakeAccount{{addr, futils.ToUnit(1000)}}
+	cfg.RulesDiffs = [][]byte{[]byte(`{"Dag":{"MaxParents":5}}`)}
+	genStore, err := makeFakeGenesisStore(cfg)
+	require.NoError(err)
+
+	require.Equal(idx.Event(5), genStore.GetRules().Dag.MaxParents)
+	require.Equal(futils.ToUnit(1000), genStore.GetEvmAccount(addr).Balance)
+	metadata := genStore.GetMetadata()
+	require.Equal(idx.Epoch(5), metadata.FirstEpoch)
+	require.Equal(inter.FromUnix(1700000000), metadata.Time)
+	// 2 validators and the prefunded account
+	supply := new(big.Int).Add(futils.ToUnit(2*1000000000), futils.ToUnit(1000))
+	require.Equal(supply, metadata.TotalSupply)
+	for i, val := range metadata.Validators {
+		require.Equal(cfg.Stakes[i], genStore.GetDelegation(val.Address, val.ID).Stake)
+	}
+
+	cfg.RulesDiffs = [][]byte{[]byte(`{"Dag":`)}
+	_, err = makeFakeGenesisStore(cfg)
+	require.Error(err)
+}
@@ -23,10 +23,14 @@ import (
 	"github.com/status-im/keycard-go/hexutils"
 	"gopkg.in/urfave/cli.v1"
 
+	"go-galaxy/eventcheck"
 	"go-galaxy/gossip"
 	"go-galaxy/gossip/emitter"
+	"go-galaxy/gossip/evmstore"
 	"go-galaxy/integration"
 	"go-galaxy/inter"
+	"go-galaxy/inter/ibr"
+	"go-galaxy/inter/ier"
 	"go-galaxy/utils/ioread"
 )
 
@@ -64,7 +68,20 @@ func importEvmFile(fn string, gdb *gossip.Store) error {
 	}
 	defer fh.Close()
 
-	var reader io.Reader = fh
+	// Files produced by export evm are detected by the header
+	headerAndVersion := make([]byte, len(evmFileHeader)+len(evmFileVersion))
+	n, err := io.ReadFull(fh, headerAndVersion)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	if n == len(headerAndVersion) && bytes.Equal(headerAndVersion[:len(evmFileHeader)], evmFileHeader) {
+		if err := checkEvmFileHeader(bytes.NewReader(headerAndVersion)); err != nil {
+			return err
+		}
+		return importEvmFlatFile(fh, gdb)
+	}
+
+	var reader io.Reader = io.MultiReader(bytes.NewReader(headerAndVersion[:n]), fh)
 	if strings.HasSuffix(fn, ".gz") {
 		if reader, err = gzip.NewReader(reader); err != nil {
 			return err
@@ -75,13 +92,46 @@ func importEvmFile(fn string, gdb *gossip.Store) error {
 	return gdb.EvmStore().ImportEvm(reader)
 }
 
+// importEvmFlatFile regenerates EVM state from a file produced by export evm
+func importEvmFlatFile(fh io.Reader, gdb *gossip.Store) error {
+	reader, err := gzip.NewReader(fh)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	start, reported := time.Now(), time.Time{}
+	root, stats, err := gdb.EvmStore().ImportEvmFlat(reader, func(stats evmstore.FlatEvmStats) {
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing EVM state", "accounts", stats.Accounts, "slots", stats.Slots, "codes", stats.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("EVM state is regenerated and verified", "root", root, "accounts", stats.Accounts, "slots", stats.Slots, "codes", stats.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 func importEvents(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
 	}
 
-	// avoid P2P interaction, API calls and events emitting
 	genesis := getGalaxyGenesis(ctx)
+	cfg := makeImportConfig(ctx)
+
+	err := importEventsToNode(ctx, cfg, genesis, ctx.Args()...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// makeImportConfig returns a node config which avoids P2P interaction, API calls and events emitting
+func makeImportConfig(ctx *cli.Context) *config {
 	cfg := makeAllConfigs(ctx)
 	cfg.Galaxy.Protocol.EventsSemaphoreLimit.Size = math.MaxUint32
 	cfg.Galaxy.Protocol.EventsSemaphoreLimit.Num = math.MaxUint32
@@ -98,13 +148,7 @@ func importEvents(ctx *cli.Context) error {
 	cfg.Node.P2P.BootstrapNodesV5 = nil
 	cfg.Node.P2P.StaticNodes = nil
 	cfg.Node.P2P.TrustedNodes = nil
-
-	err := importEventsToNode(ctx, cfg, genesis, ctx.Args()...)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return cfg
 }
 
 func importEventsToNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis, args ...string) error {
@@ -123,18 +167,30 @@ func importEventsToNode(ctx *cli.Context, cfg *config, genesis integration.Input
 }
 
 func checkEventsFileHeader(reader io.Reader) error {
-	headerAndVersion := make([]byte, len(eventsFileHeader)+len(eventsFileVersion))
+	return checkFileHeader(reader, "events", eventsFileHeader, eventsFileVersion)
+}
+
+func checkBlocksFileHeader(reader io.Reader) error {
+	return checkFileHeader(reader, "blocks", blocksFileHeader, blocksFileVersion)
+}
+
+func checkEvmFileHeader(reader io.Reader) error {
+	return checkFileHeader(reader, "EVM", evmFileHeader, evmFileVersion)
+}
+
+func checkFileHeader(reader io.Reader, kind string, header, version []byte) error {
+	headerAndVersion := make([]byte, len(header)+len(version))
 	err := ioread.ReadAll(reader, headerAndVersion)
 	if err != nil {
 		return err
 	}
-	if bytes.Compare(headerAndVersion[:len(eventsFileHeader)], eventsFileHeader) != 0 {
-		return errors.New("expected an events file, mismatched file header")
+	if bytes.Compare(headerAndVersion[:len(header)], header) != 0 {
+		return errors.New(fmt.Sprintf("expected %s file, mismatched file header", kind))
 	}
-	if bytes.Compare(headerAndVersion[len(eventsFileHeader):], eventsFileVersion) != 0 {
-		got := hexutils.BytesToHex(headerAndVersion[len(eventsFileHeader):])
-		expected := hexutils.BytesToHex(eventsFileVersion)
-		return errors.New(fmt.Sprintf("wrong version of events file, got=%s, expected=%s", got, expected))
+	if bytes.Compare(headerAndVersion[len(header):], version) != 0 {
+		got := hexutils.BytesToHex(headerAndVersion[len(header):])
+		expected := hexutils.BytesToHex(version)
+		return errors.New(fmt.Sprintf("wrong version of %s file, got=%s, expected=%s", kind, got, expected))
 	}
 	return nil
 }
@@ -231,3 +287,144 @@ func importEventsFile(srv *gossip.Service, fn string) error {
 
 	return nil
 }
+
+func importBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	genesis := getGalaxyGenesis(ctx)
+	cfg := makeImportConfig(ctx)
+
+	node, svc, nodeClose := makeNode(ctx, cfg, genesis)
+	defer nodeClose()
+	startNode(ctx, node)
+
+	for _, fn := range ctx.Args() {
+		log.Info("Importing block records from file", "file", fn)
+		if err := importBlocksFile(svc, fn); err != nil {
+			log.Error("Import error", "file", fn, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// importBlocksFile applies LLR votes, block and epoch records of the file.
+// Votes are checked like the votes received from peers, and every record is verified against
+// the LLR voting result. Records which are already present are skipped, and the import fails
+// if some records aren't decided by the votes known to the node.
+func importBlocksFile(srv *gossip.Service, fn string) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+		defer reader.(*gzip.Reader).Close()
+	}
+
+	// Check file version and header
+	if err := checkBlocksFileHeader(reader); err != nil {
+		return err
+	}
+
+	stream := rlp.NewStream(reader, 0)
+
+	start, reported := time.Now(), time.Time{}
+	var (
+		blocks    int
+		epochs    int
+		votes     int
+		skipped   int
+		undecided int
+		last      idx.Block
+	)
+	for {
+		select {
+		case <-interrupt:
+			return fmt.Errorf("interrupted")
+		default:
+		}
+		item := new(recordsFileItem)
+		err = stream.Decode(item)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch item.Kind {
+		case blockRecordItem:
+			br := ibr.LlrIdxFullBlockRecord{}
+			if err := rlp.DecodeBytes(item.Record, &br); err != nil {
+				return err
+			}
+			err = srv.ProcessFullBlockRecord(br)
+			last = br.Idx
+		case epochRecordItem:
+			er := ier.LlrIdxFullEpochRecord{}
+			if err := rlp.DecodeBytes(item.Record, &er); err != nil {
+				return err
+			}
+			err = srv.ProcessFullEpochRecord(er)
+		case blockVotesItem:
+			bvs := inter.LlrSignedBlockVotes{}
+			if err := rlp.DecodeBytes(item.Record, &bvs); err != nil {
+				return err
+			}
+			err = srv.ImportBlockVotes(bvs)
+		case epochVoteItem:
+			ev := inter.LlrSignedEpochVote{}
+			if err := rlp.DecodeBytes(item.Record, &ev); err != nil {
+				return err
+			}
+			err = srv.ImportEpochVote(ev)
+		default:
+			return fmt.Errorf("unknown record kind %d", item.Kind)
+		}
+		switch err {
+		case nil:
+			switch item.Kind {
+			case blockRecordItem:
+				blocks++
+			case epochRecordItem:
+				epochs++
+			default:
+				votes++
+			}
+		case eventcheck.ErrAlreadyProcessedBR, eventcheck.ErrAlreadyProcessedER:
+			skipped++
+		case eventcheck.ErrAlreadyProcessedBVs, eventcheck.ErrAlreadyProcessedEV:
+		case eventcheck.ErrUnknownEpochBVs, eventcheck.ErrUnknownEpochEV:
+			// votes of epochs unknown to the node, the records they decide are reported as undecided
+			log.Debug("Skipped LLR votes of unknown epoch", "kind", item.Kind)
+		case eventcheck.ErrUndecidedBR, eventcheck.ErrUndecidedER:
+			undecided++
+		default:
+			return err
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing block records", "last", last, "blocks", blocks, "epochs", epochs, "votes", votes, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Block records import is finished", "file", fn, "last", last, "blocks", blocks, "epochs", epochs, "votes", votes, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(start)))
+	if undecided != 0 {
+		return fmt.Errorf("%d records aren't decided by LLR votes, the file must contain the votes and start at the history known to the node", undecided)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package launcher
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/abft"
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/kvdb/leveldb"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy/genesisstore"
+	"go-galaxy/gossip"
+	"go-galaxy/integration"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	"go-galaxy/inter/ibr"
+	"go-galaxy/inter/ier"
+	"go-galaxy/utils"
+	"go-galaxy/vecmt"
+)
+
+func TestCheckFileHeader(t *testing.T) {
+	require := require.New(t)
+
+	blocks := append(append([]byte{}, blocksFileHeader...), blocksFileVersion...)
+	events := append(append([]byte{}, eventsFileHeader...), eventsFileVersion...)
+
+	require.NoError(checkBlocksFileHeader(bytes.NewReader(blocks)))
+	require.NoError(checkEventsFileHeader(bytes.NewReader(events)))
+	require.NoError(checkEvmFileHeader(bytes.NewReader(append(append([]byte{}, evmFileHeader...), evmFileVersion...))))
+
+	require.EqualError(checkBlocksFileHeader(bytes.NewReader(events)), "expected blocks file, mismatched file header")
+	require.EqualError(checkEventsFileHeader(bytes.NewReader(blocks)), "expected events file, mismatched file header")
+
+	wrongVersion := append(append([]byte{}, blocksFileHeader...), 0, 2, 0, 1)
+	require.EqualError(checkBlocksFileHeader(bytes.NewReader(wrongVersion)), "wrong version of blocks file, got=00020001, expected=00010001")
+}
+
+const testFirstEpoch = idx.Epoch(2)
+
+// makeTestService starts a fresh non-validator node of the single validator fakenet.
+func makeTestService(t *testing.T) (*gossip.Service, *gossip.Store) {
+	genStore := makegenesis.FakeGenesisStore(testFirstEpoch, 1, utils.ToUnit(1000000000), utils.ToUnit(5000000))
+	genesis := integration.InputGenesis{
+		Hash: genStore.Hash(),
+		Read: func(store *genesisstore.Store) error {
+			buf := bytes.NewBuffer(nil)
+			err := genStore.Export(buf)
+			if err != nil {
+				return err
+			}
+			return store.Import(buf)
+		},
+		Close: func() error {
+			return nil
+		},
+	}
+	cfg := integration.Configs{
+		Galaxy:        gossip.DefaultConfig(cachescale.Identity),
+		GalaxyStore:   gossip.LiteStoreConfig(),
+		Lachesis:      abft.DefaultConfig(),
+		LachesisStore: abft.LiteStoreConfig(),
+		VectorClock:   vecmt.LiteConfig(),
+	}
+	engine, dagIndex, gdb, cdb, _, blockProc := integration.MakeEngine(leveldb.NewProducer(t.TempDir(), func(string) int {
+		return 16 * opt.MiB
+	}), genesis, cfg)
+	t.Cleanup(func() {
+		gdb.Close()
+		_ = cdb.Close()
+	})
+
+	stack, err := node.New(&node.Config{
+		P2P: p2p.Config{
+			NoDiscovery: true,
+			MaxPeers:    0,
+		},
+	})
+	require.NoError(t, err)
+
+	newTxPool := func(reader evmcore.StateReader) gossip.TxPool {
+		txPoolCfg := evmcore.DefaultTxPoolConfig
+		txPoolCfg.Journal = ""
+		return evmcore.NewTxPool(txPoolCfg, reader.Config(), reader)
+	}
+	svc, err := gossip.NewService(stack, cfg.Galaxy, gdb, blockProc, engine, dagIndex, newTxPool)
+	require.NoError(t, err)
+	require.NoError(t, engine.Bootstrap(svc.GetConsensusCallbacks()))
+	stack.RegisterLifecycle(svc)
+	require.NoError(t, stack.Start())
+	t.Cleanup(func() {
+		_ = stack.Close()
+	})
+
+	return svc, gdb
+}
+
+// signTestEvent signs the event by the fake validator.
+func signTestEvent(t *testing.T, e *inter.MutableEventPayload) {
+	e.SetVersion(1)
+	e.SetCreator(1)
+	e.SetPayloadHash(inter.CalcPayloadHash(e))
+	sig, err := crypto.Sign(e.HashToSign().Bytes(), makegenesis.FakeKey(1))
+	require.NoError(t, err)
+	sSig := inter.Signature{}
+	copy(sSig[:], sig)
+	e.SetSig(sSig)
+}
+
+// writeTestHistory writes blocks of the current epoch, which is sealed by the last block,
+// along with the LLR votes of the single validator, as it happens on a running node.
+func writeTestHistory(t *testing.T, gdb *gossip.Store, blocks idx.Block) (from, to idx.Block) {
+	from = gdb.GetLatestBlockIndex() + 1
+	to = from + blocks - 1
+	root := gdb.GetBlock(from - 1).Root
+
+	votes := make([]hash.Hash, 0, blocks)
+	for n := from; n <= to; n++ {
+		atropos := hash.Event{byte(n), 0xff}
+		gdb.SetBlock(n, &inter.Block{
+			Time:        inter.Timestamp(n),
+			Atropos:     atropos,
+			Events:      hash.Events{},
+			Txs:         []common.Hash{},
+			InternalTxs: []common.Hash{},
+			SkippedTxs:  []uint32{},
+			Root:        root,
+		})
+		gdb.SetBlockIndex(atropos, n)
+		br := ibr.LlrIdxFullBlockRecord{LlrFullBlockRecord: *gdb.GetFullBlockRecord(n), Idx: n}
+		votes = append(votes, br.Hash())
+	}
+	bvsEvent := &inter.MutableEventPayload{}
+	bvsEvent.SetEpoch(testFirstEpoch)
+	bvsEvent.SetBlockVotes(inter.LlrBlockVotes{
+		Start: from,
+		Epoch: testFirstEpoch,
+		Votes: votes,
+	})
+	signTestEvent(t, bvsEvent)
+	gdb.SetBlockVotes(inter.AsSignedBlockVotes(bvsEvent))
+
+	hbs, hes := gdb.GetHistoryBlockEpochState(testFirstEpoch)
+	bs, es := *hbs, *hes
+	bs.LastBlock.Idx = to
+	es.Epoch = testFirstEpoch + 1
+	gdb.SetHistoryBlockEpochState(testFirstEpoch+1, bs, es)
+	gdb.SetEpochBlock(to+1, testFirstEpoch+1)
+	er := ier.LlrIdxFullEpochRecord{LlrFullEpochRecord: *gdb.GetFullEpochRecord(testFirstEpoch + 1), Idx: testFirstEpoch + 1}
+
+	evEvent := &inter.MutableEventPayload{}
+	evEvent.SetEpoch(testFirstEpoch + 1)
+	evEvent.SetEpochVote(inter.LlrEpochVote{
+		Epoch: testFirstEpoch + 1,
+		Vote:  er.Hash(),
+	})
+	signTestEvent(t, evEvent)
+	gdb.SetEpochVote(inter.AsSignedEpochVote(evEvent))
+
+	return from, to
+}
+
+func writeBlocksFile(t *testing.T, data []byte) string {
+	fn := filepath.Join(t.TempDir(), "blocks.rlp")
+	require.NoError(t, ioutil.WriteFile(fn, append(append(append([]byte{}, blocksFileHeader...), blocksFileVersion...), data...), 0600))
+	return fn
+}
+
+func TestExportImportBlocks(t *testing.T) {
+	require := require.New(t)
+
+	_, source := makeTestService(t)
+	from, to := writeTestHistory(t, source, 5)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(exportBlocksTo(buf, source, from, to))
+	fn := writeBlocksFile(t, buf.Bytes())
+
+	// records are decided by the votes of the file on a fresh node
+	svc, target := makeTestService(t)
+	require.NoError(importBlocksFile(svc, fn))
+	for n := from; n <= to; n++ {
+		require.True(target.HasBlock(n), n)
+		require.Equal(source.GetFullBlockRecord(n), target.GetFullBlockRecord(n), n)
+	}
+	require.Equal(source.GetFullEpochRecord(testFirstEpoch+1), target.GetFullEpochRecord(testFirstEpoch+1))
+	require.Equal(to+1, target.GetLlrState().LowestBlockToFill)
+
+	// import is idempotent
+	require.NoError(importBlocksFile(svc, fn))
+
+	// records without votes aren't imported
+	svc, target = makeTestService(t)
+	var withoutVotes bytes.Buffer
+	stream := rlp.NewStream(bytes.NewReader(buf.Bytes()), 0)
+	for {
+		item := new(recordsFileItem)
+		if err := stream.Decode(item); err != nil {
+			break
+		}
+		if item.Kind == blockRecordItem || item.Kind == epochRecordItem {
+			require.NoError(rlp.Encode(&withoutVotes, item))
+		}
+	}
+	err := importBlocksFile(svc, writeBlocksFile(t, withoutVotes.Bytes()))
+	require.EqualError(err, "6 records aren't decided by LLR votes, the file must contain the votes and start at the history known to the node")
+	require.False(target.HasBlock(from))
+}
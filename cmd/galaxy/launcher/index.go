@@ -0,0 +1,139 @@
+package launcher
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/gossip"
+	"go-galaxy/gossip/blockproc/tokenindex"
+	"go-galaxy/integration"
+	"go-galaxy/utils/gsignercache"
+)
+
+// openIndexedDB opens the raw gossip store and parses the optional blocks range arguments.
+func openIndexedDB(ctx *cli.Context) (cfg *config, gdb *gossip.Store, from, to idx.Block, err error) {
+	if len(ctx.Args()) > 2 {
+		utils.Fatalf("This command accepts at most two arguments.")
+	}
+
+	cfg = makeAllConfigs(ctx)
+
+	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cfg.cachescale)
+	gdb, err = makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+
+	from = idx.Block(1)
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+		if err != nil {
+			gdb.Close()
+			return nil, nil, 0, 0, err
+		}
+		from = idx.Block(n)
+	}
+	to = gdb.GetLatestBlockIndex()
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			gdb.Close()
+			return nil, nil, 0, 0, err
+		}
+		to = idx.Block(n)
+	}
+	return cfg, gdb, from, to, nil
+}
+
+func indexAddresses(ctx *cli.Context) error {
+	cfg, gdb, from, to, err := openIndexedDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	log.Info("Indexing transactions by addresses", "from", from, "to", to)
+	indexAddressTxs(gdb, from, to)
+	if !cfg.Galaxy.AddressIndex {
+		log.Warn("Address index is disabled in the config, new blocks won't be indexed")
+	}
+	return nil
+}
+
+func indexTokens(ctx *cli.Context) error {
+	cfg, gdb, from, to, err := openIndexedDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer gdb.Close()
+
+	log.Info("Indexing token transfers", "from", from, "to", to)
+	err = indexTokenTransfers(gdb, from, to)
+	if err != nil {
+		return err
+	}
+	if !cfg.Galaxy.TokenIndex {
+		log.Warn("Token index is disabled in the config, new blocks won't be indexed")
+	}
+	return nil
+}
+
+// indexAddressTxs indexes the transactions of the blocks range by their senders and recipients.
+func indexAddressTxs(gdb *gossip.Store, from, to idx.Block) {
+	start, reported := time.Now(), time.Time{}
+
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(gdb.GetRules().EvmChainConfig().ChainID))
+	var txsNum int
+	for n := from; n <= to; n++ {
+		block := gdb.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		txs := gdb.GetBlockTxs(n, block)
+		gdb.EvmStore().IndexAddressTxs(n, txs, signer)
+		txsNum += len(txs)
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Indexing transactions by addresses", "last", n, "txs", txsNum, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Indexed transactions by addresses", "from", from, "to", to, "txs", txsNum, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// indexTokenTransfers indexes token transfers of the blocks range, using the logs index.
+func indexTokenTransfers(gdb *gossip.Store, from, to idx.Block) error {
+	const step = 1000
+	start, reported := time.Now(), time.Time{}
+
+	indexer := tokenindex.New(gdb.TokenIndex())
+	var transfersNum int
+	for n := from; n <= to; n += step {
+		last := n + step - 1
+		if last > to || last < n {
+			last = to
+		}
+		transfers, err := indexer.IndexBlocks(context.Background(), gdb.EvmStore().EvmLogs, n, last)
+		if err != nil {
+			return err
+		}
+		transfersNum += transfers
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Indexing token transfers", "last", last, "transfers", transfersNum, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+		if last == to {
+			break
+		}
+	}
+	log.Info("Indexed token transfers", "from", from, "to", to, "transfers", transfersNum, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"os"
 	"path"
@@ -28,7 +29,9 @@ import (
 	"go-galaxy/flags"
 	"go-galaxy/gossip"
 	"go-galaxy/gossip/emitter"
+	"go-galaxy/graphql"
 	"go-galaxy/integration"
+	"go-galaxy/rpcauth"
 	"go-galaxy/utils/errlock"
 	"go-galaxy/valkeystore"
 	_ "go-galaxy/version"
@@ -63,6 +66,11 @@ func initFlags() {
 	// Flags for testing purpose.
 	testFlags = []cli.Flag{
 		FakeNetFlag,
+		FakeNetAccountsFlag,
+		FakeNetRulesFlag,
+		FakeNetStakesFlag,
+		FakeNetEpochFlag,
+		FakeNetTimeFlag,
 	}
 
 	// Flags that configure the node.
@@ -116,6 +124,10 @@ func initFlags() {
 		validatorPubkeyFlag,
 		validatorPasswordFlag,
 		SyncModeFlag,
+		HistoryBackfillFlag,
+		EvmPruningPeriodFlag,
+		FreezerFlag,
+		utils.AncientFlag,
 	}
 	legacyRpcFlags = []cli.Flag{
 		utils.NoUSBFlag,
@@ -148,6 +160,8 @@ func initFlags() {
 		utils.IPCPathFlag,
 		RPCGlobalGasCapFlag,
 		RPCGlobalTxFeeCapFlag,
+		RPCAuthAddrFlag,
+		RPCAuthPortFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -166,6 +180,8 @@ func initFlags() {
 		utils.MetricsInfluxDBBucketFlag,
 		utils.MetricsInfluxDBOrganizationFlag,
 		tracing.EnableFlag,
+		tracing.EndpointFlag,
+		tracing.InsecureFlag,
 	}
 
 	nodeFlags = []cli.Flag{}
@@ -210,8 +226,11 @@ func init() {
 		importCommand,
 		exportCommand,
 		checkCommand,
+		indexCommand,
 		// See snapshot.go
 		snapshotCommand,
+		// See devnet.go
+		devnetCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -254,12 +273,11 @@ func lachesisMain(ctx *cli.Context) error {
 		return fmt.Errorf("invalid command: %q", args[0])
 	}
 
-	// TODO: tracing flags
-	//tracingStop, err := tracing.Start(ctx)
-	//if err != nil {
-	//	return err
-	//}
-	//defer tracingStop()
+	tracingStop, err := tracing.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer tracingStop()
 
 	cfg := makeAllConfigs(ctx)
 	genesisPath := getGalaxyGenesis(ctx)
@@ -271,6 +289,12 @@ func lachesisMain(ctx *cli.Context) error {
 }
 
 func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (*node.Node, *gossip.Service, func()) {
+	return makeNodeWithKey(ctx, cfg, genesis, getFakeValidatorKey(ctx), makeValidatorPasswordList(ctx))
+}
+
+// makeNodeWithKey makes a node, which unlocks the validator key with one of the passwords.
+// The fake validator key is added into the keystore if not nil.
+func makeNodeWithKey(ctx *cli.Context, cfg *config, genesis integration.InputGenesis, fakeKey *ecdsa.PrivateKey, valPasswords []string) (*node.Node, *gossip.Service, func()) {
 	// check errlock file
 	errlock.SetDefaultDatadir(cfg.Node.DataDir)
 	errlock.Check()
@@ -287,15 +311,15 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 
 	valKeystore := valkeystore.NewDefaultFileKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
 	valPubkey := cfg.Emitter.Validator.PubKey
-	if key := getFakeValidatorKey(ctx); key != nil && cfg.Emitter.Validator.ID != 0 {
-		addFakeValidatorKey(ctx, key, valPubkey, valKeystore)
-		coinbase := integration.SetAccountKey(stack.AccountManager(), key, "fakepassword")
+	if fakeKey != nil && cfg.Emitter.Validator.ID != 0 {
+		addFakeValidatorKey(ctx, fakeKey, valPubkey, valKeystore)
+		coinbase := integration.SetAccountKey(stack.AccountManager(), fakeKey, "fakepassword")
 		log.Info("Unlocked fake validator account", "address", coinbase.Address.Hex())
 	}
 
 	// unlock validator key
 	if !valPubkey.Empty() {
-		err := unlockValidatorKey(ctx, valPubkey, valKeystore)
+		err := unlockValidatorKey(valPubkey, valKeystore, valPasswords)
 		if err != nil {
 			utils.Fatalf("Failed to unlock validator key: %v", err)
 		}
@@ -303,18 +327,22 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 	signer := valkeystore.NewSigner(valKeystore)
 
 	// Create and register a gossip network service.
+	var txpool *evmcore.TxPool
 	newTxPool := func(reader evmcore.StateReader) gossip.TxPool {
 		if cfg.TxPool.Journal != "" {
 			cfg.TxPool.Journal = stack.ResolvePath(cfg.TxPool.Journal)
 		}
-		return evmcore.NewTxPool(cfg.TxPool, reader.Config(), reader)
+		txpool = evmcore.NewTxPool(cfg.TxPool, reader.Config(), reader)
+		return txpool
 	}
 	svc, err := gossip.NewService(stack, cfg.Galaxy, gdb, blockProc, engine, dagIndex, newTxPool)
 	if err != nil {
 		utils.Fatalf("Failed to create the service: %v", err)
 	}
+	var em *emitter.Emitter
 	if cfg.Emitter.Validator.ID != 0 {
-		svc.RegisterEmitter(emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer)))
+		em = emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer))
+		svc.RegisterEmitter(em)
 	}
 	err = engine.Bootstrap(svc.GetConsensusCallbacks())
 	if err != nil {
@@ -325,6 +353,30 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
+	if ctx.GlobalIsSet(utils.GraphQLEnabledFlag.Name) {
+		err = graphql.New(stack, svc.EthAPI, cfg.Galaxy.FilterAPI, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts)
+		if err != nil {
+			utils.Fatalf("Failed to register the GraphQL service: %v", err)
+		}
+	}
+
+	if cfg.RPCAuth.Host != "" {
+		_, err = rpcauth.New(stack, cfg.RPCAuth)
+		if err != nil {
+			utils.Fatalf("Failed to register the authenticated RPC endpoint: %v", err)
+		}
+	}
+
+	// config reloading is possible only if the node is configured by a config file
+	if cfg.configFile != "" {
+		reloader, err := newConfigReloader(ctx, svc, txpool, em)
+		if err != nil {
+			utils.Fatalf("Failed to create the config reloader: %v", err)
+		}
+		stack.RegisterAPIs(reloader.APIs())
+		stack.RegisterLifecycle(reloader)
+	}
+
 	return stack, svc, func() {
 		_ = stack.Close()
 		gdb.Close()
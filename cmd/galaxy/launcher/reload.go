@@ -0,0 +1,248 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/gossip"
+	"go-galaxy/gossip/emitter"
+)
+
+// hotConfigFields lists the config fields (or whole sections) which are applied by a config reload
+// while the node runs. Changes of any other field are reported as requiring a restart.
+var hotConfigFields = []string{
+	// Limits of the transaction pool, transactions exceeding the new limits are dropped immediately.
+	"TxPool.PriceLimit",
+	"TxPool.PriceBump",
+	"TxPool.AccountSlots",
+	"TxPool.GlobalSlots",
+	"TxPool.AccountQueue",
+	"TxPool.GlobalQueue",
+	"TxPool.Lifetime",
+	// Gas price oracle params, applied starting from the next suggestion.
+	"Galaxy.GPO",
+	// Block range limits of logs search, applied to the subsequent requests.
+	"Galaxy.FilterAPI",
+	// Limits of the peer caches, applied to the newly connected peers.
+	"Galaxy.Protocol.PeerCache",
+	// Emission intervals and thresholds of the validator's emitter, applied immediately.
+	// Note that emitting can't be enabled at runtime if it was started with zero EmitIntervals.Min.
+	"Emitter.EmitIntervals",
+	"Emitter.MaxTxsPerAddress",
+	"Emitter.MaxParents",
+	"Emitter.LimitedTpsThreshold",
+	"Emitter.NoTxsThreshold",
+	"Emitter.EmergencyThreshold",
+	"Emitter.TxsCacheInvalidation",
+}
+
+func isHotConfigField(field string) bool {
+	for _, hot := range hotConfigFields {
+		if field == hot || strings.HasPrefix(field, hot+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// diffConfigs returns paths of the exported fields which are different in the configs
+func diffConfigs(a, b interface{}) []string {
+	var diff []string
+	diffValues(reflect.ValueOf(a), reflect.ValueOf(b), "", &diff)
+	return diff
+}
+
+func diffValues(a, b reflect.Value, path string, diff *[]string) {
+	if a.Kind() == reflect.Struct {
+		exported := 0
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			exported++
+			fieldPath := f.Name
+			if path != "" {
+				fieldPath = path + "." + f.Name
+			}
+			diffValues(a.Field(i), b.Field(i), fieldPath, diff)
+		}
+		if exported != 0 {
+			return
+		}
+	}
+	if a.Kind() == reflect.Func {
+		return
+	}
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*diff = append(*diff, path)
+	}
+}
+
+// setConfigField copies the field, specified by a path, from src into dst
+func setConfigField(dst *config, src *config, path string) {
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src).Elem()
+	for _, name := range strings.Split(path, ".") {
+		d = d.FieldByName(name)
+		s = s.FieldByName(name)
+	}
+	d.Set(s)
+}
+
+// ConfigReloadResult lists the config fields which were changed since the last (re)load.
+type ConfigReloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restartRequired"`
+}
+
+// configReloader re-reads the config file on SIGHUP or by admin_reloadConfig call,
+// and applies the changes which are safe at runtime to the running node.
+type configReloader struct {
+	ctx     *cli.Context
+	svc     *gossip.Service
+	txpool  *evmcore.TxPool
+	emitter *emitter.Emitter // nil if not a validator
+
+	mu  sync.Mutex
+	cfg config // last parsed config with the applied hot fields
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newConfigReloader(ctx *cli.Context, svc *gossip.Service, txpool *evmcore.TxPool, em *emitter.Emitter) (*configReloader, error) {
+	// the config passed to the node is modified during the node setup,
+	// so the changes are tracked against a freshly parsed config
+	cfg, err := mayMakeAllConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &configReloader{
+		ctx:     ctx,
+		svc:     svc,
+		txpool:  txpool,
+		emitter: em,
+		cfg:     *cfg,
+	}, nil
+}
+
+// Reload re-reads the config file and applies the changes of hot fields.
+// Changes of other fields are reported on every reload until the node is restarted.
+func (r *configReloader) Reload() (*ConfigReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newCfg, err := mayMakeAllConfigs(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if newCfg.configFile == "" {
+		return nil, errors.New("config file isn't specified")
+	}
+
+	res := &ConfigReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+	var txpoolChanged, galaxyChanged, emitterChanged bool
+	for _, field := range diffConfigs(r.cfg, *newCfg) {
+		if !isHotConfigField(field) {
+			res.RestartRequired = append(res.RestartRequired, field)
+			continue
+		}
+		setConfigField(&r.cfg, newCfg, field)
+		res.Applied = append(res.Applied, field)
+		txpoolChanged = txpoolChanged || strings.HasPrefix(field, "TxPool.")
+		galaxyChanged = galaxyChanged || strings.HasPrefix(field, "Galaxy.")
+		emitterChanged = emitterChanged || strings.HasPrefix(field, "Emitter.")
+	}
+
+	if txpoolChanged && r.txpool != nil {
+		r.txpool.SetConfig(r.cfg.TxPool)
+	}
+	if galaxyChanged {
+		r.svc.UpdateConfig(r.cfg.Galaxy)
+	}
+	if emitterChanged && r.emitter != nil {
+		r.emitter.SetConfig(r.cfg.Emitter)
+	}
+	return res, nil
+}
+
+func (r *configReloader) reloadAndLog() {
+	res, err := r.Reload()
+	if err != nil {
+		log.Error("Failed to reload config", "err", err)
+		return
+	}
+	log.Info("Config reloaded", "applied", len(res.Applied), "restart_required", len(res.RestartRequired))
+	for _, field := range res.Applied {
+		log.Info("Applied config change", "field", field)
+	}
+	for _, field := range res.RestartRequired {
+		log.Warn("Config change requires a restart", "field", field)
+	}
+}
+
+// Start starts listening to SIGHUP, it implements node.Lifecycle.
+func (r *configReloader) Start() error {
+	r.quit = make(chan struct{})
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-sighup:
+				log.Info("Got SIGHUP, reloading config...")
+				r.reloadAndLog()
+			case <-r.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops listening to SIGHUP, it implements node.Lifecycle.
+func (r *configReloader) Stop() error {
+	close(r.quit)
+	r.wg.Wait()
+	return nil
+}
+
+// APIs returns the admin API of the reloader.
+func (r *configReloader) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   &PrivateConfigAPI{r},
+			Public:    false,
+		},
+	}
+}
+
+// PrivateConfigAPI provides an API to reload the node config.
+type PrivateConfigAPI struct {
+	r *configReloader
+}
+
+// ReloadConfig re-reads the config file, applies the changes which are safe at runtime
+// and reports the changes which require a restart.
+func (api *PrivateConfigAPI) ReloadConfig() (*ConfigReloadResult, error) {
+	return api.r.Reload()
+}
@@ -0,0 +1,53 @@
+package launcher
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/gossip"
+	"go-galaxy/gossip/emitter"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	require := require.New(t)
+
+	a := config{
+		Node:    defaultNodeConfig(),
+		Galaxy:  gossip.DefaultConfig(cachescale.Identity),
+		Emitter: emitter.DefaultConfig(),
+		TxPool:  evmcore.DefaultTxPoolConfig,
+	}
+	b := a
+	require.Empty(diffConfigs(a, b))
+
+	b.TxPool.GlobalSlots++
+	b.Galaxy.GPO.MaxTipCap = big.NewInt(1)
+	b.Emitter.EmitIntervals.Max = time.Hour
+	b.Node.P2P.MaxPeers++
+	diff := diffConfigs(a, b)
+	require.ElementsMatch([]string{
+		"TxPool.GlobalSlots",
+		"Galaxy.GPO.MaxTipCap",
+		"Emitter.EmitIntervals.Max",
+		"Node.P2P.MaxPeers",
+	}, diff)
+
+	require.True(isHotConfigField("TxPool.GlobalSlots"))
+	require.True(isHotConfigField("Galaxy.GPO.MaxTipCap"))
+	require.True(isHotConfigField("Emitter.EmitIntervals.Max"))
+	require.False(isHotConfigField("Node.P2P.MaxPeers"))
+	require.False(isHotConfigField("TxPool.Journal"))
+	require.False(isHotConfigField("Galaxy.GPOMore"))
+
+	for _, field := range diff {
+		if isHotConfigField(field) {
+			setConfigField(&a, &b, field)
+		}
+	}
+	require.Equal([]string{"Node.P2P.MaxPeers"}, diffConfigs(a, b))
+}
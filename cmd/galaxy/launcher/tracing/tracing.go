@@ -1,10 +1,13 @@
 package tracing
 
 import (
-	opentracing "github.com/opentracing/opentracing-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	jaegerlog "github.com/uber/jaeger-client-go/log"
-	"github.com/uber/jaeger-lib/metrics"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gopkg.in/urfave/cli.v1"
 
 	"go-galaxy/tracing"
@@ -15,33 +18,50 @@ var EnableFlag = cli.BoolFlag{
 	Usage: "Enable traces collection and reporting",
 }
 
+var EndpointFlag = cli.StringFlag{
+	Name:  "tracing.endpoint",
+	Usage: "OTLP/HTTP collector endpoint (host:port) the traces are exported to",
+	Value: "localhost:4318",
+}
+
+var InsecureFlag = cli.BoolFlag{
+	Name:  "tracing.insecure",
+	Usage: "Export traces to the OTLP collector over plain HTTP",
+}
+
 func Start(ctx *cli.Context) (stop func(), err error) {
 	stop = func() {}
 
-	if !ctx.Bool(EnableFlag.Name) {
+	if !ctx.GlobalBool(EnableFlag.Name) {
 		return
 	}
 
-	var cfg *jaegercfg.Configuration
-	cfg, err = jaegercfg.FromEnv()
-	if err != nil {
-		return
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(ctx.GlobalString(EndpointFlag.Name)),
 	}
-
-	cfg.ServiceName = "galaxy"
-
-	tracer, closer, err := cfg.NewTracer(
-		jaegercfg.Logger(jaegerlog.StdLogger),
-		jaegercfg.Metrics(metrics.NullFactory),
-	)
+	if ctx.GlobalBool(InsecureFlag.Name) {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
 	if err != nil {
 		return
 	}
+
+	provider := NewProvider(sdktrace.NewBatchSpanProcessor(exporter))
 	stop = func() {
-		closer.Close()
+		_ = provider.Shutdown(context.Background())
 	}
+	return
+}
 
-	opentracing.SetGlobalTracer(tracer)
+// NewProvider sets the global tracer provider which passes spans to the processor,
+// and enables traces collection.
+func NewProvider(processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "galaxy"))),
+	)
+	otel.SetTracerProvider(provider)
 	tracing.SetEnabled(true)
-	return
+	return provider
 }
@@ -0,0 +1,364 @@
+// Package web3ext contains galaxy specific web3.js extensions.
+package web3ext
+
+// Modules are the extensions of the galaxy specific API namespaces, keyed by namespace.
+// FormattersJs must be loaded before any of them.
+var Modules = map[string]string{
+	"abft": AbftJs,
+	"dag":  DagJs,
+	"deam": DeamJs,
+	"sfc":  SfcJs,
+}
+
+// FormattersJs defines the formatters of Lachesis-specific inputs and outputs.
+const FormattersJs = `
+(function () {
+	var utils = web3._extend.utils;
+	var formatters = web3._extend.formatters;
+
+	// converts the listed fields of an object (or of each object in an array) from hex,
+	// uint64 fields which may exceed the JS numbers precision (e.g. timestamps in nanoseconds) are converted into BigNumber
+	formatters.outputFieldsFormatter = function (numbers, bigs) {
+		var format = function (obj) {
+			if (obj === null || typeof obj !== 'object') {
+				return obj;
+			}
+			if (Array.isArray(obj)) {
+				return obj.map(format);
+			}
+			numbers.forEach(function (f) {
+				if (obj[f] !== undefined && obj[f] !== null) {
+					obj[f] = utils.toDecimal(obj[f]);
+				}
+			});
+			bigs.forEach(function (f) {
+				if (obj[f] !== undefined && obj[f] !== null) {
+					obj[f] = utils.toBigNumber(obj[f]);
+				}
+			});
+			return obj;
+		};
+		return format;
+	};
+
+	// accepts full event ID in hex or short event ID (epoch:lamport:prefix)
+	formatters.inputEventIDFormatter = function (id) {
+		if (!utils.isString(id)) {
+			throw new Error('event ID must be a string');
+		}
+		return id;
+	};
+
+	// accepts epoch number, 'pending' for the current epoch or 'latest' for the latest sealed epoch
+	formatters.inputEpochFormatter = function (epoch) {
+		if (epoch === undefined || epoch === null) {
+			return 'pending';
+		}
+		return formatters.inputBlockNumberFormatter(epoch);
+	};
+
+	formatters.inputValidatorIDFormatter = function (id) {
+		return utils.toHex(id);
+	};
+
+	formatters.inputOptionalAddressFormatter = function (addr) {
+		if (addr === undefined || addr === null) {
+			return null;
+		}
+		return formatters.inputAddressFormatter(addr);
+	};
+
+	formatters.inputOptionalNumberFormatter = function (num) {
+		if (num === undefined || num === null) {
+			return null;
+		}
+		return utils.toHex(num);
+	};
+
+	formatters.outputEventFormatter = function (event) {
+		event = formatters.outputFieldsFormatter(
+			['version', 'networkVersion', 'epoch', 'seq', 'frame', 'creator', 'lamport', 'gasPowerUsed', 'size'],
+			['creationTime', 'medianTime']
+		)(event);
+		if (event && event.gasPowerLeft) {
+			event.gasPowerLeft = formatters.outputFieldsFormatter(['shortTerm', 'longTerm'], [])(event.gasPowerLeft);
+		}
+		return event;
+	};
+
+	formatters.outputDowntimeFormatter = formatters.outputFieldsFormatter(['offlineBlocks'], ['offlineTime']);
+
+	formatters.outputStakerFormatter = formatters.outputFieldsFormatter(
+		['id', 'createdEpoch', 'deactivatedEpoch', 'missedBlocks'],
+		['totalStake', 'stake', 'delegatedMe', 'createdTime', 'deactivatedTime', 'downtime', 'poi',
+			'baseRewardWeight', 'txRewardWeight', 'validationScore', 'originationScore', 'claimedRewards', 'delegationsClaimedRewards']
+	);
+
+	formatters.outputDelegationFormatter = formatters.outputFieldsFormatter(
+		['toStakerID', 'createdEpoch', 'deactivatedEpoch'],
+		['amount', 'createdTime', 'deactivatedTime', 'claimedRewards']
+	);
+})();
+`
+
+const DagJs = `
+web3._extend({
+	property: 'dag',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEvent',
+			call: 'dag_getEvent',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputEventIDFormatter],
+			outputFormatter: web3._extend.formatters.outputEventFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getEventPayload',
+			call: 'dag_getEventPayload',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputEventIDFormatter, function (val) { return !!val; }],
+			outputFormatter: web3._extend.formatters.outputEventFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getHeads',
+			call: 'dag_getHeads',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputEpochFormatter]
+		}),
+	],
+	properties: []
+});
+var dag = web3.dag;
+`
+
+const AbftJs = `
+web3._extend({
+	property: 'abft',
+	methods: [
+		new web3._extend.Method({
+			name: 'getDowntime',
+			call: 'abft_getDowntime',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputDowntimeFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getEpochUptime',
+			call: 'abft_getEpochUptime',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+		new web3._extend.Method({
+			name: 'getOriginatedEpochFee',
+			call: 'abft_getOriginatedEpochFee',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+	],
+	properties: []
+});
+var abft = web3.abft;
+`
+
+const SfcJs = `
+web3._extend({
+	property: 'sfc',
+	methods: [
+		new web3._extend.Method({
+			name: 'getStaker',
+			call: 'sfc_getStaker',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter, web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputStakerFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getStakerByAddress',
+			call: 'sfc_getStakerByAddress',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputStakerFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getStakers',
+			call: 'sfc_getStakers',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputStakerFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getDelegation',
+			call: 'sfc_getDelegation',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputValidatorIDFormatter, web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputDelegationFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getDelegationsOf',
+			call: 'sfc_getDelegationsOf',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter, web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputDelegationFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getDelegationsByAddress',
+			call: 'sfc_getDelegationsByAddress',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex],
+			outputFormatter: web3._extend.formatters.outputDelegationFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getDowntime',
+			call: 'sfc_getDowntime',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputFieldsFormatter(['missedBlocks'], ['downtime'])
+		}),
+		new web3._extend.Method({
+			name: 'getStakerPoI',
+			call: 'sfc_getStakerPoI',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getRewardWeights',
+			call: 'sfc_getRewardWeights',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputFieldsFormatter([], ['baseRewardWeight', 'txRewardWeight'])
+		}),
+		new web3._extend.Method({
+			name: 'getValidationScore',
+			call: 'sfc_getValidationScore',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getOriginationScore',
+			call: 'sfc_getOriginationScore',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getStakerClaimedRewards',
+			call: 'sfc_getStakerClaimedRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getStakerDelegationsClaimedRewards',
+			call: 'sfc_getStakerDelegationsClaimedRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getDelegationClaimedRewards',
+			call: 'sfc_getDelegationClaimedRewards',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputValidatorIDFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+	],
+	properties: []
+});
+var sfc = web3.sfc;
+`
+
+// DeamJs extends the deam namespace, which doubles the eth one, by the galaxy specific methods.
+const DeamJs = `
+web3._extend({
+	property: 'deam',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEpochStats',
+			call: 'deam_getEpochStats',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputEpochFormatter],
+			outputFormatter: web3._extend.formatters.outputFieldsFormatter(['epoch'], ['start', 'end', 'totalFee', 'totalBaseRewardWeight', 'totalTxRewardWeight'])
+		}),
+		new web3._extend.Method({
+			name: 'getRules',
+			call: 'deam_getRules',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputEpochFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPendingRules',
+			call: 'deam_getPendingRules',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getRulesHistory',
+			call: 'deam_getRulesHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputEpochFormatter, web3._extend.formatters.inputEpochFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPendingUpgrades',
+			call: 'deam_getPendingUpgrades',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'deam_getTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'simulateCalls',
+			call: 'deam_simulateCalls',
+			params: 4,
+			inputFormatter: [
+				function (calls) { return calls.map(web3._extend.formatters.inputCallFormatter); },
+				web3._extend.formatters.inputDefaultBlockNumberFormatter,
+				null,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'deam_getTransactionsByAddress',
+			params: 5,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				null,
+				web3._extend.formatters.inputOptionalNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTokenTransfers',
+			call: 'deam_getTokenTransfers',
+			params: 6,
+			inputFormatter: [
+				web3._extend.formatters.inputOptionalAddressFormatter,
+				web3._extend.formatters.inputOptionalAddressFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				null,
+				web3._extend.formatters.inputOptionalNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTokenHoldings',
+			call: 'deam_getTokenHoldings',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputOptionalNumberFormatter]
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'currentEpoch',
+			getter: 'deam_currentEpoch',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+	]
+});
+var deam = web3.deam;
+`
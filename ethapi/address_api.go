@@ -0,0 +1,126 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deamchain/deam-v2-base/common/bigendian"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/gossip/evmstore"
+)
+
+const (
+	// DefaultAddressTxsLimit is the default page size of the address transactions history.
+	DefaultAddressTxsLimit = 100
+	// MaxAddressTxsLimit is the max page size of the address transactions history.
+	MaxAddressTxsLimit = 1000
+)
+
+// PublicAddressAPI provides an API to access the transactions history of addresses.
+// It offers only methods that operate on public data that is freely available to anyone.
+type PublicAddressAPI struct {
+	b Backend
+}
+
+// NewPublicAddressAPI creates a new address transactions history API.
+func NewPublicAddressAPI(b Backend) *PublicAddressAPI {
+	return &PublicAddressAPI{b}
+}
+
+// encodeAddressTxsCursor encodes the position the next page starts from.
+func encodeAddressTxsCursor(n idx.Block, offset uint32) hexutil.Bytes {
+	return append(n.Bytes(), bigendian.Uint32ToBytes(offset)...)
+}
+
+// decodeAddressTxsCursor decodes the position the next page starts from.
+func decodeAddressTxsCursor(cursor hexutil.Bytes) (idx.Block, uint32, error) {
+	if len(cursor) != 8+4 {
+		return 0, 0, errors.New("invalid cursor")
+	}
+	return idx.BytesToBlock(cursor[:8]), bigendian.BytesToUint32(cursor[8:]), nil
+}
+
+// GetTransactionsByAddress returns the transactions sent from or to the address within the blocks range,
+// in the order of execution. At most limit transactions are returned, if there're more of them then
+// the returned cursor should be passed to get the next page. Cursor is null on the last page.
+// Requires the address index to be enabled.
+func (s *PublicAddressAPI) GetTransactionsByAddress(ctx context.Context, addr common.Address, fromBlock, toBlock rpc.BlockNumber, cursor *hexutil.Bytes, limit *hexutil.Uint64) (map[string]interface{}, error) {
+	from, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock))
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", from, to)
+	}
+	max := uint64(DefaultAddressTxsLimit)
+	if limit != nil {
+		max = uint64(*limit)
+	}
+	if max == 0 || max > MaxAddressTxsLimit {
+		return nil, fmt.Errorf("limit must be in range [1, %d]", MaxAddressTxsLimit)
+	}
+	start, startOffset := from, uint32(0)
+	if cursor != nil {
+		start, startOffset, err = decodeAddressTxsCursor(*cursor)
+		if err != nil {
+			return nil, err
+		}
+		if start < from || start > to {
+			return nil, errors.New("cursor is out of the blocks range")
+		}
+	}
+
+	positions := make([]evmstore.AddressTx, 0, max)
+	var next hexutil.Bytes
+	err = s.b.ForEachAddressTx(ctx, addr, start, startOffset, func(tx evmstore.AddressTx) bool {
+		if tx.Block > to {
+			return false
+		}
+		if uint64(len(positions)) == max {
+			next = encodeAddressTxsCursor(tx.Block, tx.BlockOffset)
+			return false
+		}
+		positions = append(positions, tx)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*RPCTransaction, 0, len(positions))
+	var block *evmcore.EvmBlock
+	for _, position := range positions {
+		if block == nil || block.NumberU64() != uint64(position.Block) {
+			block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(position.Block))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, fmt.Errorf("block %d not found", position.Block)
+			}
+		}
+		if int(position.BlockOffset) >= len(block.Transactions) || block.Transactions[position.BlockOffset].Hash() != position.TxHash {
+			return nil, fmt.Errorf("address index is corrupted, tx %s isn't found in block %d", position.TxHash.Hex(), position.Block)
+		}
+		txs = append(txs, newRPCTransactionFromBlockIndex(block, uint64(position.BlockOffset)))
+	}
+
+	result := map[string]interface{}{
+		"transactions": txs,
+		"cursor":       nil,
+	}
+	if next != nil {
+		result["cursor"] = next
+	}
+	return result, nil
+}
@@ -43,7 +43,6 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/ethereum/go-ethereum/trie"
 	"github.com/tyler-smith/go-bip39"
 
 	"go-galaxy/evmcore"
@@ -803,16 +802,12 @@ func (s *PublicBlockChainAPI) GetHeaderByHash(ctx context.Context, hash common.H
 func (s *PublicBlockChainAPI) calculateExtBlockApi(ctx context.Context, blkNumber rpc.BlockNumber) extBlockApi {
 	var ext extBlockApi
 	if s.b.CalcBlockExtApi() && blkNumber != rpc.EarliestBlockNumber {
-		receipts, err := s.b.GetReceiptsByNumber(ctx, blkNumber)
+		receiptsRoot, bloom, err := s.b.GetReceiptsDigest(ctx, blkNumber)
 		if err != nil {
 			return ext
 		}
-		if receipts.Len() != 0 {
-			ext.receiptsRoot = types.DeriveSha(receipts, trie.NewStackTrie(nil))
-			ext.bloom = types.CreateBloom(receipts)
-		} else {
-			ext.receiptsRoot = types.EmptyRootHash
-		}
+		ext.receiptsRoot = receiptsRoot
+		ext.bloom = bloom
 	}
 	return ext
 }
@@ -847,6 +842,111 @@ func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Ha
 	return nil, err
 }
 
+// GetBlockReceipts returns the receipts of all the transactions in the block.
+func (s *PublicBlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	number, err := s.b.ResolveRpcBlockNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.getBlockReceipts(ctx, number)
+}
+
+func (s *PublicBlockChainAPI) getBlockReceipts(ctx context.Context, number idx.Block) ([]map[string]interface{}, error) {
+	block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	if receipts.Len() != block.Transactions.Len() {
+		return nil, fmt.Errorf("receipts of block %d not found", number)
+	}
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), block.Number))
+	res := make([]map[string]interface{}, receipts.Len())
+	for i, receipt := range receipts {
+		res[i] = marshalReceipt(receipt, &block.EvmHeader, block.Transactions[i], uint64(i), signer)
+	}
+	return res, nil
+}
+
+// maxBlockReceiptsRange is the max number of blocks which are streamed by a BlockReceipts subscription
+const maxBlockReceiptsRange = 1024
+
+// BlockReceipts creates a subscription which streams the receipts of every block in the range [from, to],
+// one notification per block, and finishes when the last block is sent.
+// Blocks which aren't processed yet are sent once they get processed.
+func (s *PublicBlockChainAPI) BlockReceipts(ctx context.Context, from, to rpc.BlockNumber) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if from < 0 || to < 0 {
+		return nil, errors.New("block range must be specified by numbers")
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	if to-from >= maxBlockReceiptsRange {
+		return nil, fmt.Errorf("too wide blocks range, the limit is %d", maxBlockReceiptsRange)
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	// waitBlock waits for the block to get processed, it returns false if the subscription is closed
+	waitBlock := func(n idx.Block) bool {
+		if s.b.CurrentBlock().NumberU64() >= uint64(n) {
+			return true
+		}
+		// subscribe only while waiting to not hold the blocks processing while the receipts are sent
+		heads := make(chan evmcore.ChainHeadNotify, 1)
+		headsSub := s.b.SubscribeNewBlockNotify(heads)
+		defer headsSub.Unsubscribe()
+		for s.b.CurrentBlock().NumberU64() < uint64(n) {
+			select {
+			case <-heads:
+			case <-headsSub.Err():
+				return false
+			case <-rpcSub.Err():
+				return false
+			case <-notifier.Closed():
+				return false
+			}
+		}
+		return true
+	}
+
+	go func() {
+		for n := idx.Block(from); n <= idx.Block(to); n++ {
+			if !waitBlock(n) {
+				return
+			}
+			receipts, err := s.getBlockReceipts(context.Background(), n)
+			if err != nil {
+				log.Warn("Failed to stream block receipts", "block", n, "err", err)
+				return
+			}
+			err = notifier.Notify(rpcSub.ID, map[string]interface{}{
+				"blockNumber": hexutil.Uint64(n),
+				"receipts":    receipts,
+			})
+			if err != nil {
+				return
+			}
+			select {
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			default:
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // GetUncleByBlockNumberAndIndex returns the uncle block for the given block hash and index. When fullTx is true
 // all transactions in the block are returned in full detail, otherwise only the transaction hash is returned.
 func (s *PublicBlockChainAPI) GetUncleByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) (map[string]interface{}, error) {
@@ -1697,21 +1797,27 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
+	// Derive the sender.
+	bigblock := new(big.Int).SetUint64(blockNumber)
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), bigblock))
+	return marshalReceipt(receipt, header, tx, index, signer), nil
+}
+
+// marshalReceipt converts a receipt of a tx into the RPC representation.
+func marshalReceipt(receipt *types.Receipt, header *evmcore.EvmHeader, tx *types.Transaction, index uint64, signer types.Signer) map[string]interface{} {
+	blockNumber := header.Number.Uint64()
 	for _, l := range receipt.Logs {
-		l.TxHash = hash
+		l.TxHash = tx.Hash()
 		l.BlockHash = header.Hash
 		l.BlockNumber = blockNumber
 	}
 
-	// Derive the sender.
-	bigblock := new(big.Int).SetUint64(blockNumber)
-	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), bigblock))
 	from, _ := types.Sender(signer, tx)
 
 	fields := map[string]interface{}{
 		"blockHash":         header.Hash,
 		"blockNumber":       hexutil.Uint64(blockNumber),
-		"transactionHash":   hash,
+		"transactionHash":   tx.Hash(),
 		"transactionIndex":  hexutil.Uint64(index),
 		"from":              from,
 		"to":                tx.To(),
@@ -1742,7 +1848,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if tx.To() == nil {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
-	return fields, nil
+	return fields
 }
 
 // sign is a helper function that signs a transaction with the private key of the given address.
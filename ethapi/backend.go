@@ -20,6 +20,7 @@ package ethapi
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/deamchain/deam-v2-base/hash"
 	"github.com/deamchain/deam-v2-base/inter/idx"
@@ -35,8 +36,12 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"go-galaxy/evmcore"
+	"go-galaxy/galaxy"
+	"go-galaxy/gossip/blockproc/tokenindex"
+	"go-galaxy/gossip/evmstore"
 	"go-galaxy/gossip/sfcapi"
 	"go-galaxy/inter"
+	"go-galaxy/inter/iblockproc"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -71,16 +76,23 @@ type Backend interface {
 	ResolveRpcBlockNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (idx.Block, error)
 	BlockByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmBlock, error)
 	GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error)
+	GetReceiptsDigest(ctx context.Context, number rpc.BlockNumber) (common.Hash, types.Bloom, error)
 	GetTd(hash common.Hash) *big.Int
 	GetEVM(ctx context.Context, msg evmcore.Message, state *state.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error)
 	MinGasPrice() *big.Int
 	MaxGasLimit() uint64
+	SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotify) notify.Subscription
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error)
+	GetTxPosition(txHash common.Hash) *evmstore.TxPosition
+	ForEachAddressTx(ctx context.Context, addr common.Address, start idx.Block, startOffset uint32, onTx func(evmstore.AddressTx) bool) error
+	ForEachTokenTransfer(ctx context.Context, holder, token *common.Address, start tokenindex.Position, onTransfer func(*tokenindex.Transfer) bool) error
+	ForEachTokenHolding(ctx context.Context, holder, startToken common.Address, startID *big.Int, onHolding func(*tokenindex.Holding) bool) error
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
+	GetSeenPoolTransaction(txHash common.Hash) (*types.Transaction, time.Time)
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
@@ -93,9 +105,14 @@ type Backend interface {
 	// Lachesis DAG API
 	GetEventPayload(ctx context.Context, shortEventID string) (*inter.EventPayload, error)
 	GetEvent(ctx context.Context, shortEventID string) (*inter.Event, error)
+	GetTxEvent(txHash common.Hash) hash.Event
+	IsEventConfirmed(id hash.Event) bool
 	GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error)
 	CurrentEpoch(ctx context.Context) idx.Epoch
 	SealedEpochTiming(ctx context.Context) (start inter.Timestamp, end inter.Timestamp)
+	GetEpochRules(ctx context.Context, epoch rpc.BlockNumber) (*galaxy.Rules, idx.Epoch, error)
+	GetPendingRules(ctx context.Context) (*galaxy.Rules, idx.Epoch)
+	GetEpochState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.EpochState, error)
 
 	// Lachesis SFC API
 	GetValidators(ctx context.Context) *pos.Validators
@@ -172,6 +189,31 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicAbftAPI(apiBackend),
 			Public:    true,
+		}, {
+			Namespace: "deam",
+			Version:   "1.0",
+			Service:   NewPublicRulesAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "deam",
+			Version:   "1.0",
+			Service:   NewPublicTxStatusAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "deam",
+			Version:   "1.0",
+			Service:   NewPublicSimulateAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "deam",
+			Version:   "1.0",
+			Service:   NewPublicAddressAPI(apiBackend),
+			Public:    true,
+		}, {
+			Namespace: "deam",
+			Version:   "1.0",
+			Service:   NewPublicTokenAPI(apiBackend),
+			Public:    true,
 		},
 	}
 
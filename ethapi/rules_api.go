@@ -0,0 +1,123 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/galaxy"
+	"go-galaxy/version"
+)
+
+// PublicRulesAPI provides an API to access the network rules and their changes.
+// It offers only methods that operate on public data that is freely available to anyone.
+type PublicRulesAPI struct {
+	b Backend
+}
+
+// NewPublicRulesAPI creates a new network rules API.
+func NewPublicRulesAPI(b Backend) *PublicRulesAPI {
+	return &PublicRulesAPI{b}
+}
+
+// maxRulesHistoryEpochs is the max number of epochs which are scanned by GetRulesHistory
+const maxRulesHistoryEpochs = 1000
+
+// GetRules returns the network rules of an epoch.
+// * When epoch is -2 the rules of the current epoch are returned.
+// * When epoch is -1 the rules of the latest sealed epoch are returned.
+func (s *PublicRulesAPI) GetRules(ctx context.Context, epoch rpc.BlockNumber) (*galaxy.Rules, error) {
+	rules, requested, err := s.b.GetEpochRules(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, fmt.Errorf("rules of epoch %d not found", requested)
+	}
+	return rules, nil
+}
+
+// GetPendingRules returns the network rules which will be applied in the next epoch.
+func (s *PublicRulesAPI) GetPendingRules(ctx context.Context) map[string]interface{} {
+	rules, epoch := s.b.GetPendingRules(ctx)
+	return map[string]interface{}{
+		"epoch": hexutil.Uint64(epoch),
+		"rules": rules,
+	}
+}
+
+// GetRulesHistory returns every epoch of the range in which the network rules were changed, along with the
+// changed fields. The first known epoch of the range is always included with the full rules and no diff.
+// Epochs with no stored state are skipped. Epochs are resolved like in GetRules.
+func (s *PublicRulesAPI) GetRulesHistory(ctx context.Context, fromEpoch rpc.BlockNumber, toEpoch rpc.BlockNumber) ([]map[string]interface{}, error) {
+	_, from, err := s.b.GetEpochRules(ctx, fromEpoch)
+	if err != nil {
+		return nil, err
+	}
+	_, to, err := s.b.GetEpochRules(ctx, toEpoch)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("from epoch %d is greater than to epoch %d", from, to)
+	}
+	if to-from >= maxRulesHistoryEpochs {
+		return nil, fmt.Errorf("too wide epochs range, the limit is %d", maxRulesHistoryEpochs)
+	}
+
+	res := make([]map[string]interface{}, 0)
+	var prev *galaxy.Rules
+	for epoch := from; epoch <= to; epoch++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rules, _, err := s.b.GetEpochRules(ctx, rpc.BlockNumber(epoch))
+		if err != nil {
+			return nil, err
+		}
+		if rules == nil {
+			continue
+		}
+		if prev == nil {
+			res = append(res, map[string]interface{}{
+				"epoch": hexutil.Uint64(epoch),
+				"rules": rules,
+				"diff":  nil,
+			})
+			prev = rules
+			continue
+		}
+		diff, err := galaxy.DiffRules(*prev, *rules)
+		if err != nil {
+			return nil, err
+		}
+		if diff != nil {
+			res = append(res, map[string]interface{}{
+				"epoch": hexutil.Uint64(epoch),
+				"rules": rules,
+				"diff":  diff,
+			})
+		}
+		prev = rules
+	}
+	return res, nil
+}
+
+// GetPendingUpgrades returns the network upgrades which are scheduled to be enabled in future epochs,
+// including the upgrades announced in the current epoch.
+// Upgrades scheduled at an already started epoch are enabled in the next epoch.
+func (s *PublicRulesAPI) GetPendingUpgrades(ctx context.Context) ([]map[string]interface{}, error) {
+	rules, _ := s.b.GetPendingRules(ctx)
+	res := make([]map[string]interface{}, 0, len(rules.UpgradeSchedule))
+	for _, u := range rules.UpgradeSchedule {
+		res = append(res, map[string]interface{}{
+			"epoch":      hexutil.Uint64(u.Epoch),
+			"upgrades":   u.Upgrades,
+			"minVersion": version.U64ToString(u.MinVersion),
+			"supported":  u.MinVersion <= version.AsU64(),
+		})
+	}
+	return res, nil
+}
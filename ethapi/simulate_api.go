@@ -0,0 +1,181 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy"
+	"go-galaxy/inter"
+)
+
+// BlockOverrides is the set of header fields to override during the simulation.
+type BlockOverrides struct {
+	Number  *hexutil.Big    `json:"number"`
+	Time    *hexutil.Uint64 `json:"time"` // UNIX seconds
+	BaseFee *hexutil.Big    `json:"baseFee"`
+}
+
+// Apply overrides the fields of the given header.
+func (o *BlockOverrides) Apply(header *evmcore.EvmHeader) {
+	if o == nil {
+		return
+	}
+	if o.Number != nil {
+		header.Number = o.Number.ToInt()
+	}
+	if o.Time != nil {
+		header.Time = inter.FromUnix(int64(*o.Time))
+	}
+	if o.BaseFee != nil {
+		header.BaseFee = o.BaseFee.ToInt()
+	}
+}
+
+// SimulatedCallResult is the result of one call of the simulation.
+type SimulatedCallResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []*types.Log   `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Status     hexutil.Uint64 `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Revert     hexutil.Bytes  `json:"revert,omitempty"`
+}
+
+// PublicSimulateAPI provides an API to simulate sequences of calls.
+// It offers only methods that operate on public data that is freely available to anyone.
+type PublicSimulateAPI struct {
+	b Backend
+}
+
+// NewPublicSimulateAPI creates a new simulation API.
+func NewPublicSimulateAPI(b Backend) *PublicSimulateAPI {
+	return &PublicSimulateAPI{b}
+}
+
+// SimulateCalls executes the calls one by one on top of the same state, so every call sees
+// the effects of the previous ones. The state overrides are applied before the first call,
+// the block overrides are applied to the block context of all the calls.
+// A failed or reverted call doesn't abort the simulation, its changes are discarded.
+// RPCGasCap limits the total gas of all the calls.
+//
+// Note, this function doesn't make and changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (s *PublicSimulateAPI) SimulateCalls(ctx context.Context, calls []TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) ([]*SimulatedCallResult, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	return DoSimulateCalls(ctx, s.b, calls, bNrOrHash, overrides, blockOverrides, 5*time.Second, s.b.RPCGasCap())
+}
+
+// DoSimulateCalls executes the calls sequentially on the same state.
+func DoSimulateCalls(ctx context.Context, b Backend, calls []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) ([]*SimulatedCallResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM calls simulation finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(calls) == 0 {
+		return nil, errors.New("no calls to simulate")
+	}
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, err
+	}
+	simHeader := *header
+	blockOverrides.Apply(&simHeader)
+
+	// Setup context so it may be cancelled the simulation has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	gasLeft := globalGasCap
+	results := make([]*SimulatedCallResult, 0, len(calls))
+	for i, args := range calls {
+		if globalGasCap != 0 && gasLeft == 0 {
+			return nil, fmt.Errorf("gas cap %d is exhausted by call %d", globalGasCap, i-1)
+		}
+		msg, err := args.ToMessage(gasLeft, simHeader.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		vmConfig := galaxy.DefaultVMConfig
+		vmConfig.NoBaseFee = true
+		evm, vmError, err := b.GetEVM(ctx, msg, state, &simHeader, &vmConfig)
+		if err != nil {
+			return nil, err
+		}
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+
+		// a fake tx hash to collect the logs of the call
+		txHash := common.BigToHash(big.NewInt(int64(i + 1)))
+		state.Prepare(txHash, i)
+		gp := new(evmcore.GasPool).AddGas(math.MaxUint64)
+		result, applyErr := evmcore.ApplyMessage(evm, msg, gp)
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+
+		res := &SimulatedCallResult{
+			Logs: []*types.Log{},
+		}
+		if applyErr != nil {
+			res.Error = fmt.Sprintf("err: %v (supplied gas %d)", applyErr, msg.Gas())
+			results = append(results, res)
+			continue
+		}
+		state.Finalise(true)
+
+		res.ReturnData = result.Return()
+		res.GasUsed = hexutil.Uint64(result.UsedGas)
+		if result.Err == nil {
+			res.Status = hexutil.Uint64(types.ReceiptStatusSuccessful)
+		} else {
+			res.Error = result.Err.Error()
+			if len(result.Revert()) > 0 {
+				res.Error = newRevertError(result).Error()
+				res.Revert = result.Revert()
+			}
+		}
+		for _, l := range state.GetLogs(txHash, simHeader.Hash) {
+			l.BlockNumber = simHeader.Number.Uint64()
+			l.TxHash = common.Hash{}
+			res.Logs = append(res.Logs, l)
+		}
+		results = append(results, res)
+
+		if globalGasCap != 0 {
+			gasLeft -= result.UsedGas
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,179 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/gossip/blockproc/tokenindex"
+)
+
+const (
+	// DefaultTokenIndexLimit is the default page size of token transfers and holdings.
+	DefaultTokenIndexLimit = 100
+	// MaxTokenIndexLimit is the max page size of token transfers and holdings.
+	MaxTokenIndexLimit = 1000
+)
+
+// PublicTokenAPI provides an API to access the indexed token transfers and balances.
+// It offers only methods that operate on public data that is freely available to anyone.
+type PublicTokenAPI struct {
+	b Backend
+}
+
+// NewPublicTokenAPI creates a new token index API.
+func NewPublicTokenAPI(b Backend) *PublicTokenAPI {
+	return &PublicTokenAPI{b}
+}
+
+func tokenIndexLimit(limit *hexutil.Uint64) (int, error) {
+	if limit == nil {
+		return DefaultTokenIndexLimit, nil
+	}
+	if *limit == 0 || *limit > MaxTokenIndexLimit {
+		return 0, fmt.Errorf("limit must be in range [1, %d]", MaxTokenIndexLimit)
+	}
+	return int(*limit), nil
+}
+
+// tokenID returns nil for ERC-20 tokens which have no IDs.
+func tokenID(standard tokenindex.Standard, id *big.Int) *hexutil.Big {
+	if standard == tokenindex.ERC20 {
+		return nil
+	}
+	return (*hexutil.Big)(id)
+}
+
+func rpcMarshalTokenTransfer(t *tokenindex.Transfer) map[string]interface{} {
+	return map[string]interface{}{
+		"blockNumber":     hexutil.Uint64(t.Position.Block),
+		"logIndex":        hexutil.Uint64(t.Position.LogIndex),
+		"transactionHash": t.TxHash,
+		"standard":        t.Standard.String(),
+		"token":           t.Token,
+		"from":            t.From,
+		"to":              t.To,
+		"tokenId":         tokenID(t.Standard, t.ID),
+		"value":           (*hexutil.Big)(t.Value),
+	}
+}
+
+// GetTokenTransfers returns ERC-20, ERC-721 and ERC-1155 transfers within the blocks range in chronological order.
+// If holder is specified, then transfers sent or received by the holder are returned, optionally filtered by the token.
+// Otherwise, all the transfers of the token are returned.
+// At most limit transfers are returned, if there're more of them then the returned cursor should be passed
+// to get the next page. Cursor is null on the last page. Requires the token index to be enabled.
+func (s *PublicTokenAPI) GetTokenTransfers(ctx context.Context, holder, token *common.Address, fromBlock, toBlock rpc.BlockNumber, cursor *hexutil.Bytes, limit *hexutil.Uint64) (map[string]interface{}, error) {
+	if holder == nil && token == nil {
+		return nil, errors.New("either holder or token must be specified")
+	}
+	from, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock))
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is greater than toBlock %d", from, to)
+	}
+	max, err := tokenIndexLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	start := tokenindex.Position{Block: from}
+	if cursor != nil {
+		if len(*cursor) != tokenindex.PositionSize {
+			return nil, errors.New("invalid cursor")
+		}
+		start = tokenindex.BytesToPosition(*cursor)
+		if start.Block < from || start.Block > to {
+			return nil, errors.New("cursor is out of the blocks range")
+		}
+	}
+
+	transfers := make([]map[string]interface{}, 0, max)
+	var next hexutil.Bytes
+	err = s.b.ForEachTokenTransfer(ctx, holder, token, start, func(t *tokenindex.Transfer) bool {
+		if t.Position.Block > to {
+			return false
+		}
+		if holder != nil && token != nil && t.Token != *token {
+			return true
+		}
+		if len(transfers) == max {
+			next = t.Position.Bytes()
+			return false
+		}
+		transfers = append(transfers, rpcMarshalTokenTransfer(t))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"transfers": transfers,
+		"cursor":    nil,
+	}
+	if next != nil {
+		result["cursor"] = next
+	}
+	return result, nil
+}
+
+// GetTokenHoldings returns the latest known non-zero token balances of the holder, ordered by token address and token ID.
+// At most limit holdings are returned, if there're more of them then the returned cursor should be passed
+// to get the next page. Cursor is null on the last page. Requires the token index to be enabled.
+func (s *PublicTokenAPI) GetTokenHoldings(ctx context.Context, holder common.Address, cursor *hexutil.Bytes, limit *hexutil.Uint64) (map[string]interface{}, error) {
+	max, err := tokenIndexLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	startToken, startID := common.Address{}, new(big.Int)
+	if cursor != nil {
+		if len(*cursor) != common.AddressLength+common.HashLength {
+			return nil, errors.New("invalid cursor")
+		}
+		startToken = common.BytesToAddress((*cursor)[:common.AddressLength])
+		startID.SetBytes((*cursor)[common.AddressLength:])
+	}
+
+	holdings := make([]map[string]interface{}, 0, max)
+	var next hexutil.Bytes
+	err = s.b.ForEachTokenHolding(ctx, holder, startToken, startID, func(h *tokenindex.Holding) bool {
+		// negative balances are met if not all the transfers are indexed yet
+		if h.Balance.Sign() <= 0 {
+			return true
+		}
+		if len(holdings) == max {
+			next = append(h.Token.Bytes(), common.BigToHash(h.ID).Bytes()...)
+			return false
+		}
+		holdings = append(holdings, map[string]interface{}{
+			"standard": h.Standard.String(),
+			"token":    h.Token,
+			"tokenId":  tokenID(h.Standard, h.ID),
+			"balance":  (*hexutil.Big)(h.Balance),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"holdings": holdings,
+		"cursor":   nil,
+	}
+	if next != nil {
+		result["cursor"] = next
+	}
+	return result, nil
+}
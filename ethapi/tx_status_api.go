@@ -0,0 +1,189 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/inter"
+	"go-galaxy/utils/gsignercache"
+)
+
+// Transaction statuses, in the order of the transaction lifecycle
+const (
+	TxStatusQueued    = "queued"    // in the local txpool, not executable yet
+	TxStatusPending   = "pending"   // in the local txpool, waiting to be included into an event
+	TxStatusIncluded  = "included"  // included into an event, which isn't confirmed yet
+	TxStatusFinalized = "finalized" // included into a block, i.e. the event was confirmed by an Atropos
+	TxStatusReplaced  = "replaced"  // another transaction with the same nonce is in the local txpool or is finalized
+	TxStatusDropped   = "dropped"   // removed from the local txpool or skipped by the block processing
+)
+
+// PublicTxStatusAPI provides an API to track the transactions lifecycle.
+// It offers only methods that operate on public data that is freely available to anyone.
+type PublicTxStatusAPI struct {
+	b Backend
+}
+
+// NewPublicTxStatusAPI creates a new transaction status API.
+func NewPublicTxStatusAPI(b Backend) *PublicTxStatusAPI {
+	return &PublicTxStatusAPI{b}
+}
+
+// GetTransactionStatus returns the lifecycle status of a transaction:
+// * "queued" or "pending" with a reason if the tx is in the local txpool;
+// * "included" with the event which carries the tx, if the event isn't confirmed yet;
+// * "finalized" with the event and the block (which hash is the confirming Atropos);
+// * "replaced" or "dropped" if the tx was seen in the local txpool but was removed from it;
+// * "dropped" with the event, if the event was confirmed but the tx was skipped by the block processing,
+// or if the event's epoch was sealed without confirming the event.
+// The field timeToFinality is the number of nanoseconds between the moment when the tx
+// was first seen by the node (or when the event was created, if the tx wasn't seen in the local txpool)
+// and the block time.
+// Txs which aren't known to the node are reported as nil. Not finalized txs are tracked only
+// since the node start and only for a limited number of recent txs.
+func (s *PublicTxStatusAPI) GetTransactionStatus(ctx context.Context, txHash common.Hash) (map[string]interface{}, error) {
+	res := map[string]interface{}{
+		"hash": txHash,
+	}
+
+	seenTx, seenTime := s.b.GetSeenPoolTransaction(txHash)
+	if seenTx != nil {
+		res["seenTime"] = hexutil.Uint64(seenTime.UnixNano())
+	}
+
+	// finalized
+	tx, blockNumber, _, err := s.b.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx != nil {
+		res["status"] = TxStatusFinalized
+		s.fillTxFields(res, tx)
+		position := s.b.GetTxPosition(txHash)
+		var eventTime inter.Timestamp
+		if position != nil && position.Event != hash.ZeroEvent {
+			event, err := s.fillEventFields(ctx, res, position.Event)
+			if err != nil {
+				return nil, err
+			}
+			if event != nil {
+				eventTime = event.CreationTime()
+			}
+		}
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(blockNumber))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("block %d not found", blockNumber)
+		}
+		res["blockNumber"] = hexutil.Uint64(blockNumber)
+		res["atropos"] = header.Hash
+		res["finalizedTime"] = hexutil.Uint64(header.Time)
+		startTime := eventTime
+		if seenTx != nil {
+			startTime = inter.Timestamp(seenTime.UnixNano())
+		}
+		if startTime != 0 && header.Time >= startTime {
+			res["timeToFinality"] = hexutil.Uint64(header.Time - startTime)
+		}
+		return res, nil
+	}
+
+	// included into an event
+	if eventID := s.b.GetTxEvent(txHash); eventID != hash.ZeroEvent {
+		res["status"] = TxStatusIncluded
+		if seenTx != nil {
+			s.fillTxFields(res, seenTx)
+		}
+		_, err := s.fillEventFields(ctx, res, eventID)
+		if err != nil {
+			return nil, err
+		}
+		// the tx isn't finalized, so it was skipped by the block processing or the event won't be confirmed
+		if s.b.IsEventConfirmed(eventID) {
+			res["status"] = TxStatusDropped
+			res["reason"] = "skipped by the block processing"
+		} else if eventID.Epoch() < s.b.CurrentEpoch(ctx) {
+			res["status"] = TxStatusDropped
+			res["reason"] = "the event wasn't confirmed before the epoch was sealed"
+		}
+		return res, nil
+	}
+
+	// in the local txpool
+	if tx := s.b.GetPoolTransaction(txHash); tx != nil {
+		from := s.fillTxFields(res, tx)
+		pending, _ := s.b.TxPoolContentFrom(from)
+		for _, ptx := range pending {
+			if ptx.Hash() == txHash {
+				res["status"] = TxStatusPending
+				res["reason"] = "waiting to be included into an event by a validator"
+				return res, nil
+			}
+		}
+		res["status"] = TxStatusQueued
+		poolNonce, err := s.b.GetPoolNonce(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Nonce() > poolNonce {
+			res["reason"] = fmt.Sprintf("nonce gap, waiting for a transaction with nonce %d", poolNonce)
+		} else {
+			res["reason"] = "not executable"
+		}
+		return res, nil
+	}
+
+	// removed from the local txpool
+	if seenTx != nil {
+		from := s.fillTxFields(res, seenTx)
+		pending, queued := s.b.TxPoolContentFrom(from)
+		for _, ptx := range append(pending, queued...) {
+			if ptx.Nonce() == seenTx.Nonce() {
+				res["status"] = TxStatusReplaced
+				res["replacedBy"] = ptx.Hash()
+				return res, nil
+			}
+		}
+		poolNonce, err := s.b.GetPoolNonce(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		if seenTx.Nonce() < poolNonce {
+			res["status"] = TxStatusReplaced
+			res["reason"] = "nonce was used by another transaction"
+			return res, nil
+		}
+		res["status"] = TxStatusDropped
+		res["reason"] = "removed from the txpool"
+		return res, nil
+	}
+
+	return nil, nil
+}
+
+func (s *PublicTxStatusAPI) fillTxFields(res map[string]interface{}, tx *types.Transaction) common.Address {
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(s.b.ChainConfig().ChainID))
+	from, _ := types.Sender(signer, tx)
+	res["from"] = from
+	res["nonce"] = hexutil.Uint64(tx.Nonce())
+	return from
+}
+
+func (s *PublicTxStatusAPI) fillEventFields(ctx context.Context, res map[string]interface{}, id hash.Event) (*inter.Event, error) {
+	res["event"] = hexutil.Bytes(id.Bytes())
+	event, err := s.b.GetEvent(ctx, id.Hex())
+	if err != nil || event == nil {
+		return nil, err
+	}
+	res["creator"] = hexutil.Uint64(event.Creator())
+	res["eventTime"] = hexutil.Uint64(event.CreationTime())
+	return event, nil
+}
@@ -15,6 +15,7 @@ import (
 	"go-galaxy/eventcheck/epochcheck"
 	"go-galaxy/inter"
 	"go-galaxy/inter/validatorpk"
+	"go-galaxy/tracing"
 )
 
 var (
@@ -101,6 +102,17 @@ func (v *Checker) Overloaded() bool {
 }
 
 func (v *Checker) EnqueueEvent(e inter.EventPayloadI, onValidated func(error)) error {
+	if tracing.Enabled() {
+		span := tracing.StartEventStage(e.ID(), "heavycheck")
+		validated := onValidated
+		onValidated = func(err error) {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.End()
+			validated(err)
+		}
+	}
 	op := &taskData{
 		event:       e,
 		onValidated: onValidated,
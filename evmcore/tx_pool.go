@@ -450,6 +450,31 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
+// SetConfig updates the price and slots limits of a running pool. Locals and journal
+// settings aren't changed. Transactions exceeding the new limits are dropped by
+// the next pool reorganisation, which is requested immediately.
+func (pool *TxPool) SetConfig(config TxPoolConfig) {
+	config = (&config).sanitize()
+
+	pool.mu.Lock()
+	oldPriceLimit := pool.config.PriceLimit
+	pool.config.PriceLimit = config.PriceLimit
+	pool.config.PriceBump = config.PriceBump
+	pool.config.AccountSlots = config.AccountSlots
+	pool.config.GlobalSlots = config.GlobalSlots
+	pool.config.AccountQueue = config.AccountQueue
+	pool.config.GlobalQueue = config.GlobalQueue
+	pool.config.Lifetime = config.Lifetime
+	pool.mu.Unlock()
+
+	if oldPriceLimit != config.PriceLimit {
+		pool.SetGasPrice(new(big.Int).SetUint64(config.PriceLimit))
+	}
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer))
+
+	log.Info("Transaction pool config updated", "slots", config.GlobalSlots, "queue", config.GlobalQueue)
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (pool *TxPool) Nonce(addr common.Address) uint64 {
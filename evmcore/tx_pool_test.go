@@ -1207,6 +1207,57 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that lowering the global limits of a running pool drops the excessive transactions.
+func TestTransactionPendingGlobalLimitingUpdate(t *testing.T) {
+	t.Parallel()
+
+	// Create the pool to test the limit enforcement with
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = config.AccountSlots * 10
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	// Create a number of test accounts and fund them
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := 0; j < int(config.AccountSlots); j++ {
+			txs = append(txs, transaction(uint64(j), 100000, key))
+		}
+	}
+	pool.AddRemotesSync(txs)
+	if pending, _ := pool.Stats(); pending != len(txs) {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, len(txs))
+	}
+
+	// Lower the limit and verify that it's enforced
+	config.GlobalSlots = config.AccountSlots * 2
+	config.AccountSlots = 1
+	pool.SetConfig(config)
+
+	if pending, _ := pool.Stats(); pending > int(config.GlobalSlots) {
+		t.Fatalf("total pending transactions overflow allowance: %d > %d", pending, config.GlobalSlots)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+
+	// Raise the price limit and verify that cheap transactions are dropped
+	config.PriceLimit = 2
+	pool.SetConfig(config)
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("underpriced transactions aren't dropped: %d pending, %d queued", pending, queued)
+	}
+}
+
 // Test the limit on transaction size is enforced correctly.
 // This test verifies every transaction having allowed size
 // is added to the pool, and longer transactions are rejected.
@@ -20,6 +20,11 @@ func (r Rules) EncodeRLP(w io.Writer) error {
 	rType := uint8(0)
 	if r.Upgrades != (Upgrades{}) {
 		rType = 1
+	}
+	if len(r.UpgradeSchedule) != 0 {
+		rType = 2
+	}
+	if rType > 0 {
 		_, err := w.Write([]byte{rType})
 		if err != nil {
 			return err
@@ -38,6 +43,12 @@ func (r Rules) EncodeRLP(w io.Writer) error {
 			return err
 		}
 	}
+	if rType > 1 {
+		err := rlp.Encode(w, &r.UpgradeSchedule)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -58,7 +69,7 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 			return errors.New("empty typed")
 		}
 		rType = b[0]
-		if rType == 0 || rType > 1 {
+		if rType == 0 || rType > 2 {
 			return errors.New("unknown type")
 		}
 	}
@@ -76,6 +87,12 @@ func (r *Rules) DecodeRLP(s *rlp.Stream) error {
 			return err
 		}
 	}
+	if rType >= 2 {
+		err = s.Decode(&r.UpgradeSchedule)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,10 @@
 package galaxy
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
 
 func UpdateRules(src Rules, diff []byte) (res Rules, err error) {
 	changed := src.Copy()
@@ -14,3 +18,53 @@ func UpdateRules(src Rules, diff []byte) (res Rules, err error) {
 	res.Name = src.Name
 	return
 }
+
+// DiffRules returns the changed fields of rules in the same JSON shape which is accepted by UpdateRules,
+// i.e. UpdateRules(prev, DiffRules(prev, next)) results in next. Returns nil if rules are equal.
+func DiffRules(prev, next Rules) (map[string]interface{}, error) {
+	a, err := rulesToMap(prev)
+	if err != nil {
+		return nil, err
+	}
+	b, err := rulesToMap(next)
+	if err != nil {
+		return nil, err
+	}
+	return diffMaps(a, b), nil
+}
+
+func rulesToMap(r Rules) (map[string]interface{}, error) {
+	b, err := json.Marshal(&r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber() // keep big numbers precise
+	res := make(map[string]interface{})
+	return res, dec.Decode(&res)
+}
+
+func diffMaps(a, b map[string]interface{}) map[string]interface{} {
+	var res map[string]interface{}
+	for k, bv := range b {
+		av := a[k]
+		am, aIsMap := av.(map[string]interface{})
+		bm, bIsMap := bv.(map[string]interface{})
+		if aIsMap && bIsMap {
+			if sub := diffMaps(am, bm); sub != nil {
+				if res == nil {
+					res = make(map[string]interface{})
+				}
+				res[k] = sub
+			}
+			continue
+		}
+		if !reflect.DeepEqual(av, bv) {
+			if res == nil {
+				res = make(map[string]interface{})
+			}
+			res[k] = bv
+		}
+	}
+	return res
+}
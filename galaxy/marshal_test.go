@@ -1,6 +1,7 @@
 package galaxy
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -34,6 +35,32 @@ func TestUpdateRules(t *testing.T) {
 	require.Error(err)
 }
 
+func TestDiffRules(t *testing.T) {
+	require := require.New(t)
+
+	prev := MainNetRules()
+	diff, err := DiffRules(prev, prev)
+	require.NoError(err)
+	require.Nil(diff, "equal rules")
+
+	next := prev.Copy()
+	next.Dag.MaxParents = 5
+	next.Economy.MinGasPrice = new(big.Int).Lsh(big.NewInt(1), 80)
+	next.Upgrades.London = true
+	diff, err = DiffRules(prev, next)
+	require.NoError(err)
+	require.Len(diff, 3)
+	require.Len(diff["Dag"], 1)
+	require.Len(diff["Economy"], 1)
+	require.Len(diff["Upgrades"], 1)
+
+	b, err := json.Marshal(diff)
+	require.NoError(err)
+	got, err := UpdateRules(prev, b)
+	require.NoError(err)
+	require.Equal(next.String(), got.String(), "diff applies back")
+}
+
 func TestMainNetRulesRLP(t *testing.T) {
 	rules := MainNetRules()
 	require := require.New(t)
@@ -120,3 +147,46 @@ func TestGasRulesLLRCompatibilityRLP(t *testing.T) {
 
 	require.Equal(b2, b1)
 }
+
+func TestRulesUpgradeScheduleRLP(t *testing.T) {
+	rules := MainNetRules()
+	rules.Upgrades.Berlin = true
+	require := require.New(t)
+
+	rules, err := UpdateRules(rules, []byte(`{"UpgradeSchedule":[{"Epoch":100,"Upgrades":{"Berlin":true,"London":true},"MinVersion":4295032832}]}`))
+	require.NoError(err)
+	require.Len(rules.UpgradeSchedule, 1)
+
+	b, err := rlp.EncodeToBytes(rules)
+	require.NoError(err)
+
+	decodedRules := Rules{}
+	require.NoError(rlp.DecodeBytes(b, &decodedRules))
+
+	require.Equal(rules.String(), decodedRules.String())
+	require.Equal(rules.UpgradeSchedule, decodedRules.UpgradeSchedule)
+}
+
+func TestRulesActivateUpgrades(t *testing.T) {
+	require := require.New(t)
+
+	rules := MainNetRules()
+	rules.UpgradeSchedule = []UpgradeActivation{
+		{Epoch: 20, Upgrades: Upgrades{Berlin: true, London: true}},
+		{Epoch: 10, Upgrades: Upgrades{Berlin: true}},
+		{Epoch: 30, Upgrades: Upgrades{Berlin: true, London: true, Llr: true}},
+	}
+
+	got := rules.ActivateUpgrades(9)
+	require.Equal(rules.String(), got.String(), "nothing is due")
+
+	got = rules.ActivateUpgrades(20)
+	require.Equal(Upgrades{Berlin: true, London: true}, got.Upgrades, "latest due upgrade wins")
+	require.Equal([]UpgradeActivation{rules.UpgradeSchedule[2]}, got.UpgradeSchedule)
+	require.Equal(Upgrades{}, rules.Upgrades, "source isn't mutated")
+	require.Len(rules.UpgradeSchedule, 3, "source isn't mutated")
+
+	got = got.ActivateUpgrades(35)
+	require.Equal(Upgrades{Berlin: true, London: true, Llr: true}, got.Upgrades)
+	require.Empty(got.UpgradeSchedule)
+}
@@ -3,6 +3,7 @@ package galaxy
 import (
 	"encoding/json"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/deamchain/deam-v2-base/inter/idx"
@@ -47,6 +48,9 @@ type RulesRLP struct {
 	Economy EconomyRules
 
 	Upgrades Upgrades `rlp:"-"`
+
+	// Upgrades announced ahead of time, which are enabled at the beginning of the scheduled epochs
+	UpgradeSchedule []UpgradeActivation `rlp:"-"`
 }
 
 // Rules describes galaxy net.
@@ -117,6 +121,13 @@ type Upgrades struct {
 	Llr    bool
 }
 
+// UpgradeActivation is a set of upgrades scheduled to be enabled at the beginning of an epoch
+type UpgradeActivation struct {
+	Epoch      idx.Epoch
+	Upgrades   Upgrades
+	MinVersion uint64 // minimum node version which supports the upgrades, 0 if any
+}
+
 // EvmChainConfig returns ChainConfig for transactions signing and execution
 func (r Rules) EvmChainConfig() *ethparams.ChainConfig {
 	cfg := *ethparams.AllEthashProtocolChanges
@@ -268,9 +279,46 @@ func FakeShortGasPowerRules() GasPowerRules {
 func (r Rules) Copy() Rules {
 	cp := r
 	cp.Economy.MinGasPrice = new(big.Int).Set(r.Economy.MinGasPrice)
+	if r.UpgradeSchedule != nil {
+		cp.UpgradeSchedule = make([]UpgradeActivation, len(r.UpgradeSchedule))
+		copy(cp.UpgradeSchedule, r.UpgradeSchedule)
+	}
 	return cp
 }
 
+// DueUpgrades returns the scheduled upgrades which are due at the epoch, ordered by activation epoch
+func (r Rules) DueUpgrades(epoch idx.Epoch) []UpgradeActivation {
+	var due []UpgradeActivation
+	for _, u := range r.UpgradeSchedule {
+		if u.Epoch <= epoch {
+			due = append(due, u)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Epoch < due[j].Epoch
+	})
+	return due
+}
+
+// ActivateUpgrades enables the scheduled upgrades which are due at the epoch and removes them from the schedule
+func (r Rules) ActivateUpgrades(epoch idx.Epoch) Rules {
+	due := r.DueUpgrades(epoch)
+	if len(due) == 0 {
+		return r
+	}
+	res := r.Copy()
+	for _, u := range due {
+		res.Upgrades = u.Upgrades
+	}
+	res.UpgradeSchedule = nil
+	for _, u := range r.UpgradeSchedule {
+		if u.Epoch > epoch {
+			res.UpgradeSchedule = append(res.UpgradeSchedule, u)
+		}
+	}
+	return res
+}
+
 func (r Rules) String() string {
 	b, _ := json.Marshal(&r)
 	return string(b)
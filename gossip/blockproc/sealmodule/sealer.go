@@ -95,6 +95,8 @@ func (s *GalaxyEpochsSealer) SealEpoch() (iblockproc.BlockState, iblockproc.Epoc
 	s.bs.CheatersWritten = 0
 	newEpoch := s.es.Epoch + 1
 	s.es.Epoch = newEpoch
+	// scheduled upgrades become active
+	s.es.Rules = s.es.Rules.ActivateUpgrades(newEpoch)
 
 	if s.bs.AdvanceEpochs > 0 {
 		s.bs.AdvanceEpochs--
@@ -0,0 +1,112 @@
+package tokenindex
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-galaxy/logger"
+	"go-galaxy/topicsdb"
+)
+
+// Indexer maintains the index of token transfers and the latest known balances of holders.
+// As blocks are final, the index is never rolled back.
+// Balances are exact only if all the blocks since the token deployment are indexed.
+type Indexer struct {
+	store *Store
+
+	logger.Instance
+}
+
+func New(store *Store) *Indexer {
+	return &Indexer{
+		store:    store,
+		Instance: logger.New(),
+	}
+}
+
+// Store returns the index storage.
+func (x *Indexer) Store() *Store {
+	return x.store
+}
+
+// OnNewLog indexes transfers of the log of the block. Logs must be passed in the order of execution.
+// The block is passed explicitly to not rely on the derived fields of the log,
+// which are filled differently by the block processing and by the BR import.
+// Note: it's possible for logs to get indexed twice by BR and block processing,
+// already indexed transfers are ignored.
+func (x *Indexer) OnNewLog(block idx.Block, l *types.Log) {
+	for _, t := range ParseTransfers(l) {
+		t.Position.Block = block
+		x.onTransfer(t)
+	}
+}
+
+func (x *Indexer) onTransfer(t *Transfer) {
+	if x.store.HasTransfer(t.Position) {
+		return
+	}
+	x.store.SetTransfer(t)
+	if t.From == t.To {
+		return
+	}
+	if t.From != (common.Address{}) {
+		x.addBalance(t.From, t, new(big.Int).Neg(t.Value))
+	}
+	if t.To != (common.Address{}) {
+		x.addBalance(t.To, t, t.Value)
+	}
+}
+
+// addBalance changes the holder's balance of the transferred token.
+// Negative balances may be met if blocks are indexed out of order or not all the token transfers are indexed,
+// they're kept so the balance gets exact once the missing transfers are indexed.
+func (x *Indexer) addBalance(holder common.Address, t *Transfer, diff *big.Int) {
+	h := x.store.GetHolding(holder, t.Token, t.ID)
+	if h == nil {
+		h = &Holding{
+			Token:    t.Token,
+			ID:       t.ID,
+			Standard: t.Standard,
+			Balance:  new(big.Int),
+		}
+	}
+	h.Balance.Add(h.Balance, diff)
+	if h.Balance.Sign() == 0 {
+		x.store.DelHolding(holder, t.Token, t.ID)
+		return
+	}
+	x.store.SetHolding(holder, h)
+}
+
+// IndexBlocks indexes transfers of the blocks range using the logs index.
+func (x *Indexer) IndexBlocks(ctx context.Context, logs *topicsdb.Index, from, to idx.Block) (transfers int, err error) {
+	var found []*types.Log
+	pattern := [][]common.Hash{{}, {Topics.Transfer, Topics.TransferSingle, Topics.TransferBatch}}
+	err = logs.ForEachInBlocks(ctx, from, to, pattern, func(l *types.Log) bool {
+		found = append(found, l)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	// logs index isn't ordered by the order of execution
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		return a.Index < b.Index
+	})
+	for _, l := range found {
+		for _, t := range ParseTransfers(l) {
+			x.onTransfer(t)
+			transfers++
+		}
+	}
+	return transfers, nil
+}
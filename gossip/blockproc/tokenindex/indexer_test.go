@@ -0,0 +1,198 @@
+package tokenindex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func word(v int64) []byte {
+	return common.BigToHash(big.NewInt(v)).Bytes()
+}
+
+func erc20Log(block uint64, index uint, token, from, to common.Address, value int64) *types.Log {
+	return &types.Log{
+		Address:     token,
+		Topics:      []common.Hash{Topics.Transfer, from.Hash(), to.Hash()},
+		Data:        word(value),
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func erc721Log(block uint64, index uint, token, from, to common.Address, id int64) *types.Log {
+	return &types.Log{
+		Address:     token,
+		Topics:      []common.Hash{Topics.Transfer, from.Hash(), to.Hash(), common.BigToHash(big.NewInt(id))},
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func erc1155BatchLog(block uint64, index uint, token, from, to common.Address, ids, values []int64) *types.Log {
+	var data []byte
+	data = append(data, word(64)...)
+	data = append(data, word(int64(64+32+32*len(ids)))...)
+	data = append(data, word(int64(len(ids)))...)
+	for _, id := range ids {
+		data = append(data, word(id)...)
+	}
+	data = append(data, word(int64(len(values)))...)
+	for _, v := range values {
+		data = append(data, word(v)...)
+	}
+	return &types.Log{
+		Address:     token,
+		Topics:      []common.Hash{Topics.TransferBatch, common.Address{0xff}.Hash(), from.Hash(), to.Hash()},
+		Data:        data,
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func TestParseTransfers(t *testing.T) {
+	require := require.New(t)
+	token, alice, bob := common.Address{0xaa}, common.Address{1}, common.Address{2}
+
+	transfers := ParseTransfers(erc20Log(1, 2, token, alice, bob, 100))
+	require.Len(transfers, 1)
+	require.Equal(ERC20, transfers[0].Standard)
+	require.Equal(alice, transfers[0].From)
+	require.Equal(bob, transfers[0].To)
+	require.Equal(int64(100), transfers[0].Value.Int64())
+	require.Equal(Position{Block: 1, LogIndex: 2}, transfers[0].Position)
+
+	transfers = ParseTransfers(erc721Log(1, 0, token, alice, bob, 7))
+	require.Len(transfers, 1)
+	require.Equal(ERC721, transfers[0].Standard)
+	require.Equal(int64(7), transfers[0].ID.Int64())
+	require.Equal(int64(1), transfers[0].Value.Int64())
+
+	transfers = ParseTransfers(erc1155BatchLog(1, 0, token, alice, bob, []int64{3, 4}, []int64{30, 40}))
+	require.Len(transfers, 2)
+	for i, tr := range transfers {
+		require.Equal(ERC1155, tr.Standard)
+		require.Equal(uint32(i), tr.Position.Item)
+		require.Equal(int64(3+i), tr.ID.Int64())
+		require.Equal(int64(30+10*i), tr.Value.Int64())
+	}
+
+	// malformed logs
+	malformed := erc20Log(1, 0, token, alice, bob, 100)
+	malformed.Data = malformed.Data[:31]
+	require.Nil(ParseTransfers(malformed))
+	malformed = erc1155BatchLog(1, 0, token, alice, bob, []int64{3, 4}, []int64{30, 40})
+	malformed.Data = malformed.Data[:len(malformed.Data)-1]
+	require.Nil(ParseTransfers(malformed))
+	malformed = erc1155BatchLog(1, 0, token, alice, bob, []int64{3, 4}, []int64{30})
+	require.Nil(ParseTransfers(malformed))
+	require.Nil(ParseTransfers(&types.Log{}))
+}
+
+func TestIndexer(t *testing.T) {
+	require := require.New(t)
+	x := New(NewStore(memorydb.New()))
+	coin, nft, multi := common.Address{0xa1}, common.Address{0xa2}, common.Address{0xa3}
+	alice, bob := common.Address{1}, common.Address{2}
+
+	logs := []*types.Log{
+		erc20Log(1, 0, coin, common.Address{}, alice, 100),
+		erc721Log(1, 1, nft, common.Address{}, alice, 7),
+		erc20Log(2, 0, coin, alice, bob, 40),
+		erc721Log(2, 1, nft, alice, bob, 7),
+		erc1155BatchLog(3, 0, multi, common.Address{}, bob, []int64{3, 4}, []int64{30, 40}),
+	}
+	for _, l := range logs {
+		x.OnNewLog(idx.Block(l.BlockNumber), l)
+	}
+	// indexing is idempotent
+	x.OnNewLog(2, logs[2])
+
+	balances := func(holder common.Address) map[common.Address]map[int64]int64 {
+		res := map[common.Address]map[int64]int64{}
+		x.Store().ForEachHolding(holder, common.Address{}, new(big.Int), func(h *Holding) bool {
+			if res[h.Token] == nil {
+				res[h.Token] = map[int64]int64{}
+			}
+			res[h.Token][h.ID.Int64()] = h.Balance.Int64()
+			return true
+		})
+		return res
+	}
+	require.Equal(map[common.Address]map[int64]int64{
+		coin: {0: 60},
+	}, balances(alice))
+	require.Equal(map[common.Address]map[int64]int64{
+		coin:  {0: 40},
+		nft:   {7: 1},
+		multi: {3: 30, 4: 40},
+	}, balances(bob))
+	require.Empty(balances(common.Address{}))
+
+	positions := func(iterate func(Position, func(*Transfer) bool), start Position) []Position {
+		var res []Position
+		iterate(start, func(t *Transfer) bool {
+			res = append(res, t.Position)
+			return true
+		})
+		return res
+	}
+	aliceTransfers := func(start Position, fn func(*Transfer) bool) {
+		x.Store().ForEachHolderTransfer(alice, start, fn)
+	}
+	multiTransfers := func(start Position, fn func(*Transfer) bool) {
+		x.Store().ForEachTokenTransfer(multi, start, fn)
+	}
+	require.Equal([]Position{{1, 0, 0}, {1, 1, 0}, {2, 0, 0}, {2, 1, 0}}, positions(aliceTransfers, Position{}))
+	require.Equal([]Position{{2, 0, 0}, {2, 1, 0}}, positions(aliceTransfers, Position{Block: 2}))
+	require.Equal([]Position{{3, 0, 0}, {3, 0, 1}}, positions(multiTransfers, Position{}))
+	require.Equal([]Position{{3, 0, 1}}, positions(multiTransfers, Position{Block: 3, Item: 1}))
+
+	stored := x.Store().GetTransfer(Position{Block: 2})
+	require.NotNil(stored)
+	require.Equal(alice, stored.From)
+	require.Equal(bob, stored.To)
+	require.Equal(int64(40), stored.Value.Int64())
+}
+
+func TestIndexerOutOfOrderBlocks(t *testing.T) {
+	require := require.New(t)
+	x := New(NewStore(memorydb.New()))
+	coin, nft := common.Address{0xa1}, common.Address{0xa2}
+	alice, bob := common.Address{1}, common.Address{2}
+
+	balance := func(holder, token common.Address, id int64) *big.Int {
+		h := x.Store().GetHolding(holder, token, big.NewInt(id))
+		if h == nil {
+			return new(big.Int)
+		}
+		return h.Balance
+	}
+
+	// block 2 is indexed before block 1, e.g. by BR import
+	x.OnNewLog(2, erc20Log(2, 0, coin, alice, bob, 40))
+	x.OnNewLog(2, erc721Log(2, 1, nft, alice, bob, 7))
+	require.Equal(big.NewInt(-40), balance(alice, coin, 0))
+	require.Equal(big.NewInt(-1), balance(alice, nft, 7))
+	require.Equal(big.NewInt(40), balance(bob, coin, 0))
+
+	// negative balances are persisted
+	var negative []int64
+	x.Store().ForEachHolding(alice, common.Address{}, new(big.Int), func(h *Holding) bool {
+		negative = append(negative, h.Balance.Int64())
+		return true
+	})
+	require.Equal([]int64{-40, -1}, negative)
+
+	x.OnNewLog(1, erc20Log(1, 0, coin, common.Address{}, alice, 100))
+	x.OnNewLog(1, erc721Log(1, 1, nft, common.Address{}, alice, 7))
+	require.Equal(big.NewInt(60), balance(alice, coin, 0))
+	require.Equal(big.NewInt(1), balance(bob, nft, 7))
+	// zero balances are erased
+	require.Nil(x.Store().GetHolding(alice, nft, big.NewInt(7)))
+}
@@ -0,0 +1,41 @@
+package tokenindex
+
+import (
+	"github.com/deamchain/deam-v2-base/kvdb"
+	"github.com/deamchain/deam-v2-base/kvdb/table"
+
+	"go-galaxy/logger"
+	"go-galaxy/utils/rlpstore"
+)
+
+// Store is a node persistent storage working over physical key-value database.
+type Store struct {
+	mainDB kvdb.Store
+	table  struct {
+		// Position -> Transfer
+		Transfers kvdb.Store `table:"t"`
+		// holder+Position -> nil
+		HolderTransfers kvdb.Store `table:"h"`
+		// token+Position -> nil
+		TokenTransfers kvdb.Store `table:"k"`
+		// holder+token+ID -> Holding
+		Holdings kvdb.Store `table:"b"`
+	}
+
+	rlp rlpstore.Helper
+
+	logger.Instance
+}
+
+// NewStore creates store over key-value db.
+func NewStore(mainDB kvdb.Store) *Store {
+	s := &Store{
+		mainDB:   mainDB,
+		Instance: logger.New("tokenindex-store"),
+		rlp:      rlpstore.Helper{Instance: logger.New("rlp")},
+	}
+
+	table.MigrateTables(&s.table, s.mainDB)
+
+	return s
+}
@@ -0,0 +1,104 @@
+package tokenindex
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Holding is the latest known balance of a token held by an address.
+// ID is zero for ERC-20 tokens.
+// Balance is negative if the outgoing transfers were indexed before the incoming ones,
+// e.g. if blocks are imported out of order by BR/LLR.
+type Holding struct {
+	Token    common.Address
+	ID       *big.Int
+	Standard Standard
+	Balance  *big.Int
+}
+
+type holdingRLP struct {
+	Standard Standard
+	Balance  *big.Int
+	Negative bool
+}
+
+// EncodeRLP is for RLP serialization, RLP doesn't support negative big integers.
+func (h *Holding) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &holdingRLP{
+		Standard: h.Standard,
+		Balance:  new(big.Int).Abs(h.Balance),
+		Negative: h.Balance.Sign() < 0,
+	})
+}
+
+// DecodeRLP is for RLP deserialization.
+func (h *Holding) DecodeRLP(s *rlp.Stream) error {
+	var enc holdingRLP
+	if err := s.Decode(&enc); err != nil {
+		return err
+	}
+	h.Standard = enc.Standard
+	h.Balance = enc.Balance
+	if enc.Negative {
+		h.Balance.Neg(h.Balance)
+	}
+	return nil
+}
+
+func holdingKey(holder, token common.Address, id *big.Int) []byte {
+	key := make([]byte, 0, 2*common.AddressLength+common.HashLength)
+	key = append(key, holder.Bytes()...)
+	key = append(key, token.Bytes()...)
+	key = append(key, common.BigToHash(id).Bytes()...)
+	return key
+}
+
+// GetHolding returns the holding, or nil if the balance is zero.
+// The returned balance may be negative.
+func (s *Store) GetHolding(holder, token common.Address, id *big.Int) *Holding {
+	h, _ := s.rlp.Get(s.table.Holdings, holdingKey(holder, token, id), &Holding{}).(*Holding)
+	if h != nil {
+		h.Token = token
+		h.ID = id
+	}
+	return h
+}
+
+// SetHolding stores the holding.
+func (s *Store) SetHolding(holder common.Address, h *Holding) {
+	s.rlp.Set(s.table.Holdings, holdingKey(holder, h.Token, h.ID), h)
+}
+
+// DelHolding erases the holding.
+func (s *Store) DelHolding(holder, token common.Address, id *big.Int) {
+	err := s.table.Holdings.Delete(holdingKey(holder, token, id))
+	if err != nil {
+		s.Log.Crit("Failed to erase key-value", "err", err)
+	}
+}
+
+// ForEachHolding iterates holdings of the holder ordered by token and ID, starting from the specified token and ID.
+// Holdings with negative balances are iterated too.
+func (s *Store) ForEachHolding(holder, startToken common.Address, startID *big.Int, onHolding func(*Holding) bool) {
+	start := holdingKey(holder, startToken, startID)[common.AddressLength:]
+	it := s.table.Holdings.NewIterator(holder.Bytes(), start)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != 2*common.AddressLength+common.HashLength {
+			s.Log.Crit("Token holdings index is corrupted", "key", common.Bytes2Hex(key))
+		}
+		h := &Holding{}
+		if err := rlp.DecodeBytes(it.Value(), h); err != nil {
+			s.Log.Crit("Failed to decode rlp", "err", err)
+		}
+		h.Token = common.BytesToAddress(key[common.AddressLength : 2*common.AddressLength])
+		h.ID = new(big.Int).SetBytes(key[2*common.AddressLength:])
+		if !onHolding(h) {
+			break
+		}
+	}
+}
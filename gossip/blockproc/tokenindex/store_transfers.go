@@ -0,0 +1,116 @@
+package tokenindex
+
+import (
+	"math/big"
+
+	"github.com/deamchain/deam-v2-base/common/bigendian"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// PositionSize is size of serialized Position
+const PositionSize = 8 + 4 + 4
+
+// Position is a position of a token transfer in the chain.
+// Item is the index of the transfer within TransferBatch event, zero otherwise.
+type Position struct {
+	Block    idx.Block
+	LogIndex uint32
+	Item     uint32
+}
+
+// Bytes serializes the position so that positions are ordered chronologically.
+func (p Position) Bytes() []byte {
+	b := make([]byte, 0, PositionSize)
+	b = append(b, p.Block.Bytes()...)
+	b = append(b, bigendian.Uint32ToBytes(p.LogIndex)...)
+	b = append(b, bigendian.Uint32ToBytes(p.Item)...)
+	return b
+}
+
+// BytesToPosition deserializes the position.
+func BytesToPosition(b []byte) Position {
+	return Position{
+		Block:    idx.BytesToBlock(b[:8]),
+		LogIndex: bigendian.BytesToUint32(b[8:12]),
+		Item:     bigendian.BytesToUint32(b[12:16]),
+	}
+}
+
+// Transfer is a token transfer. ID is zero for ERC-20 tokens, Value is 1 for ERC-721 tokens.
+type Transfer struct {
+	Position Position `rlp:"-"`
+	Standard Standard
+	Token    common.Address
+	From     common.Address
+	To       common.Address
+	ID       *big.Int
+	Value    *big.Int
+	TxHash   common.Hash
+}
+
+// HasTransfer returns true if the transfer is indexed already.
+func (s *Store) HasTransfer(pos Position) bool {
+	ok, err := s.table.Transfers.Has(pos.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return ok
+}
+
+// GetTransfer returns stored transfer.
+func (s *Store) GetTransfer(pos Position) *Transfer {
+	t, _ := s.rlp.Get(s.table.Transfers, pos.Bytes(), &Transfer{}).(*Transfer)
+	if t != nil {
+		t.Position = pos
+	}
+	return t
+}
+
+// SetTransfer stores the transfer and indexes it by the token and by the sender and recipient.
+// Mints and burns aren't indexed by the zero address.
+func (s *Store) SetTransfer(t *Transfer) {
+	key := t.Position.Bytes()
+	s.rlp.Set(s.table.Transfers, key, t)
+	s.put(s.table.TokenTransfers, append(t.Token.Bytes(), key...))
+	if t.From != (common.Address{}) {
+		s.put(s.table.HolderTransfers, append(t.From.Bytes(), key...))
+	}
+	if t.To != (common.Address{}) {
+		s.put(s.table.HolderTransfers, append(t.To.Bytes(), key...))
+	}
+}
+
+func (s *Store) put(table ethdb.KeyValueWriter, key []byte) {
+	if err := table.Put(key, []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// ForEachHolderTransfer iterates transfers sent or received by the holder, in chronological order, starting from the position.
+func (s *Store) ForEachHolderTransfer(holder common.Address, start Position, onTransfer func(*Transfer) bool) {
+	s.forEachTransfer(s.table.HolderTransfers.NewIterator(holder.Bytes(), start.Bytes()), onTransfer)
+}
+
+// ForEachTokenTransfer iterates transfers of the token, in chronological order, starting from the position.
+func (s *Store) ForEachTokenTransfer(token common.Address, start Position, onTransfer func(*Transfer) bool) {
+	s.forEachTransfer(s.table.TokenTransfers.NewIterator(token.Bytes(), start.Bytes()), onTransfer)
+}
+
+func (s *Store) forEachTransfer(it ethdb.Iterator, onTransfer func(*Transfer) bool) {
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.AddressLength+PositionSize {
+			s.Log.Crit("Token transfers index is corrupted", "key", common.Bytes2Hex(key))
+		}
+		t := s.GetTransfer(BytesToPosition(key[common.AddressLength:]))
+		if t == nil {
+			s.Log.Crit("Token transfer not found", "key", common.Bytes2Hex(key))
+		}
+		if !onTransfer(t) {
+			break
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package tokenindex
+
+import (
+	"math/big"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Standard is a token standard.
+type Standard uint16
+
+const (
+	ERC20   Standard = 20
+	ERC721  Standard = 721
+	ERC1155 Standard = 1155
+)
+
+func (s Standard) String() string {
+	switch s {
+	case ERC20:
+		return "ERC20"
+	case ERC721:
+		return "ERC721"
+	case ERC1155:
+		return "ERC1155"
+	}
+	return "unknown"
+}
+
+// Topics of the token transfer events
+var Topics = struct {
+	Transfer       common.Hash
+	TransferSingle common.Hash
+	TransferBatch  common.Hash
+}{
+	Transfer:       crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+	TransferSingle: crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)")),
+	TransferBatch:  crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])")),
+}
+
+// ParseTransfers decodes token transfers from the log.
+// Returns nil if the log isn't a well-formed ERC-20, ERC-721 or ERC-1155 transfer event.
+func ParseTransfers(l *types.Log) []*Transfer {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	transfer := func(standard Standard, from, to common.Hash, id, value *big.Int) *Transfer {
+		return &Transfer{
+			Standard: standard,
+			Token:    l.Address,
+			From:     common.BytesToAddress(from[12:]),
+			To:       common.BytesToAddress(to[12:]),
+			ID:       id,
+			Value:    value,
+			TxHash:   l.TxHash,
+			Position: Position{
+				Block:    idx.Block(l.BlockNumber),
+				LogIndex: uint32(l.Index),
+			},
+		}
+	}
+	switch l.Topics[0] {
+	case Topics.Transfer:
+		// ERC-20 and ERC-721 share the event signature, they differ by the indexed arguments
+		if len(l.Topics) == 3 && len(l.Data) == 32 {
+			return []*Transfer{transfer(ERC20, l.Topics[1], l.Topics[2], new(big.Int), new(big.Int).SetBytes(l.Data))}
+		}
+		if len(l.Topics) == 4 && len(l.Data) == 0 {
+			return []*Transfer{transfer(ERC721, l.Topics[1], l.Topics[2], l.Topics[3].Big(), big.NewInt(1))}
+		}
+	case Topics.TransferSingle:
+		if len(l.Topics) == 4 && len(l.Data) == 64 {
+			return []*Transfer{transfer(ERC1155, l.Topics[2], l.Topics[3], new(big.Int).SetBytes(l.Data[:32]), new(big.Int).SetBytes(l.Data[32:]))}
+		}
+	case Topics.TransferBatch:
+		if len(l.Topics) != 4 {
+			return nil
+		}
+		ids, ok := decodeUint256Array(l.Data, 0)
+		if !ok {
+			return nil
+		}
+		values, ok := decodeUint256Array(l.Data, 32)
+		if !ok || len(ids) != len(values) {
+			return nil
+		}
+		transfers := make([]*Transfer, len(ids))
+		for i := range ids {
+			transfers[i] = transfer(ERC1155, l.Topics[2], l.Topics[3], ids[i], values[i])
+			transfers[i].Position.Item = uint32(i)
+		}
+		return transfers
+	}
+	return nil
+}
+
+// decodeUint256Array decodes ABI-encoded dynamic uint256[] which offset is written at the head position.
+func decodeUint256Array(data []byte, head int) ([]*big.Int, bool) {
+	word := func(pos uint64) (*big.Int, bool) {
+		if pos > uint64(len(data)) || uint64(len(data))-pos < 32 {
+			return nil, false
+		}
+		return new(big.Int).SetBytes(data[pos : pos+32]), true
+	}
+	offset, ok := word(uint64(head))
+	if !ok || !offset.IsUint64() {
+		return nil, false
+	}
+	length, ok := word(offset.Uint64())
+	if !ok || !length.IsUint64() || length.Uint64() > uint64(len(data))/32 {
+		return nil, false
+	}
+	start := offset.Uint64() + 32
+	arr := make([]*big.Int, length.Uint64())
+	for i := range arr {
+		arr[i], ok = word(start + uint64(i)*32)
+		if !ok {
+			return nil, false
+		}
+	}
+	return arr, true
+}
@@ -1,8 +1,14 @@
 package verwatcher
 
-import "time"
+import (
+	"time"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+)
 
 type Config struct {
 	ShutDownIfNotUpgraded     bool
 	WarningIfNotUpgradedEvery time.Duration
+	// UpgradeWarningEpochs is how many epochs before activation to warn about unsupported scheduled upgrades
+	UpgradeWarningEpochs idx.Epoch
 }
@@ -6,28 +6,38 @@ import (
 	"sync"
 	"time"
 
+	"github.com/deamchain/deam-v2-base/inter/idx"
 	"github.com/ethereum/go-ethereum/core/types"
 
-	"go-galaxy/logger"
+	"go-galaxy/galaxy"
 	"go-galaxy/galaxy/genesis/driver"
 	"go-galaxy/galaxy/genesis/driver/driverpos"
+	"go-galaxy/logger"
 	"go-galaxy/utils/errlock"
 	"go-galaxy/version"
 )
 
+// Reader provides the current epoch and the network rules which will be applied in the next epoch
+type Reader interface {
+	GetEpoch() idx.Epoch
+	GetPendingRules() galaxy.Rules
+}
+
 type VerWarcher struct {
-	cfg   Config
-	store *Store
+	cfg    Config
+	store  *Store
+	reader Reader
 
 	done chan struct{}
 	wg   sync.WaitGroup
 	logger.Instance
 }
 
-func New(cfg Config, store *Store) *VerWarcher {
+func New(cfg Config, store *Store, reader Reader) *VerWarcher {
 	return &VerWarcher{
 		cfg:      cfg,
 		store:    store,
+		reader:   reader,
 		done:     make(chan struct{}),
 		Instance: logger.New(),
 	}
@@ -39,21 +49,45 @@ func (w *VerWarcher) OnNewLog(l *types.Log) {
 	}
 	if l.Topics[0] == driverpos.Topics.UpdateNetworkVersion && len(l.Data) >= 32 {
 		netVersion := new(big.Int).SetBytes(l.Data[24:32]).Uint64()
-		w.store.SetNetworkVersion(netVersion)
-		if netVersion > version.AsU64() {
-			if w.cfg.ShutDownIfNotUpgraded {
-				errlock.Permanent(fmt.Errorf("The network's supported version of %s was activated at block %d.\n"+
-					"Node's current version is %s and a shutdown is required as ShutDownIfNotUpgraded flag was set.\n"+
-					"Please upgrade the node to continue.", version.U64ToString(netVersion), l.BlockNumber, version.AsString()))
-				panic("unreachable")
-			} else if w.store.GetMissedVersion() == 0 {
-				w.store.SetMissedVersion(netVersion)
-			}
+		w.onNetworkVersion(netVersion, l.BlockNumber)
+	}
+}
+
+// OnActivatedUpgrades is called when scheduled upgrades are enabled at a beginning of a new epoch
+func (w *VerWarcher) OnActivatedUpgrades(upgrades []galaxy.UpgradeActivation, block idx.Block) {
+	for _, u := range upgrades {
+		if u.MinVersion > w.store.GetNetworkVersion() {
+			w.onNetworkVersion(u.MinVersion, uint64(block))
 		}
-		w.log()
 	}
 }
 
+func (w *VerWarcher) onNetworkVersion(netVersion uint64, block uint64) {
+	w.store.SetNetworkVersion(netVersion)
+	if netVersion > version.AsU64() {
+		if w.cfg.ShutDownIfNotUpgraded {
+			errlock.Permanent(fmt.Errorf("The network's supported version of %s was activated at block %d.\n"+
+				"Node's current version is %s and a shutdown is required as ShutDownIfNotUpgraded flag was set.\n"+
+				"Please upgrade the node to continue.", version.U64ToString(netVersion), block, version.AsString()))
+			panic("unreachable")
+		} else if w.store.GetMissedVersion() == 0 {
+			w.store.SetMissedVersion(netVersion)
+		}
+	}
+	w.log()
+}
+
+// unsupportedUpgrades returns the scheduled upgrades which aren't supported by the current node version
+func unsupportedUpgrades(rules galaxy.Rules) []galaxy.UpgradeActivation {
+	var res []galaxy.UpgradeActivation
+	for _, u := range rules.UpgradeSchedule {
+		if u.MinVersion > version.AsU64() {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
 func (w *VerWarcher) log() {
 	if w.cfg.WarningIfNotUpgradedEvery == 0 {
 		return
@@ -65,6 +99,16 @@ func (w *VerWarcher) log() {
 		w.Log.Warn(fmt.Sprintf("Node's state is dirty because node was upgraded after the network upgrade %s was activated. "+
 			"Please re-sync the chain data to continue.", version.U64ToString(w.store.GetMissedVersion())))
 	}
+	if w.reader == nil || w.cfg.UpgradeWarningEpochs == 0 {
+		return
+	}
+	epoch := w.reader.GetEpoch()
+	for _, u := range unsupportedUpgrades(w.reader.GetPendingRules()) {
+		if u.Epoch <= epoch+w.cfg.UpgradeWarningEpochs {
+			w.Log.Warn(fmt.Sprintf("Network upgrade %s is scheduled at epoch %d, current epoch is %d. Current node version is %s. "+
+				"Please upgrade your node before the activation.", version.U64ToString(u.MinVersion), u.Epoch, epoch, version.AsString()))
+		}
+	}
 }
 
 func (w *VerWarcher) Start() {
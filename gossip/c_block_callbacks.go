@@ -17,8 +17,10 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"go.opentelemetry.io/otel/attribute"
 
 	"go-galaxy/evmcore"
+	"go-galaxy/gossip/blockproc/tokenindex"
 	"go-galaxy/gossip/blockproc/verwatcher"
 	"go-galaxy/gossip/emitter"
 	"go-galaxy/gossip/evmstore"
@@ -26,6 +28,7 @@ import (
 	"go-galaxy/inter"
 	"go-galaxy/inter/iblockproc"
 	"go-galaxy/galaxy"
+	"go-galaxy/tracing"
 	"go-galaxy/utils"
 )
 
@@ -76,9 +79,13 @@ func (s *Service) GetConsensusCallbacks() lachesis.ConsensusCallbacks {
 			s.store,
 			s.blockProcModules,
 			s.config.TxIndex,
+			s.config.AddressIndex,
+			s.EthAPI.signer,
 			&s.feed,
 			&s.emitters,
 			s.verWatcher,
+			s.tokenIndexer,
+			s.txTracker,
 		),
 	}
 }
@@ -92,14 +99,21 @@ func consensusCallbackBeginBlockFn(
 	store *Store,
 	blockProc BlockProc,
 	txIndex bool,
+	addressIndex bool,
+	txSigner types.Signer,
 	feed *ServiceFeed,
 	emitters *[]*emitter.Emitter,
 	verWatcher *verwatcher.VerWarcher,
+	tokenIndexer *tokenindex.Indexer,
+	txTracker *txTracker,
 ) lachesis.BeginBlockFn {
 	return func(cBlock *lachesis.Block) lachesis.BlockCallbacks {
 		wg.Wait()
 		start := time.Now()
 
+		blockSpan := tracing.StartBlock(cBlock.Atropos)
+		decisionSpan := tracing.StartChild(blockSpan, "lachesis.decision")
+
 		// Note: take copies to avoid race conditions with API calls
 		bs := store.GetBlockState().Copy()
 		es := store.GetEpochState().Copy()
@@ -193,6 +207,8 @@ func consensusCallbackBeginBlockFn(
 				}
 			},
 			EndBlock: func() (newValidators *pos.Validators) {
+				decisionSpan.SetAttributes(attribute.Int("events", confirmedEvents.Len()))
+				decisionSpan.End()
 				if atroposTime <= bs.LastBlock.Time {
 					atroposTime = bs.LastBlock.Time + 1
 				}
@@ -230,6 +246,8 @@ func consensusCallbackBeginBlockFn(
 					// save the latest block state even if block is skipped
 					store.SetBlockEpochState(bs, es)
 					log.Debug("Frame is skipped", "atropos", cBlock.Atropos.String())
+					blockSpan.SetAttributes(attribute.Bool("skipped", true))
+					blockSpan.End()
 					return nil
 				}
 
@@ -242,6 +260,9 @@ func consensusCallbackBeginBlockFn(
 					if verWatcher != nil {
 						verWatcher.OnNewLog(l)
 					}
+					if tokenIndexer != nil {
+						tokenIndexer.OnNewLog(blockCtx.Idx, l)
+					}
 					sfcapi.OnNewLog(store.sfcapi, l)
 				}
 
@@ -272,15 +293,26 @@ func consensusCallbackBeginBlockFn(
 
 				// Seal epoch if requested
 				if sealing {
+					pendingRules := es.Rules
+					if bs.DirtyRules != nil {
+						pendingRules = *bs.DirtyRules
+					}
 					sealer.Update(bs, es)
 					bs, es = sealer.SealEpoch() // TODO: refactor to not mutate the bs, it is unclear
 					store.SetBlockEpochState(bs, es)
+					if verWatcher != nil {
+						verWatcher.OnActivatedUpgrades(pendingRules.DueUpgrades(es.Epoch), blockCtx.Idx)
+					}
 					newValidators = es.Validators
 					txListener.Update(bs, es)
 				}
 
 				// At this point, newValidators may be returned and the rest of the code may be executed in a parallel thread
 				blockFn := func() {
+					defer blockSpan.End()
+					blockSpan.SetAttributes(attribute.Int64("block", int64(blockCtx.Idx)))
+					execSpan := tracing.StartChild(blockSpan, "evm.execute")
+
 					// Execute post-internal transactions
 					internalTxs := blockProc.PostTxTransactor.PopInternalTxs(blockCtx, bs, es, sealing, statedb)
 					internalReceipts := evmProcessor.Execute(internalTxs, true)
@@ -312,6 +344,9 @@ func consensusCallbackBeginBlockFn(
 					_ = evmProcessor.Execute(txs, false)
 
 					evmBlock, skippedTxs, allReceipts := evmProcessor.Finalize()
+					execSpan.SetAttributes(attribute.Int("txs", len(evmBlock.Transactions)), attribute.Int("skipped", len(skippedTxs)))
+					execSpan.End()
+					commitSpan := tracing.StartChild(blockSpan, "commit")
 					block.SkippedTxs = skippedTxs
 					block.Root = hash.Hash(evmBlock.Root)
 					block.GasUsed = evmBlock.GasUsed
@@ -369,6 +404,10 @@ func consensusCallbackBeginBlockFn(
 								store.evm.IndexLogs(r.Logs...)
 							}
 						}
+						store.SetReceiptsDigest(blockCtx.Idx, CalcReceiptsDigest(allReceipts))
+					}
+					if addressIndex {
+						store.evm.IndexAddressTxs(blockCtx.Idx, evmBlock.Transactions, txSigner)
 					}
 					for _, tx := range append(preInternalTxs, internalTxs...) {
 						store.evm.SetTx(tx.Hash(), tx)
@@ -384,6 +423,8 @@ func consensusCallbackBeginBlockFn(
 					store.SetBlockIndex(block.Atropos, blockCtx.Idx)
 					store.SetBlockEpochState(bs, es)
 					store.EvmStore().SetCachedEvmBlock(blockCtx.Idx, evmBlock)
+					// txs of the spilled events are dropped too
+					txTracker.onEventsConfirmed(hash.Events(confirmedEvents))
 					updateLowestBlockToFill(blockCtx.Idx, store)
 					updateLowestEpochToFill(es.Epoch, store)
 
@@ -420,6 +461,12 @@ func consensusCallbackBeginBlockFn(
 					}
 
 					store.commitEVM(false)
+					commitSpan.End()
+					if tracing.Enabled() {
+						for _, tx := range evmBlock.Transactions {
+							tracing.ConfirmTx(tx.Hash(), blockSpan)
+						}
+					}
 					// Update the metrics touched during block commit
 					accountCommitTimer.Update(statedb.AccountCommits)
 					storageCommitTimer.Update(statedb.StorageCommits)
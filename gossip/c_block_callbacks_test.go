@@ -1,14 +1,19 @@
 package gossip
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 
+	"go-galaxy/gossip/blockproc/tokenindex"
 	"go-galaxy/logger"
 	"go-galaxy/utils"
 )
@@ -65,3 +70,73 @@ func TestConsensusCallback(t *testing.T) {
 	}
 
 }
+
+func TestTokenIndexBlockProcessing(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	config := DefaultConfig(cachescale.Identity)
+	config.TokenIndex = true
+	env := newTestEnvWithConfig(config, 2, 1)
+	defer env.Close()
+
+	// the token emits Transfer(from, to, value) of the call data
+	runtime := "604035600052602035600035" + "7f" + tokenindex.Topics.Transfer.Hex()[2:] + "60206000a300"
+	rr, err := env.ApplyTxs(nextEpoch, env.Contract(1, utils.ToUnit(0), "0x6033600c60003960336000f3"+runtime))
+	require.NoError(err)
+	token := rr[0].ContractAddress
+
+	transfer := func(from, to common.Address, value int64) *types.Transaction {
+		sender := env.Address(1)
+		nonce, _ := env.PendingNonceAt(nil, sender)
+		env.incNonce(sender)
+		data := append(append(from.Hash().Bytes(), to.Hash().Bytes()...), common.BigToHash(big.NewInt(value)).Bytes()...)
+		tx := types.NewTransaction(nonce, token, common.Big0, maxGasLimit, env.store.GetRules().Economy.MinGasPrice, data)
+		tx, err := types.SignTx(tx, env.EthAPI.signer, env.privateKey(1))
+		require.NoError(err)
+		return tx
+	}
+
+	// every transfer is the first log of its block
+	alice, bob := common.Address{1}, common.Address{2}
+	var blocks []idx.Block
+	for _, tx := range []*types.Transaction{
+		transfer(common.Address{}, alice, 100),
+		transfer(alice, bob, 40),
+		transfer(bob, alice, 10),
+	} {
+		rr, err := env.ApplyTxs(sameEpoch, tx)
+		require.NoError(err)
+		require.Len(rr[0].Logs, 1)
+		blocks = append(blocks, idx.Block(rr[0].BlockNumber.Uint64()))
+	}
+
+	var indexed []idx.Block
+	env.tokenIndexer.Store().ForEachTokenTransfer(token, tokenindex.Position{}, func(t *tokenindex.Transfer) bool {
+		require.Equal(uint32(0), t.Position.LogIndex)
+		indexed = append(indexed, t.Position.Block)
+		return true
+	})
+	require.Equal(blocks, indexed)
+
+	balance := func(holder common.Address) int64 {
+		h := env.tokenIndexer.Store().GetHolding(holder, token, new(big.Int))
+		require.NotNil(h)
+		return h.Balance.Int64()
+	}
+	require.Equal(int64(70), balance(alice))
+	require.Equal(int64(30), balance(bob))
+
+	// logs of the same blocks received by BR aren't indexed twice
+	for _, n := range blocks {
+		receipts, err := env.EthAPI.GetReceiptsByNumber(context.Background(), rpc.BlockNumber(n))
+		require.NoError(err)
+		for _, r := range receipts {
+			for _, l := range r.Logs {
+				env.tokenIndexer.OnNewLog(n, l)
+			}
+		}
+	}
+	require.Equal(int64(70), balance(alice))
+	require.Equal(int64(30), balance(bob))
+}
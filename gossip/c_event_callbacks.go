@@ -16,6 +16,7 @@ import (
 	"go-galaxy/gossip/emitter"
 	"go-galaxy/inter"
 	"go-galaxy/inter/iblockproc"
+	"go-galaxy/tracing"
 	"go-galaxy/utils/concurrent"
 )
 
@@ -182,6 +183,9 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 	atomic.StoreUint32(&s.eventBusyFlag, 1)
 	defer atomic.StoreUint32(&s.eventBusyFlag, 0)
 
+	span := tracing.StartEventStage(e.ID(), "processEvent")
+	defer span.End()
+
 	// repeat the checks under the mutex which may depend on volatile data
 	if s.store.HasEvent(e.ID()) {
 		return eventcheck.ErrAlreadyConnectedEvent
@@ -215,6 +219,7 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 	if err != nil {
 		return err
 	}
+	s.txTracker.onNewEvent(e)
 
 	newEpoch := s.store.GetEpoch()
 
@@ -269,6 +274,9 @@ func (s *Service) commit(epochSealing bool) {
 	if !s.store.cfg.EVM.Cache.TrieDirtyDisabled {
 		s.store.commitEVM(true)
 	}
+	if epochSealing {
+		s.triggerFreezing()
+	}
 	_ = s.store.Commit()
 	if epochSealing {
 		s.store.CaptureEvmKvdbSnapshot()
@@ -88,6 +88,20 @@ func (s *Service) ProcessBlockVotes(bvs inter.LlrSignedBlockVotes) error {
 	return err
 }
 
+// ImportBlockVotes checks and processes block votes which don't come from peers, e.g. read from a file.
+func (s *Service) ImportBlockVotes(bvs inter.LlrSignedBlockVotes) error {
+	if s.store.HasBlockVotes(bvs.Val.Epoch, bvs.Val.LastBlock(), bvs.Signed.Locator.ID()) {
+		return eventcheck.ErrAlreadyProcessedBVs
+	}
+	if err := s.checkers.Basiccheck.ValidateBVs(bvs); err != nil {
+		return err
+	}
+	if err := s.checkers.Heavycheck.ValidateBVs(bvs); err != nil {
+		return err
+	}
+	return s.ProcessBlockVotes(bvs)
+}
+
 func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
 	// engineMu should NOT be locked here
 	if s.store.HasBlock(br.Idx) {
@@ -126,6 +140,9 @@ func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
 			BlockOffset: uint32(i),
 		})
 	}
+	if s.config.AddressIndex {
+		s.store.EvmStore().IndexAddressTxs(br.Idx, br.Txs, s.EthAPI.signer)
+	}
 	s.store.SetBlock(br.Idx, &inter.Block{
 		Time:        br.Time,
 		Atropos:     br.Atropos,
@@ -147,6 +164,15 @@ func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
 			}
 		}
 	}
+	if s.tokenIndexer != nil {
+		// wait for the block processing to not race over the balances
+		s.blockProcWg.Wait()
+		for _, r := range br.Receipts {
+			for _, l := range r.Logs {
+				s.tokenIndexer.OnNewLog(br.Idx, l)
+			}
+		}
+	}
 	updateLowestBlockToFill(br.Idx, s.store)
 	s.mayCommit(false)
 
@@ -211,6 +237,20 @@ func (s *Service) ProcessEpochVote(ev inter.LlrSignedEpochVote) error {
 	return err
 }
 
+// ImportEpochVote checks and processes an epoch vote which doesn't come from peers, e.g. read from a file.
+func (s *Service) ImportEpochVote(ev inter.LlrSignedEpochVote) error {
+	if s.store.HasEpochVote(ev.Val.Epoch, ev.Signed.Locator.ID()) {
+		return eventcheck.ErrAlreadyProcessedEV
+	}
+	if err := s.checkers.Basiccheck.ValidateEV(ev); err != nil {
+		return err
+	}
+	if err := s.checkers.Heavycheck.ValidateEV(ev); err != nil {
+		return err
+	}
+	return s.ProcessEpochVote(ev)
+}
+
 func (s *Service) ProcessFullEpochRecord(er ier.LlrIdxFullEpochRecord) error {
 	// engineMu should NOT be locked here
 	if s.store.HasHistoryBlockEpochState(er.Idx) {
@@ -131,6 +131,10 @@ func (m testConfirmedEventsModule) Start(bs iblockproc.BlockState, es iblockproc
 }
 
 func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
+	return newTestEnvWithConfig(DefaultConfig(cachescale.Identity), firstEpoch, validatorsNum)
+}
+
+func newTestEnvWithConfig(config Config, firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
 	genStore := makegenesis.FakeGenesisStore(firstEpoch, validatorsNum, utils.ToUnit(genesisBalance), utils.ToUnit(genesisStake))
 	genesis := genStore.GetGenesis()
 
@@ -155,7 +159,7 @@ func newTestEnv(firstEpoch idx.Epoch, validatorsNum idx.Validator) *testEnv {
 
 	// create the service
 	txPool := &dummyTxPool{}
-	env.Service, err = newService(DefaultConfig(cachescale.Identity), store, blockProc, engine, vecClock, func(_ evmcore.StateReader) TxPool {
+	env.Service, err = newService(config, store, blockProc, engine, vecClock, func(_ evmcore.StateReader) TxPool {
 		return txPool
 	})
 	if err != nil {
@@ -81,8 +81,24 @@ type (
 
 		AllowSnapsync bool
 
+		// HistoryBackfill enables downloading of the missing historical blocks, receipts and txs
+		// (e.g. skipped by snapsync) from peers in background, while new events are processed.
+		HistoryBackfill bool
+
+		// EvmPruningPeriod enables online pruning of the stale EVM state, which is started in background
+		// every period while the node runs. Zero value disables it.
+		EvmPruningPeriod time.Duration
+
 		TxIndex bool // Whether to enable indexing transactions and receipts or not
 
+		// AddressIndex enables indexing transactions by their senders and recipients.
+		// Blocks processed before enabling it are indexed by 'galaxy index addresses' command.
+		AddressIndex bool
+
+		// TokenIndex enables indexing ERC-20/721/1155 token transfers and balances of holders.
+		// Blocks processed before enabling it are indexed by 'galaxy index tokens' command.
+		TokenIndex bool
+
 		// Protocol options
 		Protocol ProtocolConfig
 
@@ -119,6 +135,14 @@ type (
 		BlockEpochStateNum int
 	}
 
+	// FreezerConfig is a config for the flat-file store of final events, blocks and receipts.
+	FreezerConfig struct {
+		// Directory of the freezer files. Empty value disables the freezer.
+		Dir string
+		// Number of the latest sealed epochs, which are kept in the key-value DB.
+		KeepEpochs idx.Epoch
+	}
+
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
@@ -126,6 +150,8 @@ type (
 		EVM                 evmstore.StoreConfig
 		MaxNonFlushedSize   int
 		MaxNonFlushedPeriod time.Duration
+		// Freezer is a config for the flat-file store of final data
+		Freezer FreezerConfig
 	}
 )
 
@@ -213,6 +239,7 @@ func DefaultConfig(scale cachescale.Func) Config {
 		VersionWatcher: verwatcher.Config{
 			ShutDownIfNotUpgraded:     false,
 			WarningIfNotUpgradedEvery: 5 * time.Second,
+			UpgradeWarningEpochs:      1000,
 		},
 		RPCBlockExt: true,
 
@@ -274,6 +301,9 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 		EVM:                 evmstore.DefaultStoreConfig(scale),
 		MaxNonFlushedSize:   17*opt.MiB + scale.I(5*opt.MiB),
 		MaxNonFlushedPeriod: 30 * time.Minute,
+		Freezer: FreezerConfig{
+			KeepEpochs: 4,
+		},
 	}
 }
 
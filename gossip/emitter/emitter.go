@@ -89,10 +89,15 @@ func NewEmitter(
 	config Config,
 	world World,
 ) *Emitter {
+	if world.Clock == nil {
+		world.Clock = systemClock{}
+	}
+	if world.Rand == nil {
+		world.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
 	// Randomize event time to decrease chance of 2 parallel instances emitting event at the same time
 	// It increases the chance of detecting parallel instances
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(r)
+	config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(world.Rand)
 
 	txTime, _ := lru.New(TxTimeBufferSize)
 	return &Emitter{
@@ -107,9 +112,10 @@ func NewEmitter(
 
 // init emitter without starting events emission
 func (em *Emitter) init() {
-	em.syncStatus.startup = time.Now()
-	em.syncStatus.lastConnected = time.Now()
-	em.syncStatus.p2pSynced = time.Now()
+	now := em.world.Clock.Now()
+	em.syncStatus.startup = now
+	em.syncStatus.lastConnected = now
+	em.syncStatus.p2pSynced = now
 	validators, epoch := em.world.GetEpochValidators()
 	em.OnNewEpoch(validators, epoch)
 
@@ -176,15 +182,40 @@ func (em *Emitter) Stop() {
 	em.busyRate.Stop()
 }
 
+// SetConfig updates the emission params of a running emitter.
+// Validator identity, published version and files of the previous actions aren't changed.
+func (em *Emitter) SetConfig(config Config) {
+	em.world.Lock()
+	defer em.world.Unlock()
+
+	config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(em.world.Rand)
+	config.VersionToPublish = em.config.VersionToPublish
+	config.Validator = em.config.Validator
+	config.PrevEmittedEventFile = em.config.PrevEmittedEventFile
+	config.PrevBlockVotesFile = em.config.PrevBlockVotesFile
+	config.PrevEpochVoteFile = em.config.PrevEpochVoteFile
+	em.config = config
+	em.intervals = config.EmitIntervals
+
+	// recalculate the params derived from the config
+	if em.validators != nil {
+		em.maxParents = em.calcMaxParents()
+		if em.isValidator() {
+			em.recountValidators(em.validators)
+		}
+	}
+	em.cache.sortedTxs = nil
+}
+
 func (em *Emitter) tick() {
 	// track synced time
 	if em.world.PeersNum() == 0 {
 		// connected time ~= last time when it's true that "not connected yet"
-		em.syncStatus.lastConnected = time.Now()
+		em.syncStatus.lastConnected = em.world.Clock.Now()
 	}
 	if !em.world.IsSynced() {
 		// synced time ~= last time when it's true that "not synced yet"
-		em.syncStatus.p2pSynced = time.Now()
+		em.syncStatus.p2pSynced = em.world.Clock.Now()
 	}
 	if em.idle() {
 		em.busyRate.Mark(0)
@@ -197,7 +228,7 @@ func (em *Emitter) tick() {
 
 	em.recheckChallenges()
 	em.recheckIdleTime()
-	if time.Since(em.prevEmittedAtTime) >= em.intervals.Min {
+	if em.world.Clock.Now().Sub(em.prevEmittedAtTime) >= em.intervals.Min {
 		_, _ = em.EmitEvent()
 	}
 }
@@ -208,7 +239,7 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 	if em.cache.sortedTxs != nil &&
 		em.cache.poolBlock == em.world.GetLatestBlockIndex() &&
 		em.cache.poolCount == poolCount &&
-		time.Since(em.cache.poolTime) < em.config.TxsCacheInvalidation {
+		em.world.Clock.Now().Sub(em.cache.poolTime) < em.config.TxsCacheInvalidation {
 		return em.cache.sortedTxs.Copy()
 	}
 	// Build the cache
@@ -227,7 +258,7 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 	em.cache.sortedTxs = sortedTxs
 	em.cache.poolCount = poolCount
 	em.cache.poolBlock = em.world.GetLatestBlockIndex()
-	em.cache.poolTime = time.Now()
+	em.cache.poolTime = em.world.Clock.Now()
 	return sortedTxs.Copy()
 }
 
@@ -266,14 +297,14 @@ func (em *Emitter) EmitEvent() (*inter.EventPayload, error) {
 	// broadcast the event
 	em.world.Broadcast(e)
 
-	em.prevEmittedAtTime = time.Now() // record time after connecting, to add the event processing time"
+	em.prevEmittedAtTime = em.world.Clock.Now() // record time after connecting, to add the event processing time"
 	em.prevEmittedAtBlock = em.world.GetLatestBlockIndex()
 
 	// metrics
 	if tracing.Enabled() {
 		for _, t := range e.Txs() {
 			span := tracing.CheckTx(t.Hash(), "Emitter.EmitEvent()")
-			defer span.Finish()
+			defer span.End()
 		}
 	}
 
@@ -354,7 +385,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 
 	mutEvent.SetParents(parents)
 	mutEvent.SetLamport(maxLamport + 1)
-	mutEvent.SetCreationTime(inter.MaxTimestamp(inter.Timestamp(time.Now().UnixNano()), selfParentTime+1))
+	mutEvent.SetCreationTime(inter.MaxTimestamp(inter.Timestamp(em.world.Clock.Now().UnixNano()), selfParentTime+1))
 
 	// add LLR votes
 	em.addLlrEpochVote(mutEvent)
@@ -1,8 +1,6 @@
 package emitter
 
 import (
-	"math/rand"
-
 	"github.com/deamchain/deam-v2-base/hash"
 	"github.com/deamchain/deam-v2-base/inter/idx"
 
@@ -104,5 +102,5 @@ func (em *Emitter) skipLlrEpochVote() bool {
 		return false
 	}
 	// otherwise, poor validators have a small chance to vote
-	return rand.Intn(30) != 0
+	return em.world.Rand.Intn(30) != 0
 }
@@ -104,4 +104,21 @@ func TestEmitter(t *testing.T) {
 	t.Run("tick", func(t *testing.T) {
 		em.tick()
 	})
+
+	t.Run("SetConfig", func(t *testing.T) {
+		require := require.New(t)
+
+		newCfg := DefaultConfig()
+		newCfg.EmitIntervals.Min = 2 * cfg.EmitIntervals.Min
+		newCfg.MaxParents = 3
+		newCfg.MaxTxsPerAddress = 1
+		em.SetConfig(newCfg)
+
+		require.Equal(newCfg.EmitIntervals.Min, em.intervals.Min)
+		require.Equal(idx.Event(3), em.maxParents)
+		require.Equal(1, em.config.MaxTxsPerAddress)
+		require.Equal(cfg.Validator.ID, em.config.Validator.ID, "validator isn't changed")
+
+		em.tick()
+	})
 }
@@ -12,18 +12,23 @@ import (
 	"go-galaxy/utils/adapters/vecmt2dagidx"
 )
 
-// OnNewEpoch should be called after each epoch change, and on startup
-func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch) {
-	em.maxParents = em.config.MaxParents
+func (em *Emitter) calcMaxParents() idx.Event {
+	maxParents := em.config.MaxParents
 	rules := em.world.GetRules()
-	if em.maxParents == 0 {
-		em.maxParents = rules.Dag.MaxParents
+	if maxParents == 0 {
+		maxParents = rules.Dag.MaxParents
 	}
-	if em.maxParents > rules.Dag.MaxParents {
-		em.maxParents = rules.Dag.MaxParents
+	if maxParents > rules.Dag.MaxParents {
+		maxParents = rules.Dag.MaxParents
 	}
+	return maxParents
+}
+
+// OnNewEpoch should be called after each epoch change, and on startup
+func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch) {
+	em.maxParents = em.calcMaxParents()
 	if em.validators != nil && em.isValidator() && !em.validators.Exists(em.config.Validator.ID) && newValidators.Exists(em.config.Validator.ID) {
-		em.syncStatus.becameValidator = time.Now()
+		em.syncStatus.becameValidator = em.world.Clock.Now()
 	}
 
 	em.validators, em.epoch = newValidators, newEpoch
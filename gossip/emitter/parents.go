@@ -1,6 +1,7 @@
 package emitter
 
 import (
+	"math/rand"
 	"time"
 
 	"github.com/deamchain/deam-v2-base/emitter/ancestor"
@@ -8,21 +9,43 @@ import (
 	"github.com/deamchain/deam-v2-base/inter/idx"
 )
 
+// seededStrategy shuffles the options with the world's Rand, instead of the map iteration order
+// which is used by ancestor.ChooseParents, so that the choice among equal options is reproducible
+type seededStrategy struct {
+	ancestor.SearchStrategy
+	r *rand.Rand
+}
+
+func (st seededStrategy) Choose(existingParents hash.Events, options hash.Events) int {
+	shuffled := options.Copy()
+	hash.OrderedEvents(shuffled).ByEpochAndLamport()
+	st.r.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	best := shuffled[st.SearchStrategy.Choose(existingParents, shuffled)]
+	for i, option := range options {
+		if option == best {
+			return i
+		}
+	}
+	return 0
+}
+
 // buildSearchStrategies returns a strategy for each parent search
 func (em *Emitter) buildSearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy {
 	strategies := make([]ancestor.SearchStrategy, 0, maxParents)
 	if maxParents == 0 {
 		return strategies
 	}
-	payloadStrategy := em.payloadIndexer.SearchStrategy()
+	payloadStrategy := seededStrategy{em.payloadIndexer.SearchStrategy(), em.world.Rand}
 	for idx.Event(len(strategies)) < 1 {
 		strategies = append(strategies, payloadStrategy)
 	}
-	randStrategy := ancestor.NewRandomStrategy(nil)
+	randStrategy := seededStrategy{ancestor.NewRandomStrategy(em.world.Rand), em.world.Rand}
 	for idx.Event(len(strategies)) < maxParents/2 {
 		strategies = append(strategies, randStrategy)
 	}
-	quorumStrategy := em.quorumIndexer.SearchStrategy()
+	quorumStrategy := seededStrategy{em.quorumIndexer.SearchStrategy(), em.world.Rand}
 	for idx.Event(len(strategies)) < maxParents {
 		strategies = append(strategies, quorumStrategy)
 	}
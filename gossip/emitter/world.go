@@ -2,7 +2,9 @@ package emitter
 
 import (
 	"errors"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/deamchain/deam-v2-base/hash"
 	"github.com/deamchain/deam-v2-base/inter/idx"
@@ -43,15 +45,32 @@ type (
 	Signer   valkeystore.SignerI
 	TxSigner types.Signer
 
+	// Clock is a source of the current time
+	Clock interface {
+		Now() time.Time
+	}
+
 	// World is an emitter's environment
 	World struct {
 		External
 		TxPool   TxPool
 		Signer   valkeystore.SignerI
 		TxSigner types.Signer
+		// Clock and Rand are optional, the system clock and a time-seeded source are used by default.
+		// Clock has to be safe for concurrent use unless emitting is driven by the caller,
+		// Rand is used only under the world's lock.
+		Clock Clock
+		Rand  *rand.Rand
 	}
 )
 
+// systemClock is the default Clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
 type LlrReader interface {
 	GetLowestBlockToDecide() idx.Block
 	GetLastBV(id idx.ValidatorID) *idx.Block
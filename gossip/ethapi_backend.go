@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/deamchain/deam-v2-base/hash"
 	"github.com/deamchain/deam-v2-base/inter/idx"
@@ -23,6 +24,7 @@ import (
 
 	"go-galaxy/ethapi"
 	"go-galaxy/evmcore"
+	"go-galaxy/gossip/blockproc/tokenindex"
 	"go-galaxy/gossip/evmstore"
 	"go-galaxy/gossip/sfcapi"
 	"go-galaxy/inter"
@@ -290,6 +292,39 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 	return receipts, nil
 }
 
+// GetReceiptsDigest returns the receipts root and the logs bloom of a block.
+// The values are calculated from receipts once and stored.
+// Nothing is stored if receipts of a block with txs aren't indexed yet.
+func (b *EthAPIBackend) GetReceiptsDigest(ctx context.Context, number rpc.BlockNumber) (common.Hash, types.Bloom, error) {
+	if !b.svc.config.TxIndex {
+		return common.Hash{}, types.Bloom{}, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+	}
+
+	if number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(b.state.CurrentHeader().Number.Uint64())
+	}
+
+	n := idx.Block(number)
+	if digest := b.svc.store.GetReceiptsDigest(n); digest != nil {
+		return digest.ReceiptsRoot, digest.Bloom, nil
+	}
+	block := b.svc.store.GetBlock(n)
+	if block == nil {
+		return common.Hash{}, types.Bloom{}, errors.New("block not found")
+	}
+	digest := b.svc.store.calcStoredReceiptsDigest(n, block)
+	if digest == nil {
+		if len(b.svc.store.GetBlockTxs(n, block)) != 0 {
+			return common.Hash{}, types.Bloom{}, fmt.Errorf("receipts of block %d not found", n)
+		}
+		// no receipts are stored for blocks without txs
+		empty := CalcReceiptsDigest(nil)
+		digest = &empty
+	}
+	b.svc.store.SetReceiptsDigest(n, *digest)
+	return digest.ReceiptsRoot, digest.Bloom, nil
+}
+
 // GetReceipts retrieves the receipts for all transactions in a given block.
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, block common.Hash) (types.Receipts, error) {
 	number := b.svc.store.GetBlockIndex(hash.Event(block))
@@ -369,6 +404,76 @@ func (b *EthAPIBackend) GetTxPosition(txHash common.Hash) *evmstore.TxPosition {
 	return b.svc.store.evm.GetTxPosition(txHash)
 }
 
+// ForEachAddressTx iterates the transactions sent from or to the address, in the order of execution,
+// starting from the specified block and block offset.
+func (b *EthAPIBackend) ForEachAddressTx(ctx context.Context, addr common.Address, start idx.Block, startOffset uint32, onTx func(evmstore.AddressTx) bool) error {
+	if !b.svc.config.AddressIndex {
+		return errors.New("address index is disabled (enable AddressIndex and run 'galaxy index addresses')")
+	}
+	var err error
+	b.svc.store.evm.ForEachAddressTx(addr, start, startOffset, func(tx evmstore.AddressTx) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		return onTx(tx)
+	})
+	return err
+}
+
+// ForEachTokenTransfer iterates indexed token transfers of the holder if it's specified, otherwise of the token.
+// Transfers are iterated in chronological order starting from the position.
+func (b *EthAPIBackend) ForEachTokenTransfer(ctx context.Context, holder, token *common.Address, start tokenindex.Position, onTransfer func(*tokenindex.Transfer) bool) error {
+	if b.svc.tokenIndexer == nil {
+		return errors.New("token index is disabled (enable TokenIndex and run 'galaxy index tokens')")
+	}
+	var err error
+	cb := func(t *tokenindex.Transfer) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		return onTransfer(t)
+	}
+	if holder != nil {
+		b.svc.tokenIndexer.Store().ForEachHolderTransfer(*holder, start, cb)
+	} else if token != nil {
+		b.svc.tokenIndexer.Store().ForEachTokenTransfer(*token, start, cb)
+	} else {
+		return errors.New("either holder or token must be specified")
+	}
+	return err
+}
+
+// ForEachTokenHolding iterates the latest known token balances of the holder, starting from the token and ID.
+func (b *EthAPIBackend) ForEachTokenHolding(ctx context.Context, holder, startToken common.Address, startID *big.Int, onHolding func(*tokenindex.Holding) bool) error {
+	if b.svc.tokenIndexer == nil {
+		return errors.New("token index is disabled (enable TokenIndex and run 'galaxy index tokens')")
+	}
+	var err error
+	b.svc.tokenIndexer.Store().ForEachHolding(holder, startToken, startID, func(h *tokenindex.Holding) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		return onHolding(h)
+	})
+	return err
+}
+
+// GetSeenPoolTransaction returns a recent tx which was seen in the txpool (even if it was removed from the pool),
+// and the time when it was first seen.
+func (b *EthAPIBackend) GetSeenPoolTransaction(txHash common.Hash) (*types.Transaction, time.Time) {
+	return b.svc.txTracker.getSeenTx(txHash)
+}
+
+// GetTxEvent returns the first known event which carries a recent tx, the tx may be not confirmed yet.
+func (b *EthAPIBackend) GetTxEvent(txHash common.Hash) hash.Event {
+	return b.svc.txTracker.getTxEvent(txHash)
+}
+
+// IsEventConfirmed returns true if a recent event with txs was confirmed and its block is processed.
+func (b *EthAPIBackend) IsEventConfirmed(id hash.Event) bool {
+	return b.svc.txTracker.isEventConfirmed(id)
+}
+
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
 	if !b.svc.config.TxIndex {
 		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
@@ -636,3 +741,38 @@ func (b *EthAPIBackend) SealedEpochTiming(ctx context.Context) (start inter.Time
 	es := b.svc.store.GetEpochState()
 	return es.PrevEpochStart, es.EpochStart
 }
+
+// GetEpochState returns the state of the requested epoch, or nil if it isn't found.
+// * When epoch is -2 the state of the current epoch is returned.
+// * When epoch is -1 the state of the latest sealed epoch is returned.
+func (b *EthAPIBackend) GetEpochState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.EpochState, error) {
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return b.svc.store.GetHistoryEpochState(requested), nil
+}
+
+// GetEpochRules returns network rules of the requested epoch and the epoch itself, or nil rules if they aren't found.
+// * When epoch is -2 the rules of the current epoch are returned.
+// * When epoch is -1 the rules of the latest sealed epoch are returned.
+func (b *EthAPIBackend) GetEpochRules(ctx context.Context, epoch rpc.BlockNumber) (*galaxy.Rules, idx.Epoch, error) {
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, 0, err
+	}
+	es := b.svc.store.GetHistoryEpochState(requested)
+	if es == nil {
+		return nil, requested, nil
+	}
+	return &es.Rules, requested, nil
+}
+
+// GetPendingRules returns network rules which will be applied in the next epoch, and the next epoch.
+func (b *EthAPIBackend) GetPendingRules(ctx context.Context) (*galaxy.Rules, idx.Epoch) {
+	bs, es := b.svc.store.GetBlockEpochState()
+	if bs.DirtyRules != nil {
+		return bs.DirtyRules, es.Epoch + 1
+	}
+	return &es.Rules, es.Epoch + 1
+}
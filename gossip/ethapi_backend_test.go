@@ -0,0 +1,448 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/ethapi"
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy"
+	"go-galaxy/gossip/blockproc/tokenindex"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	"go-galaxy/inter/iblockproc"
+	"go-galaxy/utils"
+	"go-galaxy/utils/gsignercache"
+)
+
+// newTestAPIBackend makes the API backend of the store, without running the service
+func newTestAPIBackend(store *Store) *EthAPIBackend {
+	svc := &Service{
+		store:  store,
+		config: DefaultConfig(cachescale.Identity),
+	}
+	return &EthAPIBackend{
+		svc:    svc,
+		state:  svc.GetEvmStateReader(),
+		signer: gsignercache.Wrap(types.LatestSignerForChainID(store.GetRules().EvmChainConfig().ChainID)),
+	}
+}
+
+func dialTestAPI(t *testing.T, namespace string, api interface{}) *rpc.Client {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName(namespace, api))
+	client := rpc.DialInProc(srv)
+	t.Cleanup(func() {
+		client.Close()
+		srv.Stop()
+	})
+	return client
+}
+
+type testRulesHistoryItem struct {
+	Epoch hexutil.Uint64
+	Rules galaxy.Rules
+	Diff  map[string]interface{}
+}
+
+func TestRulesAPI(t *testing.T) {
+	require := require.New(t)
+
+	// rules are changed in epoch 4, epoch 3 has no stored state, epoch 6 is the current epoch
+	store := NewMemStore()
+	rules := galaxy.FakeNetRules()
+	changed := rules.Copy()
+	changed.Dag.MaxParents = 5
+	pending := changed.Copy()
+	pending.Dag.MaxParents = 7
+	for epoch := idx.Epoch(2); epoch <= 5; epoch++ {
+		es := iblockproc.EpochState{Epoch: epoch, Rules: rules}
+		if epoch >= 4 {
+			es.Rules = changed
+		}
+		if epoch != 3 {
+			store.SetHistoryBlockEpochState(epoch, iblockproc.BlockState{}, es)
+		}
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{DirtyRules: &pending}, iblockproc.EpochState{Epoch: 6, Rules: changed})
+
+	client := dialTestAPI(t, "deam", ethapi.NewPublicRulesAPI(newTestAPIBackend(store)))
+
+	var res galaxy.Rules
+	require.NoError(client.Call(&res, "deam_getRules", "0x2"))
+	require.Equal(rules.Dag.MaxParents, res.Dag.MaxParents)
+	require.NoError(client.Call(&res, "deam_getRules", "latest"))
+	require.Equal(changed.Dag.MaxParents, res.Dag.MaxParents)
+	require.NoError(client.Call(&res, "deam_getRules", "pending"))
+	require.Equal(changed.Dag.MaxParents, res.Dag.MaxParents)
+	require.EqualError(client.Call(&res, "deam_getRules", "0x3"), "rules of epoch 3 not found")
+	require.EqualError(client.Call(&res, "deam_getRules", "0x7"), "epoch is not in range")
+
+	var pendingRes struct {
+		Epoch hexutil.Uint64
+		Rules galaxy.Rules
+	}
+	require.NoError(client.Call(&pendingRes, "deam_getPendingRules"))
+	require.Equal(hexutil.Uint64(7), pendingRes.Epoch)
+	require.Equal(idx.Event(7), pendingRes.Rules.Dag.MaxParents)
+
+	var history []testRulesHistoryItem
+	require.NoError(client.Call(&history, "deam_getRulesHistory", "0x1", "pending"))
+	require.Len(history, 2)
+	require.Equal(hexutil.Uint64(2), history[0].Epoch)
+	require.Nil(history[0].Diff)
+	require.Equal(hexutil.Uint64(4), history[1].Epoch)
+	require.Equal(map[string]interface{}{"Dag": map[string]interface{}{"MaxParents": float64(5)}}, history[1].Diff)
+
+	// the first epoch of the range is included with the full rules
+	require.NoError(client.Call(&history, "deam_getRulesHistory", "0x5", "latest"))
+	require.Len(history, 1)
+	require.Equal(hexutil.Uint64(5), history[0].Epoch)
+	require.Equal(changed.Dag.MaxParents, history[0].Rules.Dag.MaxParents)
+	require.Nil(history[0].Diff)
+
+	require.EqualError(client.Call(&history, "deam_getRulesHistory", "0x5", "0x2"), "from epoch 5 is greater than to epoch 2")
+	require.EqualError(client.Call(&history, "deam_getRulesHistory", "0x1", "0x7"), "epoch is not in range")
+
+	// the range is limited
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 2000, Rules: changed})
+	require.NoError(client.Call(&history, "deam_getRulesHistory", "0x3e9", "0x7d0"))
+	require.EqualError(client.Call(&history, "deam_getRulesHistory", "0x3e8", "0x7d0"), "too wide epochs range, the limit is 1000")
+}
+
+type testBlockReceipts struct {
+	BlockNumber hexutil.Uint64
+	Receipts    []map[string]interface{}
+}
+
+func TestBlockReceiptsAPI(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	rules := galaxy.FakeNetRules()
+	es := iblockproc.EpochState{Epoch: 1, Rules: rules}
+	store.SetHistoryBlockEpochState(1, iblockproc.BlockState{}, es)
+	store.SetEpochBlock(1, 1)
+	setLatest := func(n idx.Block) {
+		store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: n}}, es)
+	}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	signer := types.LatestSignerForChainID(rules.EvmChainConfig().ChainID)
+	newTx := func(nonce uint64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+		require.NoError(err)
+		store.EvmStore().SetTx(tx.Hash(), tx)
+		return tx
+	}
+	// setBlock writes a block of the txs, with receipts of the first receiptsNum txs
+	setBlock := func(n idx.Block, txs types.Transactions, receiptsNum int) *inter.Block {
+		block := &inter.Block{
+			Atropos: hash.Event{byte(n), 0xff},
+			Txs:     []common.Hash{},
+		}
+		receipts := types.Receipts{}
+		for i, tx := range txs {
+			block.Txs = append(block.Txs, tx.Hash())
+			if i < receiptsNum {
+				receipts = append(receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, CumulativeGasUsed: 21000 * uint64(i+1), Logs: []*types.Log{}})
+			}
+		}
+		store.SetBlock(n, block)
+		store.SetBlockIndex(block.Atropos, n)
+		store.EvmStore().SetReceipts(n, receipts)
+		return block
+	}
+
+	// block 1 has txs, block 2 is empty, block 3 has a tx with no receipt
+	setBlock(0, nil, 0)
+	txs := types.Transactions{newTx(0), newTx(1)}
+	block1 := setBlock(1, txs, 2)
+	setBlock(2, nil, 0)
+	setBlock(3, types.Transactions{newTx(2)}, 0)
+	setLatest(3)
+
+	backend := newTestAPIBackend(store)
+	client := dialTestAPI(t, "eth", ethapi.NewPublicBlockChainAPI(backend))
+
+	var receipts []map[string]interface{}
+	require.NoError(client.Call(&receipts, "eth_getBlockReceipts", "0x1"))
+	require.Len(receipts, 2)
+	for i, tx := range txs {
+		require.Equal(tx.Hash().Hex(), receipts[i]["transactionHash"])
+		require.Equal(hexutil.EncodeUint64(uint64(i)), receipts[i]["transactionIndex"])
+		require.Equal("0x1", receipts[i]["blockNumber"])
+		require.Equal(common.Hash(block1.Atropos).Hex(), receipts[i]["blockHash"])
+		require.Equal(crypto.PubkeyToAddress(key.PublicKey).Hex(), common.HexToAddress(receipts[i]["from"].(string)).Hex())
+	}
+	require.Equal("0xa410", receipts[1]["cumulativeGasUsed"])
+
+	var byHash []map[string]interface{}
+	require.NoError(client.Call(&byHash, "eth_getBlockReceipts", common.Hash(block1.Atropos)))
+	require.Equal(receipts, byHash)
+
+	require.NoError(client.Call(&receipts, "eth_getBlockReceipts", "0x2"))
+	require.NotNil(receipts)
+	require.Empty(receipts)
+
+	require.EqualError(client.Call(&receipts, "eth_getBlockReceipts", "0x3"), "receipts of block 3 not found")
+	require.EqualError(client.Call(&receipts, "eth_getBlockReceipts", "0x4"), "block not found")
+
+	// stream of processed blocks
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch := make(chan testBlockReceipts, 4)
+	sub, err := client.Subscribe(ctx, "eth", ch, "blockReceipts", "0x1", "0x2")
+	require.NoError(err)
+	for n := idx.Block(1); n <= 2; n++ {
+		select {
+		case res := <-ch:
+			require.Equal(hexutil.Uint64(n), res.BlockNumber)
+			require.Len(res.Receipts, len(store.GetBlock(n).Txs))
+		case err := <-sub.Err():
+			require.NoError(err)
+		case <-ctx.Done():
+			require.FailNow("no block receipts notification")
+		}
+	}
+	sub.Unsubscribe()
+
+	// blocks are sent once they get processed
+	sub, err = client.Subscribe(ctx, "eth", ch, "blockReceipts", "0x4", "0x4")
+	require.NoError(err)
+	defer sub.Unsubscribe()
+	select {
+	case res := <-ch:
+		require.FailNow("unexpected notification", res.BlockNumber)
+	case <-time.After(100 * time.Millisecond):
+	}
+	setBlock(4, types.Transactions{newTx(3)}, 1)
+	setLatest(4)
+	// wait for the subscriber of the new block notifications
+	require.Eventually(func() bool {
+		return backend.svc.feed.newBlock.Send(evmcore.ChainHeadNotify{Block: backend.CurrentBlock()}) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case res := <-ch:
+		require.Equal(hexutil.Uint64(4), res.BlockNumber)
+		require.Len(res.Receipts, 1)
+	case <-ctx.Done():
+		require.FailNow("no block receipts notification")
+	}
+
+	// the range is limited
+	_, err = client.Subscribe(ctx, "eth", ch, "blockReceipts", "0x1", "0x400")
+	require.NoError(err)
+	_, err = client.Subscribe(ctx, "eth", ch, "blockReceipts", "0x0", "0x400")
+	require.EqualError(err, "too wide blocks range, the limit is 1024")
+	_, err = client.Subscribe(ctx, "eth", ch, "blockReceipts", "0x2", "0x1")
+	require.EqualError(err, "invalid block range")
+}
+
+var (
+	// testCounterCode increments the counter in slot 0, logs and returns it.
+	// With non-empty calldata it sets the counter to 0xff and reverts with 42.
+	testCounterCode = hexutil.MustDecode("0x36601b576000546001018060005560005260206000a060206000f35b60ff600055602a60005260206000fd")
+	// testHeaderCode returns the block number, time and base fee
+	testHeaderCode = hexutil.MustDecode("0x43600052426020524860405260606000f3")
+	// testInvalidCode consumes all the gas
+	testInvalidCode = hexutil.MustDecode("0xfe")
+)
+
+type testSimulatedCallResult struct {
+	ReturnData hexutil.Bytes
+	Logs       []*types.Log
+	GasUsed    hexutil.Uint64
+	Status     hexutil.Uint64
+	Error      string
+	Revert     hexutil.Bytes
+}
+
+func TestSimulateCallsAPI(t *testing.T) {
+	require := require.New(t)
+
+	genesis := makegenesis.FakeGenesisStore(2, 1, utils.ToUnit(1000000), utils.ToUnit(500000)).GetGenesis()
+	store := NewMemStore()
+	_, err := store.ApplyGenesis(DefaultBlockProc(genesis), genesis)
+	require.NoError(err)
+
+	backend := newTestAPIBackend(store)
+	backend.svc.config.RPCGasCap = 200000
+	client := dialTestAPI(t, "deam", ethapi.NewPublicSimulateAPI(backend))
+
+	counter, header, invalid := common.Address{0xc}, common.Address{0xd}, common.Address{0xe}
+	overrides := map[common.Address]interface{}{
+		counter: map[string]interface{}{"code": hexutil.Bytes(testCounterCode)},
+		header:  map[string]interface{}{"code": hexutil.Bytes(testHeaderCode)},
+		invalid: map[string]interface{}{"code": hexutil.Bytes(testInvalidCode)},
+	}
+	call := func(to common.Address, data string) map[string]interface{} {
+		return map[string]interface{}{"to": to, "data": data}
+	}
+	simulate := func(calls []map[string]interface{}, blockOverrides map[string]interface{}) ([]testSimulatedCallResult, error) {
+		var res []testSimulatedCallResult
+		err := client.Call(&res, "deam_simulateCalls", calls, "latest", overrides, blockOverrides)
+		return res, err
+	}
+	counterValue := func(v uint64) hexutil.Bytes {
+		return common.BigToHash(new(big.Int).SetUint64(v)).Bytes()
+	}
+
+	// the state is carried across the calls, reverted calls don't abort the sequence and their changes are discarded
+	res, err := simulate([]map[string]interface{}{
+		call(counter, "0x"),
+		call(counter, "0x"),
+		call(counter, "0x01"),
+		call(counter, "0x"),
+	}, nil)
+	require.NoError(err)
+	require.Len(res, 4)
+	for i, v := range []uint64{1, 2} {
+		require.Equal(counterValue(v), res[i].ReturnData, i)
+		require.Equal(hexutil.Uint64(types.ReceiptStatusSuccessful), res[i].Status, i)
+		require.Empty(res[i].Error, i)
+		require.Len(res[i].Logs, 1, i)
+		require.Equal(counter, res[i].Logs[0].Address)
+		require.Equal([]byte(counterValue(v)), res[i].Logs[0].Data)
+		require.NotZero(res[i].GasUsed, i)
+	}
+	require.Equal(hexutil.Uint64(types.ReceiptStatusFailed), res[2].Status)
+	require.Equal("execution reverted", res[2].Error)
+	require.Equal(counterValue(42), res[2].Revert)
+	require.Empty(res[2].Logs)
+	require.Equal(counterValue(3), res[3].ReturnData)
+
+	// the calls don't change the state
+	res, err = simulate([]map[string]interface{}{call(counter, "0x")}, nil)
+	require.NoError(err)
+	require.Equal(counterValue(1), res[0].ReturnData)
+
+	// block overrides are applied to all the calls
+	res, err = simulate([]map[string]interface{}{call(header, "0x"), call(header, "0x")}, map[string]interface{}{
+		"number":  "0x100",
+		"time":    "0x5f5e100",
+		"baseFee": "0x3b9aca00",
+	})
+	require.NoError(err)
+	for _, r := range res {
+		require.Equal(hexutil.Uint64(types.ReceiptStatusSuccessful), r.Status)
+		require.Equal(append(append(counterValue(0x100), counterValue(100000000)...), counterValue(1000000000)...), r.ReturnData)
+	}
+
+	// RPCGasCap is the total gas of all the calls
+	res, err = simulate([]map[string]interface{}{call(counter, "0x"), call(invalid, "0x")}, nil)
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(200000)-res[0].GasUsed, res[1].GasUsed)
+	require.Equal(hexutil.Uint64(types.ReceiptStatusFailed), res[1].Status)
+	_, err = simulate([]map[string]interface{}{call(counter, "0x"), call(invalid, "0x"), call(counter, "0x")}, nil)
+	require.EqualError(err, "gas cap 200000 is exhausted by call 1")
+}
+
+type testTokenHoldings struct {
+	Holdings []struct {
+		Token   common.Address
+		Balance *hexutil.Big
+	}
+	Cursor *hexutil.Bytes
+}
+
+func TestTokenHoldingsAPI(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 2, Rules: galaxy.FakeNetRules()})
+	b := newTestAPIBackend(store)
+	b.svc.tokenIndexer = tokenindex.New(store.TokenIndex())
+	client := dialTestAPI(t, "galaxy", ethapi.NewPublicTokenAPI(b))
+
+	alice, bob := common.Address{1}, common.Address{2}
+	transfer := func(block uint64, index uint, token, from, to common.Address, value int64) {
+		b.svc.tokenIndexer.OnNewLog(idx.Block(block), &types.Log{
+			Address:     token,
+			Topics:      []common.Hash{tokenindex.Topics.Transfer, from.Hash(), to.Hash()},
+			Data:        common.BigToHash(big.NewInt(value)).Bytes(),
+			BlockNumber: block,
+			Index:       index,
+		})
+	}
+	holdings := func(holder common.Address, limit uint64) map[common.Address]int64 {
+		var res testTokenHoldings
+		require.NoError(client.Call(&res, "galaxy_getTokenHoldings", holder, nil, hexutil.Uint64(limit)))
+		balances := map[common.Address]int64{}
+		for _, h := range res.Holdings {
+			balances[h.Token] = h.Balance.ToInt().Int64()
+		}
+		return balances
+	}
+
+	// blocks are indexed out of order, alice has negative balances until block 1 is indexed
+	transfer(2, 0, common.Address{0xa1}, alice, bob, 40)
+	transfer(2, 1, common.Address{0xa2}, alice, bob, 10)
+	transfer(3, 0, common.Address{0xa3}, common.Address{}, alice, 5)
+	require.Equal(map[common.Address]int64{{0xa3}: 5}, holdings(alice, 1))
+	require.Equal(map[common.Address]int64{{0xa1}: 40, {0xa2}: 10}, holdings(bob, 10))
+
+	transfer(1, 0, common.Address{0xa1}, common.Address{}, alice, 100)
+	require.Equal(map[common.Address]int64{{0xa1}: 60, {0xa3}: 5}, holdings(alice, 10))
+}
+
+type testTxStatus struct {
+	Status string
+	Reason string
+	Event  hexutil.Bytes
+}
+
+func TestTxStatusAPI(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 3, Rules: galaxy.FakeNetRules()})
+	b := newTestAPIBackend(store)
+	b.svc.txTracker = newTxTracker(nil)
+	client := dialTestAPI(t, "galaxy", ethapi.NewPublicTxStatusAPI(b))
+
+	// txs are carried by events which aren't processed
+	newEvent := func(epoch idx.Epoch, tx *types.Transaction) hash.Event {
+		me := &inter.MutableEventPayload{}
+		me.SetVersion(1)
+		me.SetEpoch(epoch)
+		me.SetSeq(1)
+		me.SetCreator(1)
+		me.SetTxs(types.Transactions{tx})
+		me.SetPayloadHash(inter.CalcPayloadHash(me))
+		e := me.Build()
+		store.SetEvent(e)
+		b.svc.txTracker.onNewEvent(e)
+		return e.ID()
+	}
+	status := func(tx *types.Transaction) testTxStatus {
+		var res testTxStatus
+		require.NoError(client.Call(&res, "galaxy_getTransactionStatus", tx.Hash()))
+		return res
+	}
+	tx1 := types.NewTransaction(1, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(2, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	e1 := newEvent(3, tx1)
+	e2 := newEvent(2, tx2)
+
+	require.Equal(testTxStatus{Status: ethapi.TxStatusIncluded, Event: e1.Bytes()}, status(tx1))
+	// the tx isn't indexed after the block processing, i.e. it was skipped
+	b.svc.txTracker.onEventsConfirmed(hash.Events{e1})
+	require.Equal(testTxStatus{Status: ethapi.TxStatusDropped, Reason: "skipped by the block processing", Event: e1.Bytes()}, status(tx1))
+
+	// the event can't get confirmed in a sealed epoch
+	require.Equal(testTxStatus{Status: ethapi.TxStatusDropped, Reason: "the event wasn't confirmed before the epoch was sealed", Event: e2.Bytes()}, status(tx2))
+}
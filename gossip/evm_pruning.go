@@ -0,0 +1,61 @@
+package gossip
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// evmPruningRetryPeriod is a pause before the next attempt to start the EVM state pruning
+const evmPruningRetryPeriod = time.Minute
+
+// startEvmPruning starts the online pruning of the stale EVM state, targeting the current state root
+func (s *Service) startEvmPruning() error {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	if s.stopped {
+		return nil
+	}
+	// the state root mustn't be changed until the pruning is started
+	s.blockProcWg.Wait()
+
+	root := common.Hash(s.store.GetBlockState().FinalizedStateRoot)
+	var genesisRoot common.Hash
+	if genesisIdx := s.store.GetGenesisBlockIndex(); genesisIdx != nil {
+		if genesis := s.store.GetBlock(*genesisIdx); genesis != nil {
+			genesisRoot = common.Hash(genesis.Root)
+		}
+	}
+	return s.store.evm.StartPruning(root, genesisRoot)
+}
+
+// evmPruningLoop resumes an interrupted EVM state pruning and starts a new one every EvmPruningPeriod
+func (s *Service) evmPruningLoop() {
+	defer s.evmPruning.wg.Done()
+
+	period := s.config.EvmPruningPeriod
+	wait := period
+	if s.store.evm.IsPruningInterrupted() {
+		wait = 0
+	}
+	for {
+		select {
+		case <-s.evmPruning.quit:
+			return
+		case <-time.After(wait):
+		}
+		if s.store.evm.IsPruning() {
+			wait = evmPruningRetryPeriod
+			continue
+		}
+		if err := s.startEvmPruning(); err != nil {
+			s.Log.Warn("Failed to start EVM state pruning", "err", err)
+			wait = evmPruningRetryPeriod
+			continue
+		}
+		if period == 0 {
+			return
+		}
+		wait = period
+	}
+}
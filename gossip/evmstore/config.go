@@ -1,6 +1,8 @@
 package evmstore
 
 import (
+	"time"
+
 	"github.com/deamchain/deam-v2-base/utils/cachescale"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 )
@@ -29,9 +31,20 @@ type (
 		// Memory limit (MB) at which to start flushing dirty trie nodes to disk
 		TrieDirtyLimit uint
 	}
+	// PruningConfig is a config for the online EVM state pruning.
+	PruningConfig struct {
+		// Size of the bloom filter of live state entries (size in MB).
+		BloomSize uint64
+		// Number of DB entries inspected per one deletion batch.
+		BatchSize int
+		// Pause between deletion batches to not hurt blocks processing.
+		BatchPause time.Duration
+	}
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
+		// Pruning is a config for the online EVM state pruning
+		Pruning PruningConfig
 		// Enables tracking of SHA3 preimages in the VM
 		EnablePreimageRecording bool
 	}
@@ -51,6 +64,11 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			TrieDirtyDisabled: true,
 			TrieDirtyLimit:    scale.U(400 * opt.MiB),
 		},
+		Pruning: PruningConfig{
+			BloomSize:  scale.U64(512),
+			BatchSize:  10000,
+			BatchPause: 50 * time.Millisecond,
+		},
 		EnablePreimageRecording: true,
 	}
 }
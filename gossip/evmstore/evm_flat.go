@@ -0,0 +1,265 @@
+package evmstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Flat EVM state dump items.
+// Every account is followed by its code (if it wasn't dumped before) and its storage slots.
+// The dump is finished by the trailer item, which contains the state root and the checksum of all the previous items.
+const (
+	flatAccountItem uint8 = 1
+	flatStorageItem uint8 = 2
+	flatCodeItem    uint8 = 3
+	flatTrailerItem uint8 = 4
+)
+
+// FlatEvmItem is an item of the flat EVM state dump.
+// Hash is an account hash, slot hash, code hash or a state root for the trailer.
+// Value is a slim account RLP, slot value RLP, code or a checksum for the trailer.
+type FlatEvmItem struct {
+	Kind  uint8
+	Hash  common.Hash
+	Value []byte
+}
+
+// FlatEvmStats is the stats of the flat EVM state dump.
+type FlatEvmStats struct {
+	Accounts int
+	Slots    int
+	Codes    int
+}
+
+// ExportEvmFlat writes accounts, storage slots and codes of the state from the snapshot.
+// Snapshot must be opened at the root.
+func (s *Store) ExportEvmFlat(w io.Writer, root common.Hash, progress func(FlatEvmStats)) (stats FlatEvmStats, err error) {
+	if s.Snaps == nil {
+		return stats, errors.New("EVM snapshot is not opened")
+	}
+	accIt, err := s.Snaps.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return stats, err
+	}
+	defer accIt.Release()
+
+	checksum := crypto.NewKeccakState()
+	writeItem := func(kind uint8, h common.Hash, value []byte) error {
+		return rlp.Encode(io.MultiWriter(w, checksum), &FlatEvmItem{
+			Kind:  kind,
+			Hash:  h,
+			Value: value,
+		})
+	}
+
+	codes := make(map[common.Hash]bool)
+	for accIt.Next() {
+		addrHash := accIt.Hash()
+		account, err := snapshot.FullAccount(accIt.Account())
+		if err != nil {
+			return stats, err
+		}
+		if err := writeItem(flatAccountItem, addrHash, accIt.Account()); err != nil {
+			return stats, err
+		}
+		stats.Accounts++
+
+		codeHash := common.BytesToHash(account.CodeHash)
+		if codeHash != emptyCodeHash && !codes[codeHash] {
+			code := rawdb.ReadCode(s.EvmDb, codeHash)
+			if code == nil {
+				return stats, fmt.Errorf("failed to get code %s at %s addr", codeHash.String(), addrHash.String())
+			}
+			if err := writeItem(flatCodeItem, codeHash, code); err != nil {
+				return stats, err
+			}
+			codes[codeHash] = true
+			stats.Codes++
+		}
+
+		if common.BytesToHash(account.Root) != types.EmptyRootHash {
+			stIt, err := s.Snaps.StorageIterator(root, addrHash, common.Hash{})
+			if err != nil {
+				return stats, err
+			}
+			for stIt.Next() {
+				if err = writeItem(flatStorageItem, stIt.Hash(), stIt.Slot()); err != nil {
+					break
+				}
+				stats.Slots++
+			}
+			if err == nil {
+				err = stIt.Error()
+			}
+			stIt.Release()
+			if err != nil {
+				return stats, err
+			}
+		}
+		if progress != nil {
+			progress(stats)
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return stats, err
+	}
+
+	return stats, rlp.Encode(w, &FlatEvmItem{
+		Kind:  flatTrailerItem,
+		Hash:  root,
+		Value: checksum.Sum(nil),
+	})
+}
+
+// flatEvmImporter regenerates state and storage tries from a flat EVM state dump
+type flatEvmImporter struct {
+	batch    ethdb.Batch
+	checksum hash.Hash
+
+	accTrie *trie.StackTrie
+
+	addrHash    common.Hash
+	accountRLP  []byte
+	storageRoot common.Hash
+	storageTrie *trie.StackTrie
+
+	stats FlatEvmStats
+}
+
+// ImportEvmFlat regenerates the state trie, storage tries and codes from the flat EVM state dump.
+// The checksum and the regenerated state root are verified against the dump trailer,
+// and the state root is returned.
+func (s *Store) ImportEvmFlat(r io.Reader, progress func(FlatEvmStats)) (root common.Hash, stats FlatEvmStats, err error) {
+	imp := &flatEvmImporter{
+		batch:    s.EvmDb.NewBatch(),
+		checksum: crypto.NewKeccakState(),
+	}
+	imp.accTrie = trie.NewStackTrie(imp.batch)
+
+	stream := rlp.NewStream(r, 0)
+	for {
+		raw, err := stream.Raw()
+		if err == io.EOF {
+			return root, imp.stats, errors.New("EVM dump is truncated, no trailer")
+		}
+		if err != nil {
+			return root, imp.stats, err
+		}
+		item := FlatEvmItem{}
+		if err := rlp.DecodeBytes(raw, &item); err != nil {
+			return root, imp.stats, err
+		}
+		if item.Kind == flatTrailerItem {
+			if !bytes.Equal(item.Value, imp.checksum.Sum(nil)) {
+				return root, imp.stats, errors.New("EVM dump checksum mismatch")
+			}
+			root, err = imp.finish()
+			if err != nil {
+				return root, imp.stats, err
+			}
+			if root != item.Hash {
+				return root, imp.stats, fmt.Errorf("EVM state root mismatch, expected=%s, got=%s", item.Hash.String(), root.String())
+			}
+			return root, imp.stats, nil
+		}
+		imp.checksum.Write(raw)
+		if err := imp.apply(item); err != nil {
+			return root, imp.stats, err
+		}
+		if imp.batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := imp.batch.Write(); err != nil {
+				return root, imp.stats, err
+			}
+			imp.batch.Reset()
+			if progress != nil {
+				progress(imp.stats)
+			}
+		}
+	}
+}
+
+func (imp *flatEvmImporter) apply(item FlatEvmItem) error {
+	switch item.Kind {
+	case flatAccountItem:
+		if err := imp.flushAccount(); err != nil {
+			return err
+		}
+		account, err := snapshot.FullAccount(item.Value)
+		if err != nil {
+			return err
+		}
+		accountRLP, err := snapshot.FullAccountRLP(item.Value)
+		if err != nil {
+			return err
+		}
+		imp.addrHash = item.Hash
+		imp.accountRLP = accountRLP
+		imp.storageRoot = common.BytesToHash(account.Root)
+		if imp.storageRoot != types.EmptyRootHash {
+			imp.storageTrie = trie.NewStackTrie(imp.batch)
+		}
+		imp.stats.Accounts++
+	case flatStorageItem:
+		if imp.storageTrie == nil {
+			return errors.New("unexpected storage slot in EVM dump")
+		}
+		if err := imp.storageTrie.TryUpdate(item.Hash.Bytes(), item.Value); err != nil {
+			return err
+		}
+		imp.stats.Slots++
+	case flatCodeItem:
+		if crypto.Keccak256Hash(item.Value) != item.Hash {
+			return fmt.Errorf("malformed code %s in EVM dump", item.Hash.String())
+		}
+		rawdb.WriteCode(imp.batch, item.Hash, item.Value)
+		imp.stats.Codes++
+	default:
+		return fmt.Errorf("unknown EVM dump item kind %d", item.Kind)
+	}
+	return nil
+}
+
+// flushAccount inserts the current account into the state trie once its storage trie is complete
+func (imp *flatEvmImporter) flushAccount() error {
+	if imp.accountRLP == nil {
+		return nil
+	}
+	if imp.storageTrie != nil {
+		storageRoot, err := imp.storageTrie.Commit()
+		if err != nil {
+			return err
+		}
+		if storageRoot != imp.storageRoot {
+			return fmt.Errorf("storage root mismatch at %s addr, expected=%s, got=%s", imp.addrHash.String(), imp.storageRoot.String(), storageRoot.String())
+		}
+	}
+	if err := imp.accTrie.TryUpdate(imp.addrHash.Bytes(), imp.accountRLP); err != nil {
+		return err
+	}
+	imp.accountRLP = nil
+	imp.storageTrie = nil
+	return nil
+}
+
+func (imp *flatEvmImporter) finish() (common.Hash, error) {
+	if err := imp.flushAccount(); err != nil {
+		return common.Hash{}, err
+	}
+	root, err := imp.accTrie.Commit()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return root, imp.batch.Write()
+}
@@ -0,0 +1,69 @@
+package evmstore
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvmFlatExportImport(t *testing.T) {
+	require := require.New(t)
+
+	src := cachedStore()
+	statedb, err := state.New(types.EmptyRootHash, src.EvmState, nil)
+	require.NoError(err)
+	for i := int64(1); i <= 10; i++ {
+		addr := common.BigToAddress(big.NewInt(i))
+		statedb.SetBalance(addr, big.NewInt(i*1000))
+		statedb.SetNonce(addr, uint64(i))
+		if i%3 == 0 {
+			statedb.SetCode(addr, []byte{0x60, byte(i % 2), 0x00})
+			for j := int64(1); j <= i; j++ {
+				statedb.SetState(addr, common.BigToHash(big.NewInt(j)), common.BigToHash(big.NewInt(i*j)))
+			}
+		}
+	}
+	root, err := statedb.Commit(true)
+	require.NoError(err)
+	require.NoError(src.EvmState.TrieDB().Commit(root, false, nil))
+	require.NoError(src.GenerateEvmSnapshot(root, true, false))
+
+	buf := &bytes.Buffer{}
+	stats, err := src.ExportEvmFlat(buf, root, nil)
+	require.NoError(err)
+	require.Equal(FlatEvmStats{Accounts: 10, Slots: 3 + 6 + 9, Codes: 2}, stats)
+	dump := buf.Bytes()
+
+	dst := nonCachedStore()
+	importedRoot, importedStats, err := dst.ImportEvmFlat(bytes.NewReader(dump), nil)
+	require.NoError(err)
+	require.Equal(root, importedRoot)
+	require.Equal(stats, importedStats)
+
+	imported, err := state.New(root, dst.EvmState, nil)
+	require.NoError(err)
+	for i := int64(1); i <= 10; i++ {
+		addr := common.BigToAddress(big.NewInt(i))
+		require.Equal(big.NewInt(i*1000), imported.GetBalance(addr))
+		require.Equal(uint64(i), imported.GetNonce(addr))
+		if i%3 == 0 {
+			require.Equal([]byte{0x60, byte(i % 2), 0x00}, imported.GetCode(addr))
+			require.Equal(common.BigToHash(big.NewInt(i*i)), imported.GetState(addr, common.BigToHash(big.NewInt(i))))
+		}
+	}
+
+	// corrupted dump
+	corrupted := append([]byte{}, dump...)
+	corrupted[len(corrupted)/2] ^= 0xff
+	_, _, err = nonCachedStore().ImportEvmFlat(bytes.NewReader(corrupted), nil)
+	require.Error(err)
+
+	// truncated dump
+	_, _, err = nonCachedStore().ImportEvmFlat(bytes.NewReader(dump[:len(dump)-40]), nil)
+	require.Error(err)
+}
@@ -25,6 +25,7 @@ import (
 	"go-galaxy/logger"
 	"go-galaxy/topicsdb"
 	"go-galaxy/utils/adapters/kvdb2ethdb"
+	"go-galaxy/utils/freezer"
 	"go-galaxy/utils/rlpstore"
 )
 
@@ -42,7 +43,9 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
+		AddressTxs  kvdb.Store `table:"a"`
 	}
+	receiptsFreezer *freezer.Table
 
 	EvmDb    ethdb.Database
 	EvmState state.Database
@@ -63,6 +66,8 @@ type Store struct {
 
 	triegc *prque.Prque // Priority queue mapping block numbers to tries to gc
 
+	pruning onlinePruning
+
 	logger.Instance
 }
 
@@ -85,7 +90,7 @@ func NewStore(mainDB kvdb.Store, cfg StoreConfig) *Store {
 	s.EvmDb = rawdb.NewDatabase(
 		kvdb2ethdb.Wrap(
 			nokeyiserr.Wrap(
-				s.table.Evm)))
+				&pruningGuard{s.table.Evm, &s.pruning})))
 	s.EvmState = state.NewDatabaseWithConfig(s.EvmDb, &trie.Config{
 		Cache:     cfg.Cache.EvmDatabase / opt.MiB,
 		Journal:   cfg.Cache.TrieCleanJournal,
@@ -0,0 +1,72 @@
+package evmstore
+
+import (
+	"github.com/deamchain/deam-v2-base/common/bigendian"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AddressTx is a position of a transaction sent from or to an address.
+type AddressTx struct {
+	Block       idx.Block
+	BlockOffset uint32
+	TxHash      common.Hash
+}
+
+// addressTxKey is address + block + block offset, so transactions of an address are iterated in the order of execution.
+func addressTxKey(addr common.Address, n idx.Block, offset uint32) []byte {
+	key := make([]byte, 0, common.AddressLength+8+4)
+	key = append(key, addr.Bytes()...)
+	key = append(key, n.Bytes()...)
+	key = append(key, bigendian.Uint32ToBytes(offset)...)
+	return key
+}
+
+// SetAddressTx indexes the transaction by the address.
+func (s *Store) SetAddressTx(addr common.Address, tx AddressTx) {
+	err := s.table.AddressTxs.Put(addressTxKey(addr, tx.Block, tx.BlockOffset), tx.TxHash.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// IndexAddressTxs indexes the block transactions by their senders and recipients.
+// Transactions with an invalid signature (i.e. internal ones) are indexed only by recipient.
+func (s *Store) IndexAddressTxs(n idx.Block, txs types.Transactions, signer types.Signer) {
+	for i, tx := range txs {
+		position := AddressTx{
+			Block:       n,
+			BlockOffset: uint32(i),
+			TxHash:      tx.Hash(),
+		}
+		if from, err := types.Sender(signer, tx); err == nil {
+			s.SetAddressTx(from, position)
+		}
+		if to := tx.To(); to != nil {
+			s.SetAddressTx(*to, position)
+		}
+	}
+}
+
+// ForEachAddressTx iterates the transactions sent from or to the address, in the order of execution,
+// starting from the specified block and block offset.
+func (s *Store) ForEachAddressTx(addr common.Address, start idx.Block, startOffset uint32, onTx func(AddressTx) bool) {
+	prefixLen := common.AddressLength
+	it := s.table.AddressTxs.NewIterator(addr.Bytes(), addressTxKey(addr, start, startOffset)[prefixLen:])
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != prefixLen+8+4 || len(it.Value()) != common.HashLength {
+			s.Log.Crit("Address transactions index is corrupted", "key", common.Bytes2Hex(key))
+		}
+		tx := AddressTx{
+			Block:       idx.BytesToBlock(key[prefixLen : prefixLen+8]),
+			BlockOffset: bigendian.BytesToUint32(key[prefixLen+8:]),
+			TxHash:      common.BytesToHash(it.Value()),
+		}
+		if !onTx(tx) {
+			break
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/logger"
+)
+
+func TestStoreAddressTxs(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	store := cachedStore()
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.Address{1}
+	other := common.Address{2}
+
+	signed := func(nonce uint64, to *common.Address) *types.Transaction {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       to,
+			Gas:      21000,
+			GasPrice: big.NewInt(1),
+		}), signer, key)
+		require.NoError(err)
+		return tx
+	}
+	// unsigned transaction, like an internal one
+	internal := types.NewTx(&types.LegacyTx{To: &recipient})
+
+	store.IndexAddressTxs(1, types.Transactions{internal, signed(0, &recipient), signed(1, &other)}, signer)
+	store.IndexAddressTxs(3, types.Transactions{signed(2, nil), signed(3, &recipient)}, signer)
+
+	collect := func(addr common.Address, start idx.Block, startOffset uint32) []AddressTx {
+		var res []AddressTx
+		store.ForEachAddressTx(addr, start, startOffset, func(tx AddressTx) bool {
+			res = append(res, tx)
+			return true
+		})
+		return res
+	}
+	positions := func(txs []AddressTx) [][2]uint64 {
+		res := make([][2]uint64, len(txs))
+		for i, tx := range txs {
+			res[i] = [2]uint64{uint64(tx.Block), uint64(tx.BlockOffset)}
+		}
+		return res
+	}
+
+	require.Equal([][2]uint64{{1, 1}, {1, 2}, {3, 0}, {3, 1}}, positions(collect(sender, 0, 0)))
+	require.Equal([][2]uint64{{1, 0}, {1, 1}, {3, 1}}, positions(collect(recipient, 0, 0)))
+	require.Equal([][2]uint64{{1, 2}}, positions(collect(other, 0, 0)))
+	require.Empty(collect(common.Address{3}, 0, 0))
+
+	// start position
+	require.Equal([][2]uint64{{1, 2}, {3, 0}, {3, 1}}, positions(collect(sender, 1, 2)))
+	require.Equal([][2]uint64{{3, 0}, {3, 1}}, positions(collect(sender, 2, 0)))
+	require.Equal([][2]uint64{{3, 1}}, positions(collect(recipient, 1, 2)))
+	require.Empty(collect(recipient, 4, 0))
+
+	// tx hashes
+	txs := collect(recipient, 1, 0)
+	require.Equal(internal.Hash(), txs[0].TxHash)
+
+	// early stop
+	var visited int
+	store.ForEachAddressTx(sender, 0, 0, func(tx AddressTx) bool {
+		visited++
+		return visited < 2
+	})
+	require.Equal(2, visited)
+}
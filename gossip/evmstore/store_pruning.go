@@ -0,0 +1,453 @@
+package evmstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	bloomfilter "github.com/holiman/bloomfilter/v2"
+
+	"go-galaxy/utils/adapters/snap2kvdb"
+)
+
+var (
+	pruningMarkedGauge   = metrics.GetOrRegisterGauge("evm/pruning/marked", nil)
+	pruningDeletedGauge  = metrics.GetOrRegisterGauge("evm/pruning/deleted", nil)
+	pruningSizeGauge     = metrics.GetOrRegisterGauge("evm/pruning/size", nil)
+	pruningProgressGauge = metrics.GetOrRegisterGauge("evm/pruning/progress", nil)
+
+	// pruningJournalKey tracks the progress of the online pruning
+	pruningJournalKey = []byte("OnlinePruningJournal")
+
+	errPruningInterrupted = errors.New("pruning is interrupted")
+)
+
+// pruningJournal is a record of an unfinished online pruning.
+// Entries before Next key are already swept.
+type pruningJournal struct {
+	Root common.Hash
+	Next []byte
+}
+
+type pruningHasher []byte
+
+func (f pruningHasher) Write(p []byte) (n int, err error) { panic("not implemented") }
+func (f pruningHasher) Sum(b []byte) []byte               { panic("not implemented") }
+func (f pruningHasher) Reset()                            { panic("not implemented") }
+func (f pruningHasher) BlockSize() int                    { panic("not implemented") }
+func (f pruningHasher) Size() int                         { return 8 }
+func (f pruningHasher) Sum64() uint64                     { return binary.BigEndian.Uint64(f) }
+
+// stateEntryKey returns the bloom key of a trie node or a code entry,
+// or nil if the DB key isn't a state entry.
+func stateEntryKey(key []byte) []byte {
+	if len(key) == common.HashLength {
+		return key
+	}
+	if isCode, codeKey := rawdb.IsCodeKey(key); isCode {
+		return codeKey
+	}
+	return nil
+}
+
+// onlinePruning is a state of the background EVM state pruning.
+//
+// All the trie nodes and codes written while pruning is active are added into the bloom
+// filter of live entries, so the state written after the pruning target is never deleted.
+// The bloom check and the deletion of a batch are done under the same lock as writes,
+// so a stale entry which is re-written concurrently is never lost.
+type onlinePruning struct {
+	mu    sync.Mutex
+	bloom *bloomfilter.Filter // bloom of live trie nodes and codes, nil if pruning isn't active
+
+	marked  int64
+	deleted int64
+	size    common.StorageSize
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (p *onlinePruning) active() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.bloom != nil
+}
+
+// track adds the written keys into the bloom of live entries
+// Note: p.mu is locked here
+func (p *onlinePruning) track(keys ...[]byte) {
+	if p.bloom == nil {
+		return
+	}
+	for _, key := range keys {
+		if entry := stateEntryKey(key); entry != nil {
+			p.bloom.Add(pruningHasher(entry))
+		}
+	}
+}
+
+func (p *onlinePruning) interrupted() bool {
+	select {
+	case <-p.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// Put implements ethdb.KeyValueWriter to mark the entries regenerated from the snapshot.
+func (p *onlinePruning) Put(key []byte, _ []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.track(key)
+	p.marked++
+	return nil
+}
+
+// Delete implements ethdb.KeyValueWriter.
+func (p *onlinePruning) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// pruningGuard is a wrapper over EVM table, which notifies the online pruning about written state entries.
+type pruningGuard struct {
+	kvdb.Store
+	p *onlinePruning
+}
+
+type pruningGuardBatch struct {
+	kvdb.Batch
+	p    *onlinePruning
+	keys [][]byte
+}
+
+func (g *pruningGuard) Put(key []byte, value []byte) error {
+	g.p.mu.Lock()
+	defer g.p.mu.Unlock()
+	g.p.track(key)
+	return g.Store.Put(key, value)
+}
+
+func (g *pruningGuard) NewBatch() kvdb.Batch {
+	return &pruningGuardBatch{
+		Batch: g.Store.NewBatch(),
+		p:     g.p,
+	}
+}
+
+func (b *pruningGuardBatch) Put(key []byte, value []byte) error {
+	if stateEntryKey(key) != nil {
+		b.keys = append(b.keys, common.CopyBytes(key))
+	}
+	return b.Batch.Put(key, value)
+}
+
+func (b *pruningGuardBatch) Write() error {
+	b.p.mu.Lock()
+	defer b.p.mu.Unlock()
+	b.p.track(b.keys...)
+	return b.Batch.Write()
+}
+
+func (b *pruningGuardBatch) Reset() {
+	b.keys = b.keys[:0]
+	b.Batch.Reset()
+}
+
+// pruningReader is a wrapper over the frozen DB, which stops returning data once the pruning is interrupted.
+// It's the only way to abort the state regeneration from the snapshot, as it ignores write errors.
+type pruningReader struct {
+	kvdb.Store
+	p *onlinePruning
+}
+
+type pruningReaderIterator struct {
+	kvdb.Iterator
+	p *onlinePruning
+}
+
+func (r *pruningReader) Has(key []byte) (bool, error) {
+	if r.p.interrupted() {
+		return false, errPruningInterrupted
+	}
+	return r.Store.Has(key)
+}
+
+func (r *pruningReader) Get(key []byte) ([]byte, error) {
+	if r.p.interrupted() {
+		return nil, errPruningInterrupted
+	}
+	return r.Store.Get(key)
+}
+
+func (r *pruningReader) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	return &pruningReaderIterator{r.Store.NewIterator(prefix, start), r.p}
+}
+
+func (it *pruningReaderIterator) Next() bool {
+	return !it.p.interrupted() && it.Iterator.Next()
+}
+
+func (it *pruningReaderIterator) Error() error {
+	if it.p.interrupted() {
+		return errPruningInterrupted
+	}
+	return it.Iterator.Error()
+}
+
+// IsPruning returns true if the online pruning is in progress.
+func (s *Store) IsPruning() bool {
+	return s.pruning.active()
+}
+
+// IsPruningInterrupted returns true if the online pruning wasn't finished before the node stopped.
+func (s *Store) IsPruningInterrupted() bool {
+	return s.getPruningJournal() != nil
+}
+
+func (s *Store) getPruningJournal() *pruningJournal {
+	j, _ := s.rlp.Get(s.table.Evm, pruningJournalKey, &pruningJournal{}).(*pruningJournal)
+	return j
+}
+
+func (s *Store) setPruningJournal(j pruningJournal) {
+	s.rlp.Set(s.table.Evm, pruningJournalKey, &j)
+}
+
+// StartPruning starts the online pruning in background.
+// It deletes all the trie nodes and codes which don't belong to the root state,
+// the genesis state or the states written after the call.
+// Root must be the current state root of the EVM snapshot, and it must not be changed
+// until the call returns.
+// An interrupted pruning is resumed from the journaled position.
+func (s *Store) StartPruning(root, genesisRoot common.Hash) error {
+	if s.Snaps == nil {
+		return errors.New("EVM snapshot is not opened")
+	}
+	if s.IsEvmSnapshotPaused() {
+		return errors.New("EVM snapshot is paused")
+	}
+	if gen, err := s.Snaps.Generating(); gen || err != nil {
+		return errors.New("EVM snapshot is not ready")
+	}
+	if s.IsPruning() {
+		return errors.New("pruning is already in progress")
+	}
+	s.pruning.wg.Wait()
+
+	// the snapshot is capped to the disk layer at every block, so its iterators become stale soon,
+	// mark the entries from the frozen DB instead
+	kvdbSnap, err := s.mainDB.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	s.pruning.quit = make(chan struct{})
+	frozen := NewStore(&pruningReader{snap2kvdb.Wrap(kvdbSnap), &s.pruning}, LiteStoreConfig())
+	err = frozen.GenerateEvmSnapshot(root, false, false)
+	if err != nil {
+		kvdbSnap.Release()
+		return err
+	}
+
+	bloom, err := bloomfilter.New(s.cfg.Pruning.BloomSize*1024*1024*8, 4)
+	if err != nil {
+		kvdbSnap.Release()
+		return err
+	}
+	journal := pruningJournal{Root: root}
+	if prev := s.getPruningJournal(); prev != nil {
+		journal.Next = prev.Next
+		s.Log.Info("Resuming EVM state pruning", "root", root, "prev", prev.Root)
+	} else {
+		s.Log.Info("Starting EVM state pruning", "root", root)
+	}
+	s.setPruningJournal(journal)
+
+	// start tracking of written entries before the state is marked
+	s.pruning.mu.Lock()
+	s.pruning.bloom = bloom
+	s.pruning.marked, s.pruning.deleted, s.pruning.size = 0, 0, 0
+	s.pruning.mu.Unlock()
+
+	s.pruning.wg.Add(1)
+	go func() {
+		defer s.pruning.wg.Done()
+		err := s.prune(frozen, kvdbSnap, journal, genesisRoot)
+		if err == errPruningInterrupted {
+			s.Log.Info("EVM state pruning is interrupted", "marked", s.pruning.marked, "deleted", s.pruning.deleted, "size", s.pruning.size)
+		} else if err != nil {
+			s.Log.Error("EVM state pruning failed", "err", err)
+		}
+		s.pruning.mu.Lock()
+		s.pruning.bloom = nil
+		s.pruning.mu.Unlock()
+	}()
+	return nil
+}
+
+// StopPruning interrupts the online pruning and waits until it's stopped.
+// The pruning will be resumed by the next StartPruning call.
+func (s *Store) StopPruning() {
+	s.pruning.mu.Lock()
+	if s.pruning.bloom != nil {
+		select {
+		case <-s.pruning.quit:
+		default:
+			close(s.pruning.quit)
+		}
+	}
+	s.pruning.mu.Unlock()
+	s.pruning.wg.Wait()
+}
+
+func (s *Store) prune(frozen *Store, kvdbSnap kvdb.Snapshot, journal pruningJournal, genesisRoot common.Hash) error {
+	start := time.Now()
+	err := snapshot.GenerateTrie(frozen.Snaps, journal.Root, frozen.EvmDb, &s.pruning)
+	if err == nil && genesisRoot != (common.Hash{}) && genesisRoot != journal.Root {
+		err = s.markTrie(frozen.EvmDb, genesisRoot)
+	}
+	kvdbSnap.Release()
+	if s.pruning.interrupted() {
+		return errPruningInterrupted
+	}
+	if err != nil {
+		return err
+	}
+	pruningMarkedGauge.Update(s.pruning.marked)
+	s.Log.Info("Marked live EVM state", "entries", s.pruning.marked, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	logged := time.Now()
+	for {
+		next, err := s.pruneBatch(journal.Next)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			break
+		}
+		journal.Next = next
+		s.setPruningJournal(journal)
+		pruningProgressGauge.Update(int64(binary.BigEndian.Uint64(common.RightPadBytes(next, 8)) / (math.MaxUint64 / 100)))
+
+		if time.Since(logged) > 8*time.Second {
+			s.Log.Info("Pruning EVM state", "deleted", s.pruning.deleted, "size", s.pruning.size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		select {
+		case <-s.pruning.quit:
+			return errPruningInterrupted
+		case <-time.After(s.cfg.Pruning.BatchPause):
+		}
+	}
+	_ = s.table.Evm.Delete(pruningJournalKey)
+	pruningProgressGauge.Update(100)
+	s.Log.Info("EVM state pruning finished", "deleted", s.pruning.deleted, "size", s.pruning.size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// pruneBatch deletes stale entries among the next BatchSize DB entries starting from the key.
+// It returns the key to continue from, or nil if all the entries are inspected.
+func (s *Store) pruneBatch(from []byte) ([]byte, error) {
+	var (
+		candidates [][]byte
+		sizes      []int
+		last       []byte
+		inspected  int
+	)
+	it := s.table.Evm.NewIterator(nil, from)
+	for inspected < s.cfg.Pruning.BatchSize && it.Next() {
+		inspected++
+		key := it.Key()
+		last = common.CopyBytes(key)
+		if stateEntryKey(key) != nil {
+			candidates = append(candidates, last)
+			sizes = append(sizes, len(key)+len(it.Value()))
+		}
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	s.pruning.mu.Lock()
+	batch := s.table.Evm.NewBatch()
+	for i, key := range candidates {
+		if s.pruning.bloom.Contains(pruningHasher(stateEntryKey(key))) {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			s.pruning.mu.Unlock()
+			return nil, err
+		}
+		s.pruning.deleted++
+		s.pruning.size += common.StorageSize(sizes[i])
+	}
+	err = batch.Write()
+	s.pruning.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	pruningDeletedGauge.Update(s.pruning.deleted)
+	pruningSizeGauge.Update(int64(s.pruning.size))
+
+	if inspected < s.cfg.Pruning.BatchSize {
+		return nil, nil
+	}
+	return append(last, 0), nil
+}
+
+// markTrie marks all the trie nodes and codes of the state.
+func (s *Store) markTrie(db ethdb.Database, root common.Hash) error {
+	triedb := trie.NewDatabase(db)
+	t, err := trie.NewSecure(root, triedb)
+	if err != nil {
+		return err
+	}
+	accIter := t.NodeIterator(nil)
+	for accIter.Next(true) {
+		if h := accIter.Hash(); h != (common.Hash{}) {
+			_ = s.pruning.Put(h.Bytes(), nil)
+		}
+		if !accIter.Leaf() {
+			continue
+		}
+		var acc state.Account
+		if err := rlp.DecodeBytes(accIter.LeafBlob(), &acc); err != nil {
+			return err
+		}
+		if acc.Root != types.EmptyRootHash {
+			storageTrie, err := trie.NewSecure(acc.Root, triedb)
+			if err != nil {
+				return err
+			}
+			storageIter := storageTrie.NodeIterator(nil)
+			for storageIter.Next(true) {
+				if h := storageIter.Hash(); h != (common.Hash{}) {
+					_ = s.pruning.Put(h.Bytes(), nil)
+				}
+			}
+			if storageIter.Error() != nil {
+				return storageIter.Error()
+			}
+		}
+		if !bytes.Equal(acc.CodeHash, EmptyCode) {
+			_ = s.pruning.Put(acc.CodeHash, nil)
+		}
+	}
+	return accIter.Error()
+}
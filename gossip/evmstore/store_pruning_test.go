@@ -0,0 +1,164 @@
+package evmstore
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreOnlinePruning(t *testing.T) {
+	require := require.New(t)
+
+	s := cachedStore()
+	s.cfg.Pruning.BloomSize = 1
+	s.cfg.Pruning.BatchSize = 10
+	s.cfg.Pruning.BatchPause = time.Millisecond
+
+	// genesis state
+	statedb, err := state.New(types.EmptyRootHash, s.EvmState, nil)
+	require.NoError(err)
+	for i := int64(1); i <= 10; i++ {
+		addr := common.BigToAddress(big.NewInt(i))
+		statedb.SetBalance(addr, big.NewInt(i))
+		statedb.SetCode(addr, []byte{0x60, byte(i), 0x00})
+		statedb.SetState(addr, common.Hash{}, common.BigToHash(big.NewInt(i)))
+	}
+	genesisRoot, err := statedb.Commit(true)
+	require.NoError(err)
+	require.NoError(s.EvmState.TrieDB().Commit(genesisRoot, false, nil))
+	require.NoError(s.GenerateEvmSnapshot(genesisRoot, true, false))
+
+	// make states with stale nodes
+	roots := []common.Hash{genesisRoot}
+	for b := int64(1); b <= 5; b++ {
+		statedb, err := s.StateDB(hash.Hash(roots[len(roots)-1]))
+		require.NoError(err)
+		for i := int64(1); i <= 10; i++ {
+			addr := common.BigToAddress(big.NewInt(i))
+			statedb.SetBalance(addr, big.NewInt(i*100+b))
+			statedb.SetState(addr, common.BigToHash(big.NewInt(b)), common.BigToHash(big.NewInt(i*b)))
+			statedb.SetCode(addr, []byte{0x60, byte(i), byte(b)})
+		}
+		root, err := statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(s.EvmState.TrieDB().Commit(root, false, nil))
+		roots = append(roots, root)
+	}
+	head := roots[len(roots)-1]
+	before := countStateEntries(s)
+
+	// interrupt the pruning and resume it
+	require.False(s.IsPruningInterrupted())
+	require.NoError(s.StartPruning(head, genesisRoot))
+	require.Error(s.StartPruning(head, genesisRoot))
+	s.StopPruning()
+	require.False(s.IsPruning())
+	require.True(s.IsPruningInterrupted())
+
+	require.NoError(s.StartPruning(head, genesisRoot))
+	for s.IsPruning() {
+		time.Sleep(time.Millisecond)
+	}
+	require.False(s.IsPruningInterrupted())
+	require.Less(countStateEntries(s), before)
+
+	// the target and genesis states are complete, the middle states are pruned
+	for i, root := range roots {
+		err := traverseState(s, root)
+		if i == 0 || i == len(roots)-1 {
+			require.NoError(err, i)
+		} else {
+			require.Error(err, i)
+		}
+	}
+}
+
+func TestStoreOnlinePruningTracksWrites(t *testing.T) {
+	require := require.New(t)
+
+	s := cachedStore()
+	s.cfg.Pruning.BloomSize = 1
+
+	statedb, err := state.New(types.EmptyRootHash, s.EvmState, nil)
+	require.NoError(err)
+	statedb.SetBalance(common.Address{1}, big.NewInt(1))
+	root, err := statedb.Commit(true)
+	require.NoError(err)
+	require.NoError(s.EvmState.TrieDB().Commit(root, false, nil))
+	require.NoError(s.GenerateEvmSnapshot(root, true, false))
+
+	// entries written while pruning is active are never deleted
+	written := common.Hash{0xaa}
+	batchWritten := common.Hash{0xbb}
+	require.NoError(s.StartPruning(root, common.Hash{}))
+	require.NoError(s.EvmDb.Put(written.Bytes(), []byte{1}))
+	batch := s.EvmDb.NewBatch()
+	require.NoError(batch.Put(batchWritten.Bytes(), []byte{2}))
+	require.NoError(batch.Write())
+	for s.IsPruning() {
+		time.Sleep(time.Millisecond)
+	}
+	has, err := s.EvmDb.Has(written.Bytes())
+	require.NoError(err)
+	require.True(has)
+	has, err = s.EvmDb.Has(batchWritten.Bytes())
+	require.NoError(err)
+	require.True(has)
+	require.NoError(traverseState(s, root))
+}
+
+func countStateEntries(s *Store) int {
+	count := 0
+	it := s.table.Evm.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if stateEntryKey(it.Key()) != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// traverseState checks that all the trie nodes and codes of the state are present on disk
+func traverseState(s *Store, root common.Hash) error {
+	triedb := trie.NewDatabase(s.EvmDb)
+	t, err := trie.NewSecure(root, triedb)
+	if err != nil {
+		return err
+	}
+	it := trie.NewIterator(t.NodeIterator(nil))
+	for it.Next() {
+		var acc state.Account
+		if err := rlp.DecodeBytes(it.Value, &acc); err != nil {
+			return err
+		}
+		if acc.Root != types.EmptyRootHash {
+			st, err := trie.NewSecure(acc.Root, triedb)
+			if err != nil {
+				return err
+			}
+			stIt := trie.NewIterator(st.NodeIterator(nil))
+			for stIt.Next() {
+			}
+			if stIt.Err != nil {
+				return stIt.Err
+			}
+		}
+		if codeHash := common.BytesToHash(acc.CodeHash); codeHash != emptyCodeHash {
+			if len(rawdb.ReadCode(s.EvmDb, codeHash)) == 0 {
+				return fmt.Errorf("missing code %s", codeHash.String())
+			}
+		}
+	}
+	return it.Err
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-galaxy/utils/freezer"
 )
 
 // SetReceipts stores transaction receipts.
@@ -46,9 +48,36 @@ func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if buf == nil && s.receiptsFreezer != nil && s.receiptsFreezer.Has(uint64(n)) {
+		buf, err = s.receiptsFreezer.Retrieve(uint64(n))
+		if err != nil {
+			s.Log.Crit("Failed to retrieve frozen receipts", "block", n, "err", err)
+		}
+		if len(buf) == 0 {
+			return nil
+		}
+	}
 	return buf
 }
 
+// SetReceiptsFreezer sets the flat-file table of final receipts.
+func (s *Store) SetReceiptsFreezer(t *freezer.Table) {
+	s.receiptsFreezer = t
+}
+
+// FreezeReceipts moves receipts of the block from the key-value DB into the freezer.
+// Missing receipts are frozen as an empty item.
+func (s *Store) FreezeReceipts(n idx.Block) error {
+	buf, err := s.table.Receipts.Get(n.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := s.receiptsFreezer.Append(uint64(n), buf); err != nil {
+		return err
+	}
+	return s.table.Receipts.Delete(n.Bytes())
+}
+
 func (s *Store) GetRawReceipts(n idx.Block) ([]*types.ReceiptForStorage, int) {
 	buf := s.GetRawReceiptsRLP(n)
 	if buf == nil {
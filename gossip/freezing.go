@@ -0,0 +1,35 @@
+package gossip
+
+// triggerFreezing wakes up the freezing loop, if it isn't running yet
+func (s *Service) triggerFreezing() {
+	select {
+	case s.freezing.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// freezingLoop moves final data of the sealed epochs into the freezer after every epoch sealing.
+// Data is moved in batches until the freezer catches up, so a large backlog doesn't block the events processing for long.
+func (s *Service) freezingLoop() {
+	defer s.freezing.wg.Done()
+
+	for {
+		select {
+		case <-s.freezing.quit:
+			return
+		case <-s.freezing.trigger:
+		}
+		for more := true; more; {
+			s.engineMu.Lock()
+			more = s.store.FreezeEpochs()
+			s.mayCommit(false)
+			s.engineMu.Unlock()
+
+			select {
+			case <-s.freezing.quit:
+				return
+			default:
+			}
+		}
+	}
+}
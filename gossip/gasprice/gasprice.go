@@ -66,7 +66,8 @@ type cache struct {
 type Oracle struct {
 	backend Reader
 
-	cfg Config
+	cfg     Config
+	cfgLock sync.RWMutex
 
 	cache cache
 }
@@ -90,15 +91,33 @@ func sanitizeBigInt(val, min, max, _default *big.Int, name string) *big.Int {
 // NewOracle returns a new gasprice oracle which can recommend suitable
 // gasprice for newly created transaction.
 func NewOracle(backend Reader, params Config) *Oracle {
+	return &Oracle{
+		backend: backend,
+		cfg:     sanitizeConfig(params),
+	}
+}
+
+// SetConfig updates the params of a running oracle, the new params are applied starting from the next suggestion.
+func (gpo *Oracle) SetConfig(params Config) {
+	params = sanitizeConfig(params)
+	gpo.cfgLock.Lock()
+	gpo.cfg = params
+	gpo.cfgLock.Unlock()
+
+	// drop the suggestion which was made with the previous params
+	gpo.cache.lock.Lock()
+	gpo.cache.head = 0
+	gpo.cache.value = nil
+	gpo.cache.lock.Unlock()
+}
+
+func sanitizeConfig(params Config) Config {
 	params.MaxTipCap = sanitizeBigInt(params.MaxTipCap, nil, nil, DefaultMaxTipCap, "MaxTipCap")
 	params.MinTipCap = sanitizeBigInt(params.MinTipCap, nil, nil, new(big.Int), "MinTipCap")
 	params.GasPowerWallRatio = sanitizeBigInt(params.GasPowerWallRatio, big.NewInt(1), big.NewInt(DecimalUnit-2), big.NewInt(1), "GasPowerWallRatio")
 	params.MaxTipCapMultiplierRatio = sanitizeBigInt(params.MaxTipCapMultiplierRatio, DecimalUnitBn, nil, big.NewInt(10*DecimalUnit), "MaxTipCapMultiplierRatio")
 	params.MiddleTipCapMultiplierRatio = sanitizeBigInt(params.MiddleTipCapMultiplierRatio, DecimalUnitBn, params.MaxTipCapMultiplierRatio, big.NewInt(2*DecimalUnit), "MiddleTipCapMultiplierRatio")
-	return &Oracle{
-		backend: backend,
-		cfg:     params,
-	}
+	return params
 }
 
 func (gpo *Oracle) maxTotalGasPower() *big.Int {
@@ -112,6 +131,9 @@ func (gpo *Oracle) maxTotalGasPower() *big.Int {
 }
 
 func (gpo *Oracle) suggestTipCap() *big.Int {
+	gpo.cfgLock.RLock()
+	defer gpo.cfgLock.RUnlock()
+
 	max := gpo.maxTotalGasPower()
 
 	current := new(big.Int).SetUint64(gpo.backend.TotalGasPowerLeft())
@@ -176,7 +198,7 @@ func (gpo *Oracle) SuggestTipCap() *big.Int {
 	gpo.cache.lock.RLock()
 	cachedHead, cachedValue := gpo.cache.head, gpo.cache.value
 	gpo.cache.lock.RUnlock()
-	if head <= cachedHead {
+	if head <= cachedHead && cachedValue != nil {
 		return new(big.Int).Set(cachedValue)
 	}
 
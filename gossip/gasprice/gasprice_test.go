@@ -152,3 +152,31 @@ func TestSuggestTipCap(t *testing.T) {
 	require.Equal(t, "1500000000", gpo.SuggestTipCap().String())
 	backend.block++
 }
+
+func TestSetConfig(t *testing.T) {
+	backend := &TestBackend{
+		block:             1,
+		totalGasPowerLeft: 0,
+		rules:             galaxy.FakeNetRules(),
+		pendingRules:      galaxy.FakeNetRules(),
+	}
+
+	gpo := NewOracle(backend, Config{
+		MiddleTipCapMultiplierRatio: big.NewInt(DecimalUnit),
+		MaxTipCapMultiplierRatio:    big.NewInt(9 * DecimalUnit),
+	})
+	require.Equal(t, "9000000000", gpo.SuggestTipCap().String())
+
+	// new params are applied without waiting for a new block
+	gpo.SetConfig(Config{
+		MaxTipCap:                   big.NewInt(5000000000),
+		MiddleTipCapMultiplierRatio: big.NewInt(DecimalUnit),
+		MaxTipCapMultiplierRatio:    big.NewInt(9 * DecimalUnit),
+	})
+	require.Equal(t, "5000000000", gpo.SuggestTipCap().String())
+
+	// new params are sanitized
+	gpo.SetConfig(Config{})
+	require.Equal(t, DefaultMaxTipCap.String(), gpo.cfg.MaxTipCap.String())
+	require.Equal(t, big.NewInt(10*DecimalUnit).String(), gpo.cfg.MaxTipCapMultiplierRatio.String())
+}
@@ -22,11 +22,7 @@ func (b *GPOBackend) GetRules() galaxy.Rules {
 }
 
 func (b *GPOBackend) GetPendingRules() galaxy.Rules {
-	bs, es := b.store.GetBlockEpochState()
-	if bs.DirtyRules != nil {
-		return *bs.DirtyRules
-	}
-	return es.Rules
+	return b.store.GetPendingRules()
 }
 
 // TotalGasPowerLeft returns a total amount of obtained gas power by the validators, according to the latest events from each validator
@@ -49,6 +49,7 @@ import (
 	"go-galaxy/inter/ibr"
 	"go-galaxy/inter/ier"
 	"go-galaxy/logger"
+	"go-galaxy/tracing"
 )
 
 const (
@@ -127,6 +128,9 @@ type handler struct {
 	NetworkID uint64
 	config    Config
 
+	// peerCacheMu protects config.Protocol.PeerCache, which may be updated at runtime
+	peerCacheMu sync.RWMutex
+
 	syncStatus syncStatus
 
 	txpool   TxPool
@@ -193,6 +197,19 @@ type handler struct {
 
 // newHandler returns a new chain sub protocol manager. The chain sub protocol manages peers capable
 // with the chain network.
+// setPeerCacheConfig updates the caches config, which is applied to the newly connected peers
+func (h *handler) setPeerCacheConfig(cfg PeerCacheConfig) {
+	h.peerCacheMu.Lock()
+	defer h.peerCacheMu.Unlock()
+	h.config.Protocol.PeerCache = cfg
+}
+
+func (h *handler) peerCacheConfig() PeerCacheConfig {
+	h.peerCacheMu.RLock()
+	defer h.peerCacheMu.RUnlock()
+	return h.config.Protocol.PeerCache
+}
+
 func newHandler(
 	c handlerConfig,
 ) (
@@ -334,6 +351,12 @@ func newHandler(
 			llrs := h.store.GetLlrState()
 			start := llrs.LowestBlockToFill
 			end := llrs.LowestBlockToDecide
+			if h.syncStatus.Is(ssEvents) {
+				// only history may be back-filled, new blocks are produced by events processing
+				if latest := h.store.GetLatestBlockIndex() + 1; end > latest {
+					end = latest
+				}
+			}
 			if end > start+100 && h.store.HasBlock(start+100) {
 				return start + 100
 			}
@@ -457,6 +480,7 @@ func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.
 			Process: func(_e dag.Event) error {
 				e := _e.(*inter.EventPayload)
 				preStart := time.Now()
+				tracing.CloseEventStage(e.ID(), "dagprocessor")
 				h.engineMu.Lock()
 				defer h.engineMu.Unlock()
 
@@ -472,6 +496,7 @@ func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.
 				return nil
 			},
 			Released: func(e dag.Event, peer string, err error) {
+				tracing.FinishEvent(e.ID(), err)
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming event rejected", "event", e.ID().String(), "creator", e.Creator(), "err", err)
 					h.removePeer(peer)
@@ -943,7 +968,18 @@ func (h *handler) handleEvents(p *peer, events dag.Events, ordered bool) {
 	notifyAnnounces := func(ids hash.Events) {
 		_ = h.dagFetcher.NotifyAnnounces(peer.id, eventIDsToInterfaces(ids), now, requestEvents)
 	}
-	_ = h.dagProcessor.Enqueue(peer.id, notTooHigh, ordered, notifyAnnounces, nil)
+	if tracing.Enabled() {
+		for _, e := range notTooHigh {
+			tracing.StartEvent(e.ID(), peer.id)
+			tracing.OpenEventStage(e.ID(), "dagprocessor")
+		}
+	}
+	err := h.dagProcessor.Enqueue(peer.id, notTooHigh, ordered, notifyAnnounces, nil)
+	if err != nil && tracing.Enabled() {
+		for _, e := range notTooHigh {
+			tracing.FinishEvent(e.ID(), err)
+		}
+	}
 }
 
 // handleMsg is invoked whenever an inbound message is received from a remote
@@ -1222,7 +1258,7 @@ func (h *handler) handleMsg(p *peer) error {
 		}
 
 	case msg.Code == BRsStreamResponse:
-		if !h.syncStatus.AcceptBlockRecords() {
+		if !h.acceptsBRs() {
 			break
 		}
 
@@ -1237,8 +1273,11 @@ func (h *handler) handleMsg(p *peer) error {
 
 		var last idx.Block
 		if len(chunk.BRs) != 0 {
-			_ = h.brProcessor.Enqueue(p.id, chunk.BRs, msgSize, nil)
 			last = chunk.BRs[len(chunk.BRs)-1].Idx
+			chunk.BRs = h.acceptedBRs(chunk.BRs)
+		}
+		if len(chunk.BRs) != 0 {
+			_ = h.brProcessor.Enqueue(p.id, chunk.BRs, msgSize, nil)
 		}
 
 		_ = h.brLeecher.NotifyChunkReceived(chunk.SessionID, last, chunk.Done)
@@ -0,0 +1,84 @@
+//go:build gofuzz
+// +build gofuzz
+
+package gossip
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+var updateFuzzCorpus = flag.Bool("update-fuzz-corpus", false, "regenerate the seed corpus of FuzzHandler")
+
+const handlerFuzzCorpus = "testdata/fuzz/handler"
+
+// handlerFuzzSeeds returns a raw and a structured message of every code
+func handlerFuzzSeeds(f *handlerFuzzer) map[string][]byte {
+	r := rand.New(rand.NewSource(0))
+	seeds := make(map[string][]byte)
+	for i, m := range fuzzMsgs {
+		data := make([]byte, 256)
+		r.Read(data)
+		seeds[fmt.Sprintf("%02d-structured", m.code)] = append([]byte{byte(i), 1}, data...)
+
+		val := m.new()
+		(&fuzzReader{data: data, events: f.events}).fill(reflect.ValueOf(val).Elem())
+		raw, err := rlp.EncodeToBytes(val)
+		if err != nil {
+			panic(err)
+		}
+		seeds[fmt.Sprintf("%02d-raw", m.code)] = append([]byte{byte(i), 0}, raw...)
+	}
+	return seeds
+}
+
+func TestHandlerFuzzCorpus(t *testing.T) {
+	require := require.New(t)
+
+	f, err := newHandlerFuzzer()
+	require.NoError(err)
+	defer f.Close()
+
+	if *updateFuzzCorpus {
+		require.NoError(os.RemoveAll(handlerFuzzCorpus))
+		require.NoError(os.MkdirAll(handlerFuzzCorpus, 0755))
+		for name, data := range handlerFuzzSeeds(f) {
+			require.NoError(ioutil.WriteFile(filepath.Join(handlerFuzzCorpus, name), data, 0644))
+		}
+	}
+
+	files, err := ioutil.ReadDir(handlerFuzzCorpus)
+	require.NoError(err)
+	require.Len(files, 2*len(fuzzMsgs))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(filepath.Join(handlerFuzzCorpus, file.Name()))
+		require.NoError(err)
+		_, err = f.Fuzz(data)
+		require.NoError(err, file.Name())
+	}
+}
+
+func TestHandlerFuzzRandom(t *testing.T) {
+	require := require.New(t)
+
+	f, err := newHandlerFuzzer()
+	require.NoError(err)
+	defer f.Close()
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		data := make([]byte, r.Intn(1024))
+		r.Read(data)
+		_, err = f.Fuzz(data)
+		require.NoError(err, "input %x", data)
+	}
+}
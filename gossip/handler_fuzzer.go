@@ -0,0 +1,512 @@
+//go:build gofuzz
+// +build gofuzz
+
+package gossip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/abft"
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/dag"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/gossip/emitter"
+	"go-galaxy/gossip/protocols/blockrecords/brstream"
+	"go-galaxy/gossip/protocols/blockvotes/bvstream"
+	"go-galaxy/gossip/protocols/dag/dagstream"
+	"go-galaxy/gossip/protocols/epochpacks/epstream"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	"go-galaxy/inter/validatorpk"
+	"go-galaxy/utils"
+	"go-galaxy/utils/adapters/vecmt2dagidx"
+	"go-galaxy/valkeystore"
+	"go-galaxy/vecmt"
+)
+
+const (
+	fuzzHot      int = 1  // if the fuzzer should increase priority of the given input during subsequent fuzzing;
+	fuzzCold     int = -1 // if the input must not be added to corpus even if gives new coverage;
+	fuzzNoMatter int = 0  // otherwise.
+)
+
+const (
+	// fuzzDeadlockTimeout is greater than the default MsgsSemaphoreTimeout
+	fuzzDeadlockTimeout = 20 * time.Second
+	// fuzzMaxAlloc is the allowed memory size of a decoded message, besides fuzzMaxAllocPerByte per message byte
+	fuzzMaxAlloc        = 1024 * 1024
+	fuzzMaxAllocPerByte = 64
+	// fuzzMaxItems limits the number of items in the structured messages
+	fuzzMaxItems = 8
+)
+
+var (
+	errFuzzDeadlock  = errors.New("handler is deadlocked")
+	errFuzzAlloc     = errors.New("too many allocations")
+	errFuzzResponse  = errors.New("response is too large")
+	errFuzzEmptyData = errors.New("empty data")
+)
+
+// fuzzMsgs are all the message codes of the protocol, with constructors of the structured messages
+var fuzzMsgs = []struct {
+	code uint64
+	new  func() interface{}
+}{
+	{HandshakeMsg, func() interface{} { return new(handshakeData) }},
+	{ProgressMsg, func() interface{} { return new(PeerProgress) }},
+	{EvmTxsMsg, func() interface{} { return new(types.Transactions) }},
+	{NewEvmTxHashesMsg, func() interface{} { return new([]common.Hash) }},
+	{GetEvmTxsMsg, func() interface{} { return new([]common.Hash) }},
+	{NewEventIDsMsg, func() interface{} { return new(hash.Events) }},
+	{GetEventsMsg, func() interface{} { return new(hash.Events) }},
+	{EventsMsg, func() interface{} { return new(inter.EventPayloads) }},
+	{RequestEventsStream, func() interface{} { return new(dagstream.Request) }},
+	{EventsStreamResponse, func() interface{} { return new(dagChunk) }},
+	{RequestBVsStream, func() interface{} { return new(bvstream.Request) }},
+	{BVsStreamResponse, func() interface{} { return new(bvsChunk) }},
+	{RequestBRsStream, func() interface{} { return new(brstream.Request) }},
+	{BRsStreamResponse, func() interface{} { return new(brsChunk) }},
+	{RequestEPsStream, func() interface{} { return new(epstream.Request) }},
+	{EPsStreamResponse, func() interface{} { return new(epsChunk) }},
+}
+
+// handlerFuzzer feeds p2p messages to a running handler of a service with a real store.
+// The first byte of fuzzing data selects the message code, the second byte selects either
+// a raw message, or a structured message which is built from the rest of data.
+type handlerFuzzer struct {
+	svc    *Service
+	txpool *evmcore.TxPool
+	peer   *peer
+	rw     *fuzzMsgReadWriter
+	// events of the store, which are referenced by the structured messages
+	events hash.Events
+}
+
+func newHandlerFuzzer() (*handlerFuzzer, error) {
+	const (
+		genesisStakers = 3
+		genesisBalance = 1e18
+		genesisStake   = 2 * 4e6
+	)
+
+	genStore := makegenesis.FakeGenesisStore(2, genesisStakers, utils.ToUnit(genesisBalance), utils.ToUnit(genesisStake))
+	genesis := genStore.GetGenesis()
+
+	config := DefaultConfig(cachescale.Identity)
+	store := NewMemStore()
+	blockProc := DefaultBlockProc(genesis)
+	_, err := store.ApplyGenesis(blockProc, genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &handlerFuzzer{}
+	engine, vecClock, err := newFuzzEngine(store)
+	if err != nil {
+		return nil, err
+	}
+	f.svc, err = newService(config, store, blockProc, engine, vecClock, func(reader evmcore.StateReader) TxPool {
+		txPoolConfig := evmcore.DefaultTxPoolConfig
+		txPoolConfig.Journal = ""
+		f.txpool = evmcore.NewTxPool(txPoolConfig, store.GetRules().EvmChainConfig(), reader)
+		return f.txpool
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = engine.Bootstrap(f.svc.GetConsensusCallbacks())
+	if err != nil {
+		return nil, err
+	}
+	_ = store.GenerateSnapshotAt(common.Hash(store.GetBlockState().FinalizedStateRoot), false)
+	f.svc.blockProcTasks.Start(1)
+
+	// fill the store with events and blocks
+	valKeystore := valkeystore.NewDefaultMemKeystore()
+	for _, v := range genesis.Validators {
+		cfg := emitter.DefaultConfig()
+		cfg.Validator = emitter.ValidatorConfig{
+			ID:     v.ID,
+			PubKey: v.PubKey,
+		}
+		cfg.EmitIntervals = emitter.EmitIntervals{}
+		_ = valKeystore.Add(v.PubKey, crypto.FromECDSA(makegenesis.FakeKey(v.ID)), validatorpk.FakePassword)
+		_ = valKeystore.Unlock(v.PubKey, validatorpk.FakePassword)
+		em := emitter.NewEmitter(cfg, f.svc.EmitterWorld(valkeystore.NewSigner(valKeystore)))
+		f.svc.RegisterEmitter(em)
+		em.Start()
+	}
+	for i := 0; i < 20; i++ {
+		for _, em := range f.svc.emitters {
+			_, _ = em.EmitEvent()
+		}
+		f.svc.WaitBlockEnd()
+	}
+	f.svc.store.ForEachEvent(0, func(e *inter.EventPayload) bool {
+		f.events = append(f.events, e.ID())
+		return true
+	})
+
+	h := f.svc.handler
+	h.Start(3)
+	h.syncStatus.MarkMaybeSynced()
+
+	f.rw = &fuzzMsgReadWriter{}
+	f.peer = newPeer(ProtocolVersion, p2p.NewPeer(enode.ID{1}, "fuzzer", []p2p.Cap{}), f.rw, config.Protocol.PeerCache)
+	if err := h.peers.RegisterPeer(f.peer, nil); err != nil {
+		return nil, err
+	}
+	_ = h.dagLeecher.RegisterPeer(f.peer.id)
+	_ = h.epLeecher.RegisterPeer(f.peer.id)
+	_ = h.bvLeecher.RegisterPeer(f.peer.id)
+	_ = h.brLeecher.RegisterPeer(f.peer.id)
+
+	return f, nil
+}
+
+// fuzzEventSource provides the events of the store to the consensus engine
+type fuzzEventSource struct {
+	*Store
+}
+
+func (s *fuzzEventSource) GetEvent(id hash.Event) dag.Event {
+	e := s.Store.GetEvent(id)
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// newFuzzEngine makes the consensus engine of the store, which panics on errors to make them visible to the fuzzer
+func newFuzzEngine(gdb *Store) (*abft.Lachesis, *vecmt.Index, error) {
+	crit := func(err error) {
+		panic(err)
+	}
+	cdb := abft.NewMemStore()
+	err := cdb.ApplyGenesis(&abft.Genesis{
+		Epoch:      gdb.GetEpoch(),
+		Validators: gdb.GetValidators(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	vecClock := vecmt.NewIndex(crit, vecmt.LiteConfig())
+	engine := abft.NewLachesis(cdb, &fuzzEventSource{gdb}, vecmt2dagidx.Wrap(vecClock), crit, abft.LiteConfig())
+	return engine, vecClock, nil
+}
+
+// Close stops the handler and the service.
+func (f *handlerFuzzer) Close() {
+	f.svc.handler.Stop()
+	for _, em := range f.svc.emitters {
+		em.Stop()
+	}
+	f.txpool.Stop()
+	f.svc.WaitBlockEnd()
+	f.svc.store.Close()
+}
+
+// Fuzz handles a message made of the data. It returns a non-nil error if the handler
+// has deadlocked, the message is decoded into too much memory or a too large response is sent.
+// The memory is bounded per message rather than by the process-wide allocation counters,
+// which are affected by the background loops of the service.
+func (f *handlerFuzzer) Fuzz(data []byte) (int, error) {
+	code, payload, err := f.newMsg(data)
+	if err != nil {
+		return fuzzCold, nil
+	}
+	msg := &p2p.Msg{
+		Code:    code,
+		Size:    uint32(len(payload)),
+		Payload: bytes.NewReader(payload),
+	}
+	f.rw.msg = msg
+
+	if size := fuzzDecodedSize(code, payload); size > fuzzMaxAlloc+fuzzMaxAllocPerByte*uint64(len(payload)) {
+		return fuzzHot, fmt.Errorf("%w: %d bytes for message %d of %d bytes", errFuzzAlloc, size, msg.Code, msg.Size)
+	}
+
+	handled := make(chan error, 1)
+	go func() {
+		handled <- f.svc.handler.handleMsg(f.peer)
+	}()
+	select {
+	case err = <-handled:
+	case <-time.After(fuzzDeadlockTimeout):
+		return fuzzHot, fmt.Errorf("%w: message %d isn't handled", errFuzzDeadlock, msg.Code)
+	}
+	if size := f.rw.maxResponse(); size > protocolMaxMsgSize {
+		return fuzzHot, fmt.Errorf("%w: %d bytes", errFuzzResponse, size)
+	}
+
+	// the asynchronous processing must not keep the engine locked
+	locked := make(chan struct{})
+	go func() {
+		f.svc.engineMu.Lock()
+		f.svc.engineMu.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(fuzzDeadlockTimeout):
+		return fuzzHot, fmt.Errorf("%w: engine is locked after message %d", errFuzzDeadlock, msg.Code)
+	}
+
+	if err != nil {
+		return fuzzNoMatter, nil
+	}
+	return fuzzHot, nil
+}
+
+func (f *handlerFuzzer) newMsg(data []byte) (uint64, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, errFuzzEmptyData
+	}
+	m := fuzzMsgs[int(data[0])%len(fuzzMsgs)]
+	structured := data[1]%2 == 1
+	data = data[2:]
+
+	if structured {
+		val := m.new()
+		r := &fuzzReader{data: data, events: f.events}
+		r.fill(reflect.ValueOf(val).Elem())
+		raw, err := rlp.EncodeToBytes(val)
+		if err != nil {
+			return 0, nil, err
+		}
+		data = raw
+	}
+
+	return m.code, data, nil
+}
+
+// fuzzDecodedSize returns the memory size of the message payload decoded the same way as by the handler,
+// or 0 if the payload isn't decodable
+func fuzzDecodedSize(code uint64, payload []byte) uint64 {
+	for _, m := range fuzzMsgs {
+		if m.code != code {
+			continue
+		}
+		val := m.new()
+		if rlp.DecodeBytes(payload, val) != nil {
+			return 0
+		}
+		return fuzzMemorySize(reflect.ValueOf(val))
+	}
+	return 0
+}
+
+// fuzzMemorySize estimates the memory size of the value including the memory referenced by it
+func fuzzMemorySize(v reflect.Value) uint64 {
+	size := uint64(v.Type().Size())
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			size += fuzzMemorySize(v.Elem())
+		}
+	case reflect.String:
+		size += uint64(v.Len())
+	case reflect.Slice:
+		size += uint64(v.Cap()) * uint64(v.Type().Elem().Size())
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			size += fuzzMemorySize(v.Index(i)) - uint64(v.Index(i).Type().Size())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			size += fuzzMemorySize(v.Field(i)) - uint64(v.Field(i).Type().Size())
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			size += fuzzMemorySize(iter.Key()) + fuzzMemorySize(iter.Value())
+		}
+	}
+	return size
+}
+
+// fuzzMsgReadWriter returns the fuzzed message, and discards the responses
+type fuzzMsgReadWriter struct {
+	msg *p2p.Msg
+
+	mu       sync.Mutex
+	response uint32
+}
+
+func (rw *fuzzMsgReadWriter) ReadMsg() (p2p.Msg, error) {
+	return *rw.msg, nil
+}
+
+func (rw *fuzzMsgReadWriter) WriteMsg(msg p2p.Msg) error {
+	rw.mu.Lock()
+	if msg.Size > rw.response {
+		rw.response = msg.Size
+	}
+	rw.mu.Unlock()
+	_, err := ioutil.ReadAll(msg.Payload)
+	return err
+}
+
+// maxResponse returns size of the largest response since the previous call
+func (rw *fuzzMsgReadWriter) maxResponse() uint32 {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	size := rw.response
+	rw.response = 0
+	return size
+}
+
+// fuzzReader fills values with the fuzzing data, zeros are read after the data is exhausted
+type fuzzReader struct {
+	data   []byte
+	events hash.Events
+}
+
+func (r *fuzzReader) read(n int) []byte {
+	b := make([]byte, n)
+	r.data = r.data[copy(b, r.data):]
+	return b
+}
+
+func (r *fuzzReader) byte() byte {
+	return r.read(1)[0]
+}
+
+func (r *fuzzReader) uint64(size int) uint64 {
+	b := make([]byte, 8)
+	copy(b[8-size:], r.read(size))
+	return binary.BigEndian.Uint64(b)
+}
+
+var (
+	hashEventType   = reflect.TypeOf(hash.Event{})
+	bigIntType      = reflect.TypeOf(big.Int{})
+	transactionType = reflect.TypeOf(types.Transaction{})
+	eventType       = reflect.TypeOf(inter.EventPayload{})
+)
+
+func (r *fuzzReader) fill(v reflect.Value) {
+	switch v.Type() {
+	case hashEventType:
+		// refer to an existing event in a half of cases
+		if b := r.byte(); b%2 == 0 && len(r.events) != 0 {
+			v.Set(reflect.ValueOf(r.events[int(b/2)%len(r.events)]))
+			return
+		}
+	case bigIntType:
+		v.Set(reflect.ValueOf(*new(big.Int).SetUint64(r.uint64(8))))
+		return
+	case transactionType:
+		var tx types.LegacyTx
+		r.fill(reflect.ValueOf(&tx).Elem())
+		v.Set(reflect.ValueOf(*types.NewTx(&tx)))
+		return
+	case eventType:
+		v.Set(reflect.ValueOf(*r.event()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.byte()%2 == 1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(r.uint64(int(v.Type().Size())))
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(r.uint64(int(v.Type().Size()))))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.fill(v.Index(i))
+		}
+	case reflect.Slice:
+		n := int(r.byte()) % fuzzMaxItems
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			n = int(r.byte())
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			// nil items are not encodable
+			if item := v.Index(i); item.Kind() == reflect.Ptr {
+				item.Set(reflect.New(item.Type().Elem()))
+				r.fill(item.Elem())
+			} else {
+				r.fill(item)
+			}
+		}
+	case reflect.Ptr:
+		if r.byte()%4 == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		r.fill(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				r.fill(v.Field(i))
+			}
+		}
+	}
+}
+
+func (r *fuzzReader) event() *inter.EventPayload {
+	var (
+		e       inter.MutableEventPayload
+		parents hash.Events
+		txs     types.Transactions
+		gas     inter.GasPowerLeft
+		bvs     inter.LlrBlockVotes
+		ev      inter.LlrEpochVote
+		sig     inter.Signature
+	)
+	e.SetVersion(r.byte() % 2)
+	e.SetEpoch(idx.Epoch(r.uint64(1)))
+	e.SetSeq(idx.Event(r.uint64(1)))
+	e.SetLamport(idx.Lamport(r.uint64(1)))
+	e.SetCreator(idx.ValidatorID(r.uint64(1) % 4))
+	r.fill(reflect.ValueOf(&parents).Elem())
+	e.SetParents(parents)
+	// parents cannot have higher Lamport time, otherwise event isn't serializable
+	for _, p := range parents {
+		if e.Lamport() < p.Lamport() {
+			e.SetLamport(p.Lamport())
+		}
+	}
+	e.SetCreationTime(inter.Timestamp(r.uint64(8)))
+	e.SetMedianTime(inter.Timestamp(r.uint64(8)))
+	e.SetExtra(r.read(int(r.byte() % 16)))
+	e.SetGasPowerUsed(r.uint64(4))
+	r.fill(reflect.ValueOf(&gas).Elem())
+	e.SetGasPowerLeft(gas)
+	r.fill(reflect.ValueOf(&txs).Elem())
+	e.SetTxs(txs)
+	if e.Version() == 1 {
+		r.fill(reflect.ValueOf(&bvs).Elem())
+		e.SetBlockVotes(bvs)
+		r.fill(reflect.ValueOf(&ev).Elem())
+		if ev.Vote == hash.Zero {
+			ev = inter.LlrEpochVote{}
+		}
+		e.SetEpochVote(ev)
+	}
+	e.SetPayloadHash(inter.CalcPayloadHash(&e))
+	r.fill(reflect.ValueOf(&sig).Elem())
+	e.SetSig(sig)
+	return e.Build()
+}
@@ -42,6 +42,7 @@ import (
 	"go-galaxy/gossip/blockproc/eventmodule"
 	"go-galaxy/gossip/blockproc/evmmodule"
 	"go-galaxy/gossip/blockproc/sealmodule"
+	"go-galaxy/gossip/blockproc/tokenindex"
 	"go-galaxy/gossip/blockproc/verwatcher"
 	"go-galaxy/gossip/emitter"
 	"go-galaxy/gossip/filters"
@@ -121,6 +122,7 @@ type Service struct {
 	engineMu            *sync.RWMutex
 	emitters            []*emitter.Emitter
 	txpool              TxPool
+	txTracker           *txTracker
 	heavyCheckReader    HeavyCheckReader
 	gasPowerCheckReader GasPowerCheckReader
 	checkers            *eventcheck.Checkers
@@ -129,6 +131,9 @@ type Service struct {
 	// version watcher
 	verWatcher *verwatcher.VerWarcher
 
+	// token transfers indexer, nil if disabled
+	tokenIndexer *tokenindex.Indexer
+
 	blockProcWg        sync.WaitGroup
 	blockProcTasks     *workers.Workers
 	blockProcTasksDone chan struct{}
@@ -142,6 +147,8 @@ type Service struct {
 
 	gpo *gasprice.Oracle
 
+	filterAPI *filters.PublicFilterAPI
+
 	// application protocol
 	handler *handler
 
@@ -157,6 +164,17 @@ type Service struct {
 
 	tflusher PeriodicFlusher
 
+	evmPruning struct {
+		quit chan struct{}
+		wg   sync.WaitGroup
+	}
+
+	freezing struct {
+		trigger chan struct{}
+		quit    chan struct{}
+		wg      sync.WaitGroup
+	}
+
 	logger.Instance
 }
 
@@ -223,6 +241,7 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 	// create tx pool
 	stateReader := svc.GetEvmStateReader()
 	svc.txpool = newTxPool(stateReader)
+	svc.txTracker = newTxTracker(svc.txpool)
 
 	// init dialCandidates
 	dnsclient := dnsdisc.NewClient(dnsdisc.Config{})
@@ -264,9 +283,14 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 
 	// create API backend
 	svc.EthAPI = &EthAPIBackend{config.ExtRPCEnabled, svc, stateReader, txSigner, config.AllowUnprotectedTxs}
+	svc.filterAPI = filters.NewPublicFilterAPI(svc.EthAPI, config.FilterAPI)
 
-	svc.verWatcher = verwatcher.New(config.VersionWatcher, verwatcher.NewStore(store.table.NetworkVersion))
+	svc.verWatcher = verwatcher.New(config.VersionWatcher, verwatcher.NewStore(store.table.NetworkVersion), store)
+	if config.TokenIndex {
+		svc.tokenIndexer = tokenindex.New(store.TokenIndex())
+	}
 	svc.tflusher = svc.makePeriodicFlusher()
+	svc.freezing.trigger = make(chan struct{}, 1)
 
 	return svc, nil
 }
@@ -350,7 +374,7 @@ func MakeProtocols(svc *Service, backend *handler, disc enode.Iterator) []p2p.Pr
 			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
 				// wait until handler has started
 				backend.started.Wait()
-				peer := newPeer(version, p, rw, backend.config.Protocol.PeerCache)
+				peer := newPeer(version, p, rw, backend.peerCacheConfig())
 				defer peer.Close()
 
 				select {
@@ -400,7 +424,7 @@ func (s *Service) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.EthAPI, s.config.FilterAPI),
+			Service:   s.filterAPI,
 			Public:    true,
 		}, {
 			Namespace: "eth",
@@ -418,6 +442,14 @@ func (s *Service) APIs() []rpc.API {
 	return apis
 }
 
+// UpdateConfig applies the params, which are safe to change while the service runs:
+// GPO, FilterAPI and Protocol.PeerCache. Changes of other params are ignored.
+func (s *Service) UpdateConfig(config Config) {
+	s.gpo.SetConfig(config.GPO)
+	s.filterAPI.SetConfig(config.FilterAPI)
+	s.handler.setPeerCacheConfig(config.Protocol.PeerCache)
+}
+
 // Start method invoked when the node is ready to start the service.
 func (s *Service) Start() error {
 	// start tflusher before starting snapshots generation
@@ -435,6 +467,21 @@ func (s *Service) Start() error {
 	}
 	_ = s.store.GenerateSnapshotAt(common.Hash(root), true)
 
+	// start online EVM state pruning
+	s.evmPruning.quit = make(chan struct{})
+	if s.config.EvmPruningPeriod != 0 || s.store.evm.IsPruningInterrupted() {
+		s.evmPruning.wg.Add(1)
+		go s.evmPruningLoop()
+	}
+
+	// start moving final data into freezer, catch up with the sealed epochs first
+	s.freezing.quit = make(chan struct{})
+	if s.store.freezer != nil {
+		s.freezing.wg.Add(1)
+		go s.freezingLoop()
+		s.triggerFreezing()
+	}
+
 	// start blocks processor
 	s.blockProcTasks.Start(1)
 
@@ -448,6 +495,7 @@ func (s *Service) Start() error {
 	}
 
 	s.verWatcher.Start()
+	s.txTracker.Start()
 
 	return nil
 }
@@ -460,6 +508,7 @@ func (s *Service) WaitBlockEnd() {
 // Stop method invoked when the node terminates the service.
 func (s *Service) Stop() error {
 	defer log.Info("Galaxy service stopped")
+	s.txTracker.Stop()
 	s.verWatcher.Stop()
 	for _, em := range s.emitters {
 		em.Stop()
@@ -474,6 +523,13 @@ func (s *Service) Stop() error {
 	s.eventMux.Stop()
 	// it's safe to stop tflusher only before locking engineMu
 	s.tflusher.Stop()
+	// stop EVM pruning before locking engineMu, it will be resumed after restart
+	close(s.evmPruning.quit)
+	s.evmPruning.wg.Wait()
+	s.store.evm.StopPruning()
+	// stop freezing before locking engineMu, it will catch up after restart
+	close(s.freezing.quit)
+	s.freezing.wg.Wait()
 
 	// flush the state at exit, after all the routines stopped
 	s.engineMu.Lock()
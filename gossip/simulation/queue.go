@@ -0,0 +1,59 @@
+package simulation
+
+import (
+	"container/heap"
+	"time"
+)
+
+// task is an action scheduled at a virtual time
+type task struct {
+	at  time.Time
+	seq uint64
+	fn  func() error
+}
+
+// taskQueue is a priority queue of tasks, ordered by time and then by the scheduling order
+type taskQueue struct {
+	tasks []*task
+	seq   uint64
+}
+
+func (q *taskQueue) Len() int {
+	return len(q.tasks)
+}
+
+func (q *taskQueue) Less(i, j int) bool {
+	if !q.tasks[i].at.Equal(q.tasks[j].at) {
+		return q.tasks[i].at.Before(q.tasks[j].at)
+	}
+	return q.tasks[i].seq < q.tasks[j].seq
+}
+
+func (q *taskQueue) Swap(i, j int) {
+	q.tasks[i], q.tasks[j] = q.tasks[j], q.tasks[i]
+}
+
+func (q *taskQueue) Push(x interface{}) {
+	q.tasks = append(q.tasks, x.(*task))
+}
+
+func (q *taskQueue) Pop() interface{} {
+	last := q.tasks[len(q.tasks)-1]
+	q.tasks[len(q.tasks)-1] = nil
+	q.tasks = q.tasks[:len(q.tasks)-1]
+	return last
+}
+
+// schedule adds a task to the queue
+func (q *taskQueue) schedule(at time.Time, fn func() error) {
+	q.seq++
+	heap.Push(q, &task{at: at, seq: q.seq, fn: fn})
+}
+
+// next removes the earliest task from the queue, or returns nil if it's later than the deadline
+func (q *taskQueue) next(deadline time.Time) *task {
+	if q.Len() == 0 || q.tasks[0].at.After(deadline) {
+		return nil
+	}
+	return heap.Pop(q).(*task)
+}
@@ -0,0 +1,354 @@
+// Package simulation runs a network of validator nodes under a virtual clock and a simulated
+// message bus with injected faults: latency, message drops and reorders, partitions and crashes.
+// Forking (doublesigning) validators are simulated by running multiple nodes of the same validator.
+//
+// All the nodes are driven from a single goroutine, and the schedule of emitting and delivering
+// is derived from the seed. The nodes are expected to use the network's Clock and Config.NodeRand
+// as the only sources of time and randomness, so that the same seed results in the same blocks.
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+
+	"go-galaxy/inter"
+)
+
+// Node is a validator node under simulation.
+// It doesn't need to be safe for concurrent use, as all the calls are made from Network.Run.
+type Node interface {
+	// Validator returns ID of the node's validator.
+	Validator() idx.ValidatorID
+	// Emit tries to create a new event, it returns nil if the node decided not to emit.
+	Emit() (*inter.EventPayload, error)
+	// Receive connects an event received from the network. The node is expected to buffer
+	// events with missing parents and to ignore events which aren't relevant anymore.
+	Receive(e *inter.EventPayload) error
+	// HasEvent returns true if the event is connected.
+	HasEvent(id hash.Event) bool
+	// Epoch returns the current epoch.
+	Epoch() idx.Epoch
+	// LastBlock returns index of the latest decided block.
+	LastBlock() idx.Block
+	// BlockHash returns hash of a decided block, or nil if it isn't decided.
+	BlockHash(n idx.Block) *hash.Hash
+}
+
+// Clock is a virtual clock, which is advanced only by Network.Run.
+type Clock struct {
+	now time.Time
+}
+
+// NewClock creates a clock starting at the given time.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the current virtual time.
+func (c *Clock) Now() time.Time {
+	return c.now
+}
+
+// Config is the configuration of the simulated network.
+type Config struct {
+	Seed int64
+
+	// EmitInterval is an average interval between emitting attempts of a node
+	EmitInterval time.Duration
+	// Latency is the minimum delivery time of a message
+	Latency time.Duration
+	// Jitter is the maximum random addition to the latency
+	Jitter time.Duration
+	// DropProb is the probability of a message to be lost
+	DropProb float64
+	// ReorderProb is the probability of a message to be delayed for up to 10 latencies,
+	// so that it's likely to be overtaken by the next messages
+	ReorderProb float64
+	// SyncInterval is the interval of re-sending events which peers are missing,
+	// it's the only way for nodes to recover the lost messages
+	SyncInterval time.Duration
+}
+
+// NodeRand returns a source of randomness of the i-th node, which is derived from the seed.
+func (c Config) NodeRand(i int) *rand.Rand {
+	return rand.New(rand.NewSource(c.Seed + int64(i) + 1))
+}
+
+// DefaultConfig returns a configuration of a reliable network with a small latency.
+func DefaultConfig() Config {
+	return Config{
+		Seed:         1,
+		EmitInterval: 200 * time.Millisecond,
+		Latency:      20 * time.Millisecond,
+		Jitter:       20 * time.Millisecond,
+		SyncInterval: time.Second,
+	}
+}
+
+// Stats is the statistics of the network messages.
+type Stats struct {
+	Emitted   uint64
+	Sent      uint64
+	Delivered uint64
+	Dropped   uint64
+}
+
+// Network is a simulated network of nodes.
+type Network struct {
+	config Config
+	clock  *Clock
+	rand   *rand.Rand
+	queue  taskQueue
+
+	nodes []Node
+	alive []bool
+	group []int
+
+	// events emitted by all the nodes, in the order of emitting
+	events []*inter.EventPayload
+	stats  Stats
+}
+
+// New creates a network of the nodes. Nodes are addressed by their index.
+func New(config Config, clock *Clock, nodes []Node) *Network {
+	n := &Network{
+		config: config,
+		clock:  clock,
+		rand:   rand.New(rand.NewSource(config.Seed)),
+		nodes:  nodes,
+		alive:  make([]bool, len(nodes)),
+		group:  make([]int, len(nodes)),
+	}
+	for i := range nodes {
+		n.alive[i] = true
+		n.scheduleEmit(i, time.Duration(n.rand.Int63n(int64(config.EmitInterval))))
+	}
+	if config.SyncInterval != 0 {
+		n.scheduleSync()
+	}
+	return n
+}
+
+// Clock returns the virtual clock of the network.
+func (n *Network) Clock() *Clock {
+	return n.clock
+}
+
+// Stats returns the statistics of the network messages.
+func (n *Network) Stats() Stats {
+	return n.stats
+}
+
+// Node returns the i-th node.
+func (n *Network) Node(i int) Node {
+	return n.nodes[i]
+}
+
+// After schedules an action in the given virtual time from now, e.g. a fault injection.
+func (n *Network) After(d time.Duration, fn func()) {
+	n.queue.schedule(n.clock.Now().Add(d), func() error {
+		fn()
+		return nil
+	})
+}
+
+// Crash stops the i-th node. A crashed node neither emits nor receives events, but keeps its state.
+func (n *Network) Crash(i int) {
+	n.alive[i] = false
+}
+
+// Recover restarts a crashed node.
+func (n *Network) Recover(i int) {
+	n.alive[i] = true
+}
+
+// Partition splits the network into groups of nodes, such that messages are delivered only within a group.
+// The nodes which aren't listed form a separate group.
+func (n *Network) Partition(groups ...[]int) {
+	for i := range n.group {
+		n.group[i] = 0
+	}
+	for g, nodes := range groups {
+		for _, i := range nodes {
+			n.group[i] = g + 1
+		}
+	}
+}
+
+// Heal removes the partitions.
+func (n *Network) Heal() {
+	n.Partition()
+}
+
+func (n *Network) connected(from, to int) bool {
+	return n.alive[from] && n.alive[to] && n.group[from] == n.group[to]
+}
+
+// Run runs the simulation for the given virtual time.
+func (n *Network) Run(d time.Duration) error {
+	deadline := n.clock.Now().Add(d)
+	for t := n.queue.next(deadline); t != nil; t = n.queue.next(deadline) {
+		n.clock.now = t.at
+		if err := t.fn(); err != nil {
+			return err
+		}
+	}
+	n.clock.now = deadline
+	return nil
+}
+
+func (n *Network) scheduleEmit(i int, after time.Duration) {
+	n.queue.schedule(n.clock.Now().Add(after), func() error {
+		// the next attempt is in [0.5, 1.5) of the interval
+		n.scheduleEmit(i, n.config.EmitInterval/2+time.Duration(n.rand.Int63n(int64(n.config.EmitInterval))))
+		if !n.alive[i] {
+			return nil
+		}
+		e, err := n.nodes[i].Emit()
+		if err != nil {
+			return fmt.Errorf("node %d failed to emit: %v", i, err)
+		}
+		if e == nil {
+			return nil
+		}
+		n.stats.Emitted++
+		n.events = append(n.events, e)
+		for to := range n.nodes {
+			if to != i {
+				n.send(i, to, e)
+			}
+		}
+		return nil
+	})
+}
+
+func (n *Network) send(from, to int, e *inter.EventPayload) {
+	n.stats.Sent++
+	if !n.connected(from, to) || n.rand.Float64() < n.config.DropProb {
+		n.stats.Dropped++
+		return
+	}
+	delay := n.config.Latency
+	if n.config.Jitter != 0 {
+		delay += time.Duration(n.rand.Int63n(int64(n.config.Jitter)))
+	}
+	if n.rand.Float64() < n.config.ReorderProb {
+		delay += time.Duration(n.rand.Int63n(10*int64(n.config.Latency) + 1))
+	}
+	n.queue.schedule(n.clock.Now().Add(delay), func() error {
+		if !n.connected(from, to) {
+			n.stats.Dropped++
+			return nil
+		}
+		n.stats.Delivered++
+		if err := n.nodes[to].Receive(e); err != nil {
+			return fmt.Errorf("node %d failed to receive event %s from node %d: %v", to, e.ID().String(), from, err)
+		}
+		return nil
+	})
+}
+
+func (n *Network) scheduleSync() {
+	n.queue.schedule(n.clock.Now().Add(n.config.SyncInterval), func() error {
+		n.scheduleSync()
+		n.sync()
+		return nil
+	})
+}
+
+// sync sends to every node the events of its epoch which it's missing, from any connected peer which has them
+func (n *Network) sync() {
+	// forget the events which are irrelevant for all the nodes
+	minEpoch := idx.Epoch(0)
+	for i, node := range n.nodes {
+		if i == 0 || node.Epoch() < minEpoch {
+			minEpoch = node.Epoch()
+		}
+	}
+	for len(n.events) != 0 && n.events[0].Epoch() < minEpoch {
+		n.events = n.events[1:]
+	}
+
+	for to, node := range n.nodes {
+		if !n.alive[to] {
+			continue
+		}
+		epoch := node.Epoch()
+		for _, e := range n.events {
+			if e.Epoch() != epoch || node.HasEvent(e.ID()) {
+				continue
+			}
+			for from, peer := range n.nodes {
+				if from != to && n.connected(from, to) && peer.HasEvent(e.ID()) {
+					n.send(from, to, e)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Progress returns the latest block which is decided by all the alive nodes.
+func (n *Network) Progress() idx.Block {
+	progress := idx.Block(0)
+	first := true
+	for i, node := range n.nodes {
+		if !n.alive[i] {
+			continue
+		}
+		if first || node.LastBlock() < progress {
+			progress = node.LastBlock()
+			first = false
+		}
+	}
+	return progress
+}
+
+// CheckLiveness checks that every alive node has decided at least minBlocks blocks after the given block.
+func (n *Network) CheckLiveness(since idx.Block, minBlocks idx.Block) error {
+	for i, node := range n.nodes {
+		if !n.alive[i] {
+			continue
+		}
+		if last := node.LastBlock(); last < since+minBlocks {
+			return fmt.Errorf("node %d decided only %d blocks after block %d, expected at least %d", i, last-since, since, minBlocks)
+		}
+	}
+	return nil
+}
+
+// CheckSafety checks that all the nodes, including the crashed ones, decided the same blocks.
+func (n *Network) CheckSafety() error {
+	maxBlock := idx.Block(0)
+	for _, node := range n.nodes {
+		if node.LastBlock() > maxBlock {
+			maxBlock = node.LastBlock()
+		}
+	}
+	for b := idx.Block(1); b <= maxBlock; b++ {
+		var (
+			expected *hash.Hash
+			first    int
+		)
+		for i, node := range n.nodes {
+			if node.LastBlock() < b {
+				continue
+			}
+			got := node.BlockHash(b)
+			if got == nil {
+				return fmt.Errorf("node %d has no block %d, while its last block is %d", i, b, node.LastBlock())
+			}
+			if expected == nil {
+				expected = got
+				first = i
+			} else if *got != *expected {
+				return fmt.Errorf("nodes %d and %d decided different blocks %d: %s != %s", first, i, b, expected.String(), got.String())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package simulation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/inter"
+)
+
+// testNode is a chain of self-events, which connects events of the other validators in any order
+type testNode struct {
+	validator idx.ValidatorID
+	clock     *Clock
+	last      *inter.EventPayload
+	events    map[hash.Event]bool
+	created   map[hash.Event]time.Time
+	received  []string
+	blocks    []hash.Hash
+}
+
+func newTestNode(validator idx.ValidatorID, clock *Clock) *testNode {
+	return &testNode{
+		validator: validator,
+		clock:     clock,
+		events:    make(map[hash.Event]bool),
+		created:   make(map[hash.Event]time.Time),
+	}
+}
+
+func (n *testNode) Validator() idx.ValidatorID {
+	return n.validator
+}
+
+func (n *testNode) Emit() (*inter.EventPayload, error) {
+	me := &inter.MutableEventPayload{}
+	me.SetEpoch(1)
+	me.SetCreator(n.validator)
+	me.SetSeq(1)
+	me.SetLamport(1)
+	if n.last != nil {
+		me.SetSeq(n.last.Seq() + 1)
+		me.SetLamport(n.last.Lamport() + 1)
+		me.SetParents(hash.Events{n.last.ID()})
+	}
+	me.SetCreationTime(inter.Timestamp(n.clock.Now().UnixNano()))
+	me.SetPayloadHash(inter.CalcPayloadHash(me))
+	e := me.Build()
+	n.last = e
+	n.events[e.ID()] = true
+	n.created[e.ID()] = n.clock.Now()
+	return e, nil
+}
+
+func (n *testNode) Receive(e *inter.EventPayload) error {
+	n.received = append(n.received, fmt.Sprintf("%s@%d", e.ID().String(), n.clock.Now().UnixNano()))
+	n.events[e.ID()] = true
+	return nil
+}
+
+func (n *testNode) HasEvent(id hash.Event) bool {
+	return n.events[id]
+}
+
+func (n *testNode) Epoch() idx.Epoch {
+	return 1
+}
+
+func (n *testNode) LastBlock() idx.Block {
+	return idx.Block(len(n.blocks))
+}
+
+func (n *testNode) BlockHash(b idx.Block) *hash.Hash {
+	if b == 0 || b > n.LastBlock() {
+		return nil
+	}
+	return &n.blocks[b-1]
+}
+
+func newTestNetwork(config Config, num int) (*Network, []*testNode) {
+	clock := NewClock(time.Unix(1000, 0))
+	testNodes := make([]*testNode, num)
+	nodes := make([]Node, num)
+	for i := range nodes {
+		testNodes[i] = newTestNode(idx.ValidatorID(i+1), clock)
+		nodes[i] = testNodes[i]
+	}
+	return New(config, clock, nodes), testNodes
+}
+
+func TestNetworkDeterminism(t *testing.T) {
+	config := DefaultConfig()
+	config.DropProb = 0.1
+	config.ReorderProb = 0.1
+
+	net1, nodes1 := newTestNetwork(config, 4)
+	require.NoError(t, net1.Run(10*time.Second))
+	net2, nodes2 := newTestNetwork(config, 4)
+	require.NoError(t, net2.Run(10*time.Second))
+
+	require.Equal(t, net1.Stats(), net2.Stats())
+	require.NotZero(t, net1.Stats().Dropped)
+	for i := range nodes1 {
+		require.NotEmpty(t, nodes1[i].received)
+		require.Equal(t, nodes1[i].received, nodes2[i].received)
+	}
+	require.Equal(t, time.Unix(1010, 0), net1.Clock().Now())
+}
+
+func TestNetworkFaults(t *testing.T) {
+	require := require.New(t)
+
+	config := DefaultConfig()
+	config.DropProb = 0.3
+	config.ReorderProb = 0.3
+	net, nodes := newTestNetwork(config, 4)
+
+	// checks that the nodes have all the events of the given creators, created in the time range
+	hasEvents := func(nodes []*testNode, creators []*testNode, from, to time.Time) bool {
+		for _, creator := range creators {
+			for _, node := range nodes {
+				for id := range creator.events {
+					created := creator.created[id]
+					if !created.Before(from) && created.Before(to) && !node.events[id] {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+
+	// lost messages are recovered by syncing
+	start := net.Clock().Now()
+	require.NoError(net.Run(10 * time.Second))
+	require.NotZero(net.Stats().Dropped)
+	require.True(hasEvents(nodes, nodes, start, net.Clock().Now().Add(-3*time.Second)))
+
+	// partitioned nodes don't exchange events
+	net.Partition([]int{0})
+	partitioned := net.Clock().Now()
+	require.NoError(net.Run(5 * time.Second))
+	require.False(hasEvents(nodes[:1], nodes[1:], partitioned, net.Clock().Now()))
+	require.False(hasEvents(nodes[1:], nodes[:1], partitioned, net.Clock().Now()))
+	require.True(hasEvents(nodes[1:], nodes[1:], partitioned, net.Clock().Now().Add(-3*time.Second)))
+
+	// crashed node neither emits nor receives events
+	net.Heal()
+	net.Crash(3)
+	crashed := net.Clock().Now()
+	received := len(nodes[3].received)
+	require.NoError(net.Run(5 * time.Second))
+	require.Equal(received, len(nodes[3].received))
+	require.True(nodes[3].last.CreationTime().Time().Before(crashed))
+
+	// all the nodes catch up after healing
+	net.Recover(3)
+	recovered := net.Clock().Now()
+	require.NoError(net.Run(5 * time.Second))
+	require.True(hasEvents(nodes, nodes, start, recovered))
+}
+
+func TestNetworkCheckSafety(t *testing.T) {
+	net, nodes := newTestNetwork(DefaultConfig(), 3)
+	nodes[0].blocks = []hash.Hash{{1}, {2}, {3}}
+	nodes[1].blocks = []hash.Hash{{1}, {2}}
+	net.Crash(2)
+	require.NoError(t, net.CheckSafety())
+	require.Equal(t, idx.Block(2), net.Progress())
+	require.NoError(t, net.CheckLiveness(0, 2))
+	require.Error(t, net.CheckLiveness(1, 2))
+
+	nodes[2].blocks = []hash.Hash{{1}, {3}}
+	require.Error(t, net.CheckSafety())
+}
@@ -0,0 +1,323 @@
+package gossip
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy"
+	"go-galaxy/gossip/emitter"
+	"go-galaxy/gossip/simulation"
+	"go-galaxy/integration/makegenesis"
+	"go-galaxy/inter"
+	"go-galaxy/inter/validatorpk"
+	"go-galaxy/utils"
+	"go-galaxy/valkeystore"
+)
+
+// simNode is a gossip service with a single emitter, which is driven by the simulation.
+// Events signatures aren't checked, as all the events are created by the simulated validators.
+type simNode struct {
+	*Service
+	validator idx.ValidatorID
+	clock     *simulation.Clock
+	emitter   *emitter.Emitter
+	// received events with missing parents
+	pending map[hash.Event]*inter.EventPayload
+}
+
+type simEmitterWorldExternal struct {
+	emitter.External
+}
+
+func (em simEmitterWorldExternal) Broadcast(*inter.EventPayload) {}
+
+func newSimNode(genesis galaxy.Genesis, validator idx.ValidatorID, clock *simulation.Clock, r *rand.Rand) *simNode {
+	store := NewMemStore()
+	blockProc := DefaultBlockProc(genesis)
+	_, err := store.ApplyGenesis(blockProc, genesis)
+	if err != nil {
+		panic(err)
+	}
+	engine, vecClock := makeTestEngine(store)
+
+	n := &simNode{
+		validator: validator,
+		clock:     clock,
+		pending:   make(map[hash.Event]*inter.EventPayload),
+	}
+	txPool := &dummyTxPool{}
+	n.Service, err = newService(DefaultConfig(cachescale.Identity), store, blockProc, engine, vecClock, func(_ evmcore.StateReader) TxPool {
+		return txPool
+	})
+	if err != nil {
+		panic(err)
+	}
+	txPool.signer = n.EthAPI.signer
+	err = engine.Bootstrap(n.GetConsensusCallbacks())
+	if err != nil {
+		panic(err)
+	}
+
+	pubkey := genesis.Validators.Map()[validator].PubKey
+	valKeystore := valkeystore.NewDefaultMemKeystore()
+	_ = valKeystore.Add(pubkey, crypto.FromECDSA(makegenesis.FakeKey(validator)), validatorpk.FakePassword)
+	_ = valKeystore.Unlock(pubkey, validatorpk.FakePassword)
+
+	cfg := emitter.DefaultConfig()
+	cfg.Validator = emitter.ValidatorConfig{
+		ID:     validator,
+		PubKey: pubkey,
+	}
+	// emitting is paced by the simulation
+	cfg.EmitIntervals = emitter.EmitIntervals{}
+	cfg.MaxParents = idx.Event(len(genesis.Validators)/2 + 1)
+	world := n.EmitterWorld(valkeystore.NewSigner(valKeystore))
+	world.External = simEmitterWorldExternal{world.External}
+	world.Clock = clock
+	world.Rand = r
+	n.emitter = emitter.NewEmitter(cfg, world)
+	n.RegisterEmitter(n.emitter)
+	n.emitter.Start()
+
+	_ = store.GenerateSnapshotAt(common.Hash(store.GetBlockState().FinalizedStateRoot), false)
+	n.blockProcTasks.Start(1)
+	n.verWatcher.Start()
+	return n
+}
+
+func (n *simNode) Close() {
+	n.emitter.Stop()
+	n.verWatcher.Stop()
+	n.store.Close()
+	n.tflusher.Stop()
+}
+
+func (n *simNode) Validator() idx.ValidatorID {
+	return n.validator
+}
+
+func (n *simNode) Emit() (*inter.EventPayload, error) {
+	e, err := n.emitter.EmitEvent()
+	n.WaitBlockEnd()
+	return e, err
+}
+
+func (n *simNode) Receive(e *inter.EventPayload) error {
+	n.pending[e.ID()] = e
+	// connect all the pending events which have their parents connected,
+	// in the order of IDs (i.e. of Lamport times) to not depend on the map iteration order
+	for connected := true; connected; {
+		connected = false
+		ids := make(hash.Events, 0, len(n.pending))
+		for id := range n.pending {
+			ids = append(ids, id)
+		}
+		hash.OrderedEvents(ids).ByEpochAndLamport()
+		for _, id := range ids {
+			e := n.pending[id]
+			if e.Epoch() != n.store.GetEpoch() || n.store.HasEvent(id) {
+				delete(n.pending, id)
+				continue
+			}
+			parents, ok := n.parentsOf(e)
+			if !ok {
+				continue
+			}
+			delete(n.pending, id)
+			if err := n.connect(e, parents); err != nil {
+				return err
+			}
+			connected = true
+		}
+	}
+	return nil
+}
+
+func (n *simNode) parentsOf(e *inter.EventPayload) (inter.EventIs, bool) {
+	parents := make(inter.EventIs, len(e.Parents()))
+	for i, p := range e.Parents() {
+		parent := n.store.GetEvent(p)
+		if parent == nil {
+			return nil, false
+		}
+		parents[i] = parent
+	}
+	return parents, true
+}
+
+// connect performs the same checks and processing as the DAG processor, except for the signature check
+func (n *simNode) connect(e *inter.EventPayload, parents inter.EventIs) error {
+	if err := n.checkers.Basiccheck.Validate(e); err != nil {
+		return err
+	}
+	if err := n.checkers.Epochcheck.Validate(e); err != nil {
+		return err
+	}
+	if err := n.checkers.Parentscheck.Validate(e, parents); err != nil {
+		return err
+	}
+	var selfParent inter.EventI
+	if e.SelfParent() != nil {
+		selfParent = parents[0]
+	}
+	if err := n.checkers.Gaspowercheck.Validate(e, selfParent); err != nil {
+		return err
+	}
+
+	n.engineMu.Lock()
+	err := n.processEvent(e)
+	n.engineMu.Unlock()
+	n.WaitBlockEnd()
+	return err
+}
+
+func (n *simNode) HasEvent(id hash.Event) bool {
+	return n.store.HasEvent(id)
+}
+
+func (n *simNode) Epoch() idx.Epoch {
+	return n.store.GetEpoch()
+}
+
+func (n *simNode) LastBlock() idx.Block {
+	return n.store.GetLatestBlockIndex()
+}
+
+func (n *simNode) BlockHash(b idx.Block) *hash.Hash {
+	record := n.store.GetFullBlockRecord(b)
+	if record == nil {
+		return nil
+	}
+	h := record.Hash()
+	return &h
+}
+
+// newSimNetwork starts a node per validator, plus the additional nodes of the forking validators
+func newSimNetwork(t *testing.T, config simulation.Config, validatorsNum idx.Validator, forking ...idx.ValidatorID) *simulation.Network {
+	genStore := makegenesis.FakeGenesisStore(1, validatorsNum, utils.ToUnit(genesisBalance), utils.ToUnit(genesisStake))
+	clock := simulation.NewClock(genStore.GetGenesis().Time.Time())
+
+	var nodes []simulation.Node
+	validators := make([]idx.ValidatorID, 0, int(validatorsNum)+len(forking))
+	for i := idx.Validator(1); i <= validatorsNum; i++ {
+		validators = append(validators, idx.ValidatorID(i))
+	}
+	for i, validator := range append(validators, forking...) {
+		genesis := genStore.GetGenesis()
+		genesis.Rules.Epochs.MaxEpochDuration = inter.Timestamp(10 * time.Second)
+		genesis.Rules.Blocks.MaxEmptyBlockSkipPeriod = 0
+		node := newSimNode(genesis, validator, clock, config.NodeRand(i))
+		t.Cleanup(node.Close)
+		nodes = append(nodes, node)
+	}
+	return simulation.New(config, clock, nodes)
+}
+
+func TestSimulationUnreliableNetwork(t *testing.T) {
+	require := require.New(t)
+
+	config := simulation.DefaultConfig()
+	config.Latency = 50 * time.Millisecond
+	config.Jitter = 100 * time.Millisecond
+	config.DropProb = 0.1
+	config.ReorderProb = 0.2
+	net := newSimNetwork(t, config, 4)
+
+	require.NoError(net.Run(30 * time.Second))
+	require.NoError(net.CheckSafety())
+	require.NoError(net.CheckLiveness(0, 10))
+	require.NotZero(net.Stats().Dropped)
+	// epochs are sealed
+	require.Greater(uint32(net.Node(0).Epoch()), uint32(2))
+}
+
+func TestSimulationPartition(t *testing.T) {
+	require := require.New(t)
+
+	net := newSimNetwork(t, simulation.DefaultConfig(), 4)
+	require.NoError(net.Run(5 * time.Second))
+	progress := net.Progress()
+	require.NoError(net.CheckLiveness(0, 1))
+
+	// neither half has a quorum
+	net.Partition([]int{0, 1}, []int{2, 3})
+	require.NoError(net.Run(10 * time.Second))
+	stuck := net.Progress()
+	// only the blocks which were in flight may be decided
+	require.NoError(net.Run(10 * time.Second))
+	require.Equal(stuck, net.Progress())
+	require.NoError(net.CheckSafety())
+
+	net.Heal()
+	require.NoError(net.Run(10 * time.Second))
+	require.NoError(net.CheckSafety())
+	require.NoError(net.CheckLiveness(progress, 5))
+}
+
+func TestSimulationCrash(t *testing.T) {
+	require := require.New(t)
+
+	net := newSimNetwork(t, simulation.DefaultConfig(), 4)
+	require.NoError(net.Run(5 * time.Second))
+
+	// the rest of validators have a quorum
+	net.Crash(3)
+	progress := net.Progress()
+	require.NoError(net.Run(10 * time.Second))
+	require.NoError(net.CheckLiveness(progress, 5))
+	require.Less(uint64(net.Node(3).LastBlock()), uint64(net.Progress()))
+
+	// the crashed node catches up
+	net.Recover(3)
+	progress = net.Progress()
+	require.NoError(net.Run(15 * time.Second))
+	require.NoError(net.CheckSafety())
+	require.NoError(net.CheckLiveness(progress, 5))
+}
+
+func TestSimulationForkingValidator(t *testing.T) {
+	require := require.New(t)
+
+	// validator 4 runs 2 instances, which emit events in parallel until they see each other's events
+	net := newSimNetwork(t, simulation.DefaultConfig(), 4, 4)
+	require.NoError(net.Run(20 * time.Second))
+	require.NoError(net.CheckSafety())
+	require.NoError(net.CheckLiveness(0, 10))
+	// the cheater is excluded from the next epochs
+	require.False(net.Node(0).(*simNode).store.GetValidators().Exists(4))
+}
+
+func TestSimulationDeterminism(t *testing.T) {
+	require := require.New(t)
+
+	config := simulation.DefaultConfig()
+	config.Seed = 7
+	config.DropProb = 0.1
+	config.ReorderProb = 0.2
+
+	run := func() []hash.Hash {
+		net := newSimNetwork(t, config, 4)
+		require.NoError(net.Run(20 * time.Second))
+		require.NoError(net.CheckSafety())
+		node := net.Node(0)
+		blocks := make([]hash.Hash, 0, node.LastBlock())
+		for b := idx.Block(1); b <= node.LastBlock(); b++ {
+			blocks = append(blocks, *node.BlockHash(b))
+		}
+		return blocks
+	}
+
+	blocks := run()
+	require.NotEmpty(blocks)
+	// the same seed results in the same blocks
+	require.Equal(blocks, run())
+}
@@ -14,10 +14,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
+	"go-galaxy/gossip/blockproc/tokenindex"
 	"go-galaxy/gossip/evmstore"
 	"go-galaxy/gossip/sfcapi"
 	"go-galaxy/logger"
 	"go-galaxy/utils/adapters/snap2kvdb"
+	"go-galaxy/utils/freezer"
 	"go-galaxy/utils/rlpstore"
 	"go-galaxy/utils/switchable"
 )
@@ -31,6 +33,7 @@ type Store struct {
 	snapshotedDB *switchable.Snapshot
 	evm          *evmstore.Store
 	sfcapi       *sfcapi.Store
+	tokenIndex   *tokenindex.Store
 	table        struct {
 		Version kvdb.Store `table:"_"`
 
@@ -49,8 +52,10 @@ type Store struct {
 		NetworkVersion kvdb.Store `table:"V"`
 
 		// API-only
-		BlockHashes kvdb.Store `table:"B"`
-		SfcAPI      kvdb.Store `table:"S"`
+		BlockHashes     kvdb.Store `table:"B"`
+		SfcAPI          kvdb.Store `table:"S"`
+		ReceiptsDigests kvdb.Store `table:"R"`
+		TokenIndex      kvdb.Store `table:"T"`
 
 		LlrState           kvdb.Store `table:"!"`
 		LlrBlockResults    kvdb.Store `table:"@"`
@@ -61,8 +66,14 @@ type Store struct {
 		LlrEpochVoteIndex  kvdb.Store `table:"&"`
 		LlrLastBlockVotes  kvdb.Store `table:"*"`
 		LlrLastEpochVote   kvdb.Store `table:"("`
+
+		// Freezer
+		FreezerState kvdb.Store `table:"Z"`
+		FrozenEvents kvdb.Store `table:"z"`
 	}
 
+	freezer *freezer.Freezer
+
 	prevFlushTime time.Time
 
 	epochStore atomic.Value
@@ -120,6 +131,11 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 	s.initCache()
 	s.evm = evmstore.NewStore(s.mainDB, cfg.EVM)
 	s.sfcapi = sfcapi.NewStore(s.table.SfcAPI)
+	s.tokenIndex = tokenindex.NewStore(s.table.TokenIndex)
+
+	if err := s.openFreezer(); err != nil {
+		s.Log.Crit("Failed to open freezer", "dir", s.cfg.Freezer.Dir, "err", err)
+	}
 
 	if err := s.migrateData(); err != nil {
 		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
@@ -157,6 +173,7 @@ func (s *Store) Close() {
 	_ = s.mainDB.Close()
 	s.sfcapi.Close()
 	_ = s.closeEpochStore()
+	s.closeFreezer()
 }
 
 func (s *Store) IsCommitNeeded() bool {
@@ -219,6 +236,10 @@ func (s *Store) EvmStore() *evmstore.Store {
 	return s.evm
 }
 
+func (s *Store) TokenIndex() *tokenindex.Store {
+	return s.tokenIndex
+}
+
 func (s *Store) CaptureEvmKvdbSnapshot() {
 	if s.evm.Snaps == nil {
 		return
@@ -47,6 +47,15 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	if block == nil {
+		if raw := s.getFrozenBlockRLP(n); raw != nil {
+			block = &inter.Block{}
+			err := rlp.DecodeBytes(raw, block)
+			if err != nil {
+				s.Log.Crit("Failed to decode block", "err", err)
+			}
+		}
+	}
 
 	// Add to LRU cache.
 	if block != nil {
@@ -58,19 +67,58 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 
 func (s *Store) HasBlock(n idx.Block) bool {
 	has, _ := s.table.Blocks.Has(n.Bytes())
+	if !has {
+		has = s.getFrozenBlockRLP(n) != nil
+	}
 	return has
 }
 
 func (s *Store) ForEachBlock(fn func(index idx.Block, block *inter.Block)) {
-	it := s.table.Blocks.NewIterator(nil, nil)
-	defer it.Release()
-	for it.Next() {
+	s.forEachBlockRLP(0, func(n idx.Block, raw rlp.RawValue) bool {
 		var block inter.Block
-		err := rlp.DecodeBytes(it.Value(), &block)
+		err := rlp.DecodeBytes(raw, &block)
 		if err != nil {
 			s.Log.Crit("Failed to decode block", "err", err)
 		}
-		fn(idx.BytesToBlock(it.Key()), &block)
+		fn(n, &block)
+		return true
+	})
+}
+
+// forEachBlockRLP iterates blocks in order, merging the frozen blocks and the blocks in DB.
+// Blocks in DB have priority over the frozen ones.
+func (s *Store) forEachBlockRLP(start idx.Block, fn func(n idx.Block, raw rlp.RawValue) bool) {
+	it := s.table.Blocks.NewIterator(nil, start.Bytes())
+	defer it.Release()
+	hasNext := it.Next()
+
+	frozen, head := uint64(start), uint64(0)
+	if s.freezer != nil {
+		t := s.freezer.Table(freezerBlocksTable)
+		if first := t.First(); frozen < first {
+			frozen = first
+		}
+		head = t.Head()
+	}
+	for hasNext || frozen < head {
+		if hasNext {
+			n := idx.BytesToBlock(it.Key())
+			if frozen >= head || uint64(n) <= frozen {
+				if !fn(n, it.Value()) {
+					return
+				}
+				if uint64(n) == frozen {
+					frozen++
+				}
+				hasNext = it.Next()
+				continue
+			}
+		}
+		raw := s.retrieveFrozen(freezerBlocksTable, frozen)
+		if raw != nil && !fn(idx.Block(frozen), raw) {
+			return
+		}
+		frozen++
 	}
 }
 
@@ -132,6 +132,15 @@ func (s *Store) GetRules() galaxy.Rules {
 	return s.GetEpochState().Rules
 }
 
+// GetPendingRules retrieves network rules which will be applied in the next epoch
+func (s *Store) GetPendingRules() galaxy.Rules {
+	bs, es := s.GetBlockEpochState()
+	if bs.DirtyRules != nil {
+		return *bs.DirtyRules
+	}
+	return es.Rules
+}
+
 // GetEpochRules retrieves current network rules and epoch atomically
 func (s *Store) GetEpochRules() (galaxy.Rules, idx.Epoch) {
 	es := s.GetEpochState()
@@ -7,9 +7,9 @@ package gossip
 import (
 	"bytes"
 
+	"github.com/deamchain/deam-v2-base/common/bigendian"
 	"github.com/deamchain/deam-v2-base/hash"
 	"github.com/deamchain/deam-v2-base/inter/idx"
-	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"go-galaxy/inter"
@@ -23,6 +23,12 @@ func (s *Store) DelEvent(id hash.Event) {
 	if err != nil {
 		s.Log.Crit("Failed to delete key", "err", err)
 	}
+	if s.freezer != nil {
+		err = s.table.FrozenEvents.Delete(key)
+		if err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
 
 	// Remove from LRU cache.
 	s.cache.Events.Remove(id)
@@ -48,12 +54,7 @@ func (s *Store) GetEventPayload(id hash.Event) *inter.EventPayload {
 		return ev.(*inter.EventPayload)
 	}
 
-	key := id.Bytes()
-	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
-
-	if w != nil {
-		fixEventTxHashes(w)
-	}
+	w := s.getEventPayload(id)
 
 	// Put event to LRU cache.
 	if w != nil {
@@ -72,12 +73,10 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 		return ev.(*inter.Event)
 	}
 
-	key := id.Bytes()
-	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	w := s.getEventPayload(id)
 	if w == nil {
 		return nil
 	}
-	fixEventTxHashes(w)
 
 	eh := w.Event
 
@@ -88,40 +87,66 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 	return &eh
 }
 
-func (s *Store) forEachEvent(it ethdb.Iterator, onEvent func(event *inter.EventPayload) bool) {
-	for it.Next() {
-		event := &inter.EventPayload{}
-		err := rlp.DecodeBytes(it.Value(), event)
+// getEventPayload returns stored event, either from DB or from freezer.
+func (s *Store) getEventPayload(id hash.Event) *inter.EventPayload {
+	w, _ := s.rlp.Get(s.table.Events, id.Bytes(), &inter.EventPayload{}).(*inter.EventPayload)
+	if w == nil {
+		raw := s.getFrozenEventRLP(id)
+		if raw == nil {
+			return nil
+		}
+		w = &inter.EventPayload{}
+		err := rlp.DecodeBytes(raw, w)
 		if err != nil {
 			s.Log.Crit("Failed to decode event", "err", err)
 		}
+	}
+	fixEventTxHashes(w)
+	return w
+}
 
-		if !onEvent(event) {
+func (s *Store) forEachEvent(prefix, start []byte, onEvent func(event *inter.EventPayload) bool) {
+	s.forEachEventRLP(prefix, start, func(_ hash.Event, raw rlp.RawValue) bool {
+		event := &inter.EventPayload{}
+		err := rlp.DecodeBytes(raw, event)
+		if err != nil {
+			s.Log.Crit("Failed to decode event", "err", err)
+		}
+		return onEvent(event)
+	})
+}
+
+// forEachEventRLP iterates frozen events first, as all of them precede the events in DB.
+func (s *Store) forEachEventRLP(prefix, start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
+	if s.freezer != nil {
+		it := s.table.FrozenEvents.NewIterator(prefix, start)
+		defer it.Release()
+		for it.Next() {
+			raw := s.retrieveFrozen(freezerEventsTable, bigendian.BytesToUint64(it.Value()))
+			if !onEvent(hash.BytesToEvent(it.Key()), raw) {
+				return
+			}
+		}
+	}
+	it := s.table.Events.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
 			return
 		}
 	}
 }
 
 func (s *Store) ForEachEpochEvent(epoch idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
-	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(epoch.Bytes(), nil, onEvent)
 }
 
 func (s *Store) ForEachEvent(start idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
-	it := s.table.Events.NewIterator(nil, start.Bytes())
-	defer it.Release()
-	s.forEachEvent(it, onEvent)
+	s.forEachEvent(nil, start.Bytes(), onEvent)
 }
 
 func (s *Store) ForEachEventRLP(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
-	it := s.table.Events.NewIterator(nil, start)
-	defer it.Release()
-	for it.Next() {
-		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
-			return
-		}
-	}
+	s.forEachEventRLP(nil, start, onEvent)
 }
 
 func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix []byte) hash.Events {
@@ -130,6 +155,13 @@ func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix
 	prefix.Write(hashPrefix)
 	res := make(hash.Events, 0, 10)
 
+	if s.freezer != nil {
+		it := s.table.FrozenEvents.NewIterator(prefix.Bytes(), nil)
+		for it.Next() {
+			res = append(res, hash.BytesToEvent(it.Key()))
+		}
+		it.Release()
+	}
 	it := s.table.Events.NewIterator(prefix.Bytes(), nil)
 	defer it.Release()
 	for it.Next() {
@@ -147,12 +179,18 @@ func (s *Store) GetEventPayloadRLP(id hash.Event) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if data == nil {
+		data = s.getFrozenEventRLP(id)
+	}
 	return data
 }
 
 // HasEvent returns true if event exists.
 func (s *Store) HasEvent(h hash.Event) bool {
 	has, _ := s.table.Events.Has(h.Bytes())
+	if !has && s.freezer != nil {
+		has, _ = s.table.FrozenEvents.Has(h.Bytes())
+	}
 	return has
 }
 
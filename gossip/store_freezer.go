@@ -0,0 +1,229 @@
+package gossip
+
+import (
+	"errors"
+
+	"github.com/deamchain/deam-v2-base/common/bigendian"
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-galaxy/utils/freezer"
+)
+
+const (
+	freezerEventsTable   = "events"
+	freezerBlocksTable   = "blocks"
+	freezerReceiptsTable = "receipts"
+)
+
+// freezeItemsLimit is the maximum number of events or blocks frozen at once,
+// so catching up a large DB doesn't block the events processing for long
+var freezeItemsLimit = 10000
+
+// freezerState is the number of committed items of every freezer table.
+// Items above it were appended, but the DB wasn't flushed after that, so they are truncated on start.
+type freezerState struct {
+	Events   uint64
+	Blocks   uint64
+	Receipts uint64
+}
+
+func (s *Store) getFreezerState() *freezerState {
+	st, _ := s.rlp.Get(s.table.FreezerState, []byte("s"), &freezerState{}).(*freezerState)
+	return st
+}
+
+func (s *Store) setFreezerState(st *freezerState) {
+	s.rlp.Set(s.table.FreezerState, []byte("s"), st)
+}
+
+// openFreezer opens the flat-file store of final events, blocks and receipts
+func (s *Store) openFreezer() error {
+	st := s.getFreezerState()
+	if s.cfg.Freezer.Dir == "" {
+		if st != nil {
+			return errors.New("freezer directory isn't specified, but DB contains frozen data")
+		}
+		return nil
+	}
+	f, err := freezer.Open(s.cfg.Freezer.Dir, freezerEventsTable, freezerBlocksTable, freezerReceiptsTable)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		st = &freezerState{}
+	}
+	for name, head := range map[string]uint64{
+		freezerEventsTable:   st.Events,
+		freezerBlocksTable:   st.Blocks,
+		freezerReceiptsTable: st.Receipts,
+	} {
+		if err := f.Table(name).Truncate(head); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	s.freezer = f
+	s.evm.SetReceiptsFreezer(f.Table(freezerReceiptsTable))
+	return nil
+}
+
+func (s *Store) closeFreezer() {
+	if s.freezer == nil {
+		return
+	}
+	if err := s.freezer.Close(); err != nil {
+		s.Log.Error("Failed to close freezer", "err", err)
+	}
+}
+
+// FreezeEpochs moves a batch of events, blocks and receipts of the sealed epochs, except for the Freezer.KeepEpochs latest ones,
+// from the key-value DB into the freezer. Frozen data is still accessible through the Store getters.
+// Returns true if there's more data to freeze.
+func (s *Store) FreezeEpochs() bool {
+	if s.freezer == nil {
+		return false
+	}
+	current := s.GetEpoch()
+	if current <= s.cfg.Freezer.KeepEpochs+1 {
+		return false
+	}
+	target := current - 1 - s.cfg.Freezer.KeepEpochs
+	st := s.getFreezerState()
+	if st == nil {
+		st = &freezerState{}
+	}
+
+	events := s.freezeEvents(target, st)
+	blocks := 0
+	if bs, _ := s.GetHistoryBlockEpochState(target); bs != nil {
+		blocks = s.freezeBlocks(bs.LastBlock.Idx, st)
+	}
+	if events == 0 && blocks == 0 {
+		return false
+	}
+	// DB mustn't refer to the frozen items before they are flushed to disk
+	if err := s.freezer.Sync(); err != nil {
+		s.Log.Crit("Failed to sync freezer", "err", err)
+	}
+	s.setFreezerState(st)
+	s.Log.Info("Moved final data into freezer", "epoch", target, "events", events, "blocks", blocks)
+	return events == freezeItemsLimit || blocks == freezeItemsLimit
+}
+
+// freezeEvents moves events of epochs up to the target into the freezer
+func (s *Store) freezeEvents(target idx.Epoch, st *freezerState) int {
+	t := s.freezer.Table(freezerEventsTable)
+
+	// all the frozen events precede the events in DB, as they are frozen in the order of keys
+	var (
+		ids    []hash.Event
+		events [][]byte
+	)
+	it := s.table.Events.NewIterator(nil, nil)
+	for len(ids) < freezeItemsLimit && it.Next() {
+		id := hash.BytesToEvent(it.Key())
+		if id.Epoch() > target {
+			break
+		}
+		ids = append(ids, id)
+		events = append(events, common.CopyBytes(it.Value()))
+	}
+	it.Release()
+
+	// DB isn't modified while it's iterated
+	for i, id := range ids {
+		if err := t.Append(st.Events, events[i]); err != nil {
+			s.Log.Crit("Failed to freeze event", "event", id.String(), "err", err)
+		}
+		if err := s.table.FrozenEvents.Put(id.Bytes(), bigendian.Uint64ToBytes(st.Events)); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+		if err := s.table.Events.Delete(id.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+		st.Events++
+	}
+	return len(ids)
+}
+
+// freezeBlocks moves blocks and receipts up to the target block into the freezer.
+// Missing blocks (e.g. skipped by snapsync) are frozen as empty items.
+func (s *Store) freezeBlocks(target idx.Block, st *freezerState) int {
+	t := s.freezer.Table(freezerBlocksTable)
+
+	var (
+		indexes []idx.Block
+		blocks  [][]byte
+	)
+	it := s.table.Blocks.NewIterator(nil, idx.Block(st.Blocks).Bytes())
+	for len(indexes) < freezeItemsLimit && it.Next() {
+		n := idx.BytesToBlock(it.Key())
+		if n > target {
+			break
+		}
+		indexes = append(indexes, n)
+		blocks = append(blocks, common.CopyBytes(it.Value()))
+	}
+	it.Release()
+
+	// DB isn't modified while it's iterated
+	for i, n := range indexes {
+		if st.Blocks == 0 {
+			// first frozen block
+			st.Blocks, st.Receipts = uint64(n), uint64(n)
+		}
+		for ; st.Blocks <= uint64(n); st.Blocks++ {
+			var blob []byte
+			if st.Blocks == uint64(n) {
+				blob = blocks[i]
+			}
+			if err := t.Append(st.Blocks, blob); err != nil {
+				s.Log.Crit("Failed to freeze block", "block", st.Blocks, "err", err)
+			}
+			if err := s.evm.FreezeReceipts(idx.Block(st.Receipts)); err != nil {
+				s.Log.Crit("Failed to freeze receipts", "block", st.Receipts, "err", err)
+			}
+			st.Receipts++
+		}
+		if err := s.table.Blocks.Delete(n.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	return len(indexes)
+}
+
+// getFrozenEventRLP returns the event from the freezer, or nil if it isn't frozen
+func (s *Store) getFrozenEventRLP(id hash.Event) []byte {
+	if s.freezer == nil {
+		return nil
+	}
+	pos, err := s.table.FrozenEvents.Get(id.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if pos == nil {
+		return nil
+	}
+	return s.retrieveFrozen(freezerEventsTable, bigendian.BytesToUint64(pos))
+}
+
+// getFrozenBlockRLP returns the block from the freezer, or nil if it isn't frozen
+func (s *Store) getFrozenBlockRLP(n idx.Block) []byte {
+	if s.freezer == nil || !s.freezer.Table(freezerBlocksTable).Has(uint64(n)) {
+		return nil
+	}
+	return s.retrieveFrozen(freezerBlocksTable, uint64(n))
+}
+
+func (s *Store) retrieveFrozen(table string, n uint64) []byte {
+	blob, err := s.freezer.Table(table).Retrieve(n)
+	if err != nil {
+		s.Log.Crit("Failed to retrieve frozen item", "table", table, "item", n, "err", err)
+	}
+	if len(blob) == 0 {
+		return nil
+	}
+	return blob
+}
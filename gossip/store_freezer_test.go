@@ -0,0 +1,159 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/inter/pos"
+	"github.com/deamchain/deam-v2-base/kvdb/flushable"
+	"github.com/deamchain/deam-v2-base/kvdb/leveldb"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"go-galaxy/galaxy"
+	"go-galaxy/inter"
+	"go-galaxy/inter/iblockproc"
+)
+
+func TestStoreFreezeEpochs(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.Freezer.Dir = t.TempDir()
+	cfg.Freezer.KeepEpochs = 1
+	dir := t.TempDir()
+	cache16mb := func(string) int {
+		return 16 * opt.MiB
+	}
+	store := NewStore(flushable.NewSyncedPool(leveldb.NewProducer(dir, cache16mb), []byte{0}), cfg)
+
+	validators := pos.EqualWeightValidators([]idx.ValidatorID{1}, 1)
+	rules := galaxy.FakeNetRules()
+	var events []hash.Event
+	for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 3; lamport++ {
+			me := &inter.MutableEventPayload{}
+			me.SetVersion(1)
+			me.SetEpoch(epoch)
+			me.SetLamport(lamport)
+			me.SetCreator(1)
+			me.SetPayloadHash(inter.CalcPayloadHash(me))
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e.ID())
+		}
+		// block 2 is missing
+		for _, n := range []idx.Block{idx.Block(epoch)*3 - 2, idx.Block(epoch) * 3} {
+			store.SetBlock(n, &inter.Block{Time: inter.Timestamp(n)})
+		}
+		bs := iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: idx.Block(epoch) * 3}}
+		es := iblockproc.EpochState{Epoch: epoch, Validators: validators, Rules: rules}
+		store.SetHistoryBlockEpochState(epoch, bs, es)
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 4, Validators: validators, Rules: rules})
+
+	hotEvents := func() (n int) {
+		it := store.table.Events.NewIterator(nil, nil)
+		defer it.Release()
+		for it.Next() {
+			n++
+		}
+		return n
+	}
+
+	// epochs 1 and 2 are frozen, epoch 3 is kept
+	store.FreezeEpochs()
+	require.Equal(3, hotEvents())
+	require.Equal(uint64(1), store.freezer.Table(freezerBlocksTable).First())
+	require.Equal(uint64(7), store.freezer.Table(freezerBlocksTable).Head())
+	require.Equal(uint64(7), store.freezer.Table(freezerReceiptsTable).Head())
+
+	// frozen data is read transparently
+	for _, id := range events {
+		require.True(store.HasEvent(id))
+		require.NotNil(store.GetEventPayloadRLP(id))
+	}
+	var iterated []hash.Event
+	store.ForEachEvent(0, func(e *inter.EventPayload) bool {
+		iterated = append(iterated, e.ID())
+		return true
+	})
+	require.Equal(events, iterated)
+	require.Equal(hash.Events{events[4]}, store.FindEventHashes(2, 2, nil))
+
+	require.False(store.HasBlock(2))
+	require.Nil(store.GetBlock(2))
+	var blocks []idx.Block
+	store.ForEachBlock(func(n idx.Block, b *inter.Block) {
+		require.Equal(inter.Timestamp(n), b.Time)
+		blocks = append(blocks, n)
+	})
+	require.Equal([]idx.Block{1, 3, 4, 6, 7, 9}, blocks)
+
+	// the frozen items are dropped on restart, if the DB wasn't flushed
+	require.NoError(store.flushDBs())
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 5, Validators: validators, Rules: rules})
+	store.FreezeEpochs()
+	require.Equal(0, hotEvents())
+	store.Close()
+
+	dbs := flushable.NewSyncedPool(leveldb.NewProducer(dir, cache16mb), []byte{0})
+	require.NoError(dbs.Initialize([]string{"gossip"}))
+	store = NewStore(dbs, cfg)
+	defer store.Close()
+	require.Equal(uint64(7), store.freezer.Table(freezerBlocksTable).Head())
+	require.Equal(uint64(6), store.freezer.Table(freezerEventsTable).Head())
+	require.NotNil(store.GetEventPayloadRLP(events[0]))
+	require.NotNil(store.GetEventPayloadRLP(events[8]))
+	require.NotNil(store.GetBlock(1))
+	require.NotNil(store.GetBlock(9))
+}
+
+func TestStoreFreezeEpochsInBatches(t *testing.T) {
+	require := require.New(t)
+
+	defer func(limit int) {
+		freezeItemsLimit = limit
+	}(freezeItemsLimit)
+	freezeItemsLimit = 2
+
+	cfg := LiteStoreConfig()
+	cfg.Freezer.Dir = t.TempDir()
+	cfg.Freezer.KeepEpochs = 0
+	store := NewMemStore()
+	store.cfg = cfg
+	require.NoError(store.openFreezer())
+	defer store.Close()
+
+	validators := pos.EqualWeightValidators([]idx.ValidatorID{1}, 1)
+	rules := galaxy.FakeNetRules()
+	for epoch := idx.Epoch(1); epoch <= 2; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 3; lamport++ {
+			me := &inter.MutableEventPayload{}
+			me.SetVersion(1)
+			me.SetEpoch(epoch)
+			me.SetLamport(lamport)
+			me.SetCreator(1)
+			me.SetPayloadHash(inter.CalcPayloadHash(me))
+			store.SetEvent(me.Build())
+		}
+		for n := idx.Block(epoch)*3 - 2; n <= idx.Block(epoch)*3; n++ {
+			store.SetBlock(n, &inter.Block{Time: inter.Timestamp(n)})
+		}
+		bs := iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: idx.Block(epoch) * 3}}
+		es := iblockproc.EpochState{Epoch: epoch, Validators: validators, Rules: rules}
+		store.SetHistoryBlockEpochState(epoch, bs, es)
+	}
+	store.SetBlockEpochState(iblockproc.BlockState{}, iblockproc.EpochState{Epoch: 3, Validators: validators, Rules: rules})
+
+	// 6 events and 6 blocks are frozen by 2 items at once
+	batches := 0
+	for store.FreezeEpochs() {
+		batches++
+	}
+	require.Equal(3, batches)
+	require.Equal(uint64(6), store.freezer.Table(freezerEventsTable).Head())
+	require.Equal(uint64(7), store.freezer.Table(freezerBlocksTable).Head())
+	require.False(store.FreezeEpochs())
+}
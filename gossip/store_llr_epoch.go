@@ -32,6 +32,13 @@ func (s *Store) iterateEpochVotesRLP(prefix []byte, f func(key []byte, ev rlp.Ra
 	}
 }
 
+// ForEachEpochVoteRLP iterates over the epoch votes for the epoch.
+func (s *Store) ForEachEpochVoteRLP(epoch idx.Epoch, f func(ev rlp.RawValue) bool) {
+	s.iterateEpochVotesRLP(epoch.Bytes(), func(key []byte, ev rlp.RawValue) bool {
+		return f(ev)
+	})
+}
+
 func (s *Store) AddLlrEpochVoteWeight(epoch idx.Epoch, ev hash.Hash, val idx.Validator, vals idx.Validator, diff pos.Weight) pos.Weight {
 	key := append(epoch.Bytes(), ev[:]...)
 	return s.addLlrVoteWeight(s.table.LlrEpochVoteIndex, key, val, vals, diff)
@@ -0,0 +1,59 @@
+package gossip
+
+import (
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"go-galaxy/gossip/evmstore"
+	"go-galaxy/inter"
+)
+
+// ReceiptsDigest is the receipts root and the logs bloom of a block.
+// They aren't a part of the block, so they are calculated from receipts once and stored separately.
+type ReceiptsDigest struct {
+	ReceiptsRoot common.Hash
+	Bloom        types.Bloom
+}
+
+// CalcReceiptsDigest calculates the receipts root and the logs bloom of the block receipts.
+func CalcReceiptsDigest(receipts types.Receipts) ReceiptsDigest {
+	if receipts.Len() == 0 {
+		return ReceiptsDigest{
+			ReceiptsRoot: types.EmptyRootHash,
+		}
+	}
+	return ReceiptsDigest{
+		ReceiptsRoot: types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+		Bloom:        types.CreateBloom(receipts),
+	}
+}
+
+// SetReceiptsDigest stores receipts root and logs bloom of a block.
+func (s *Store) SetReceiptsDigest(n idx.Block, digest ReceiptsDigest) {
+	s.rlp.Set(s.table.ReceiptsDigests, n.Bytes(), &digest)
+}
+
+// GetReceiptsDigest returns stored receipts root and logs bloom of a block.
+func (s *Store) GetReceiptsDigest(n idx.Block) *ReceiptsDigest {
+	digest, _ := s.rlp.Get(s.table.ReceiptsDigests, n.Bytes(), &ReceiptsDigest{}).(*ReceiptsDigest)
+	return digest
+}
+
+// calcStoredReceiptsDigest calculates receipts root and logs bloom of a block from the stored receipts.
+// Returns nil if block or receipts aren't found.
+func (s *Store) calcStoredReceiptsDigest(n idx.Block, block *inter.Block) *ReceiptsDigest {
+	raw, _ := s.evm.GetRawReceipts(n)
+	if raw == nil {
+		return nil
+	}
+	// only tx types are needed to encode receipts, so a signer isn't needed
+	receipts, err := evmstore.UnwrapStorageReceipts(raw, n, nil, common.Hash(block.Atropos), s.GetBlockTxs(n, block))
+	if err != nil {
+		s.Log.Error("Failed to derive receipts", "block", n, "err", err)
+		return nil
+	}
+	digest := CalcReceiptsDigest(receipts)
+	return &digest
+}
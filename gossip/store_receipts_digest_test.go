@@ -0,0 +1,102 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/galaxy"
+	"go-galaxy/inter"
+	"go-galaxy/inter/iblockproc"
+)
+
+func TestStoreReceiptsDigest(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	// empty receipts
+	empty := CalcReceiptsDigest(nil)
+	require.Equal(types.EmptyRootHash, empty.ReceiptsRoot)
+	require.Equal(types.Bloom{}, empty.Bloom)
+
+	// block with txs
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewTransaction(1, common.Address{2}, big.NewInt(1), 21000, big.NewInt(1), nil),
+	}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{{Address: common.Address{3}, Topics: []common.Hash{{4}}}}},
+		{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 42000, Logs: []*types.Log{}},
+	}
+	for _, r := range receipts {
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+	}
+	block := &inter.Block{
+		Atropos: hash.FakeEvent(),
+	}
+	for _, tx := range txs {
+		store.EvmStore().SetTx(tx.Hash(), tx)
+		block.Txs = append(block.Txs, tx.Hash())
+	}
+	n := idx.Block(2)
+	store.SetBlock(n, block)
+
+	// receipts aren't stored yet
+	require.Nil(store.GetReceiptsDigest(n))
+	require.Nil(store.calcStoredReceiptsDigest(n, block))
+
+	store.EvmStore().SetReceipts(n, receipts)
+	digest := store.calcStoredReceiptsDigest(n, block)
+	require.NotNil(digest)
+	require.Equal(types.DeriveSha(receipts, trie.NewStackTrie(nil)), digest.ReceiptsRoot)
+	require.Equal(types.CreateBloom(receipts), digest.Bloom)
+	require.Equal(CalcReceiptsDigest(receipts), *digest)
+}
+
+func TestReceiptsDigestAPI(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+	store.SetBlockEpochState(iblockproc.BlockState{LastBlock: iblockproc.BlockCtx{Idx: 3}}, iblockproc.EpochState{Epoch: 2, Rules: galaxy.FakeNetRules()})
+	b := newTestAPIBackend(store)
+	ctx := context.Background()
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	store.EvmStore().SetTx(tx.Hash(), tx)
+	store.SetBlock(2, &inter.Block{Atropos: hash.FakeEvent(), Txs: []common.Hash{tx.Hash()}})
+	store.SetBlock(3, &inter.Block{Atropos: hash.FakeEvent()})
+
+	// the digest isn't stored until receipts of the block are indexed
+	_, _, err := b.GetReceiptsDigest(ctx, 2)
+	require.EqualError(err, "receipts of block 2 not found")
+	require.Nil(store.GetReceiptsDigest(2))
+
+	receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{{Address: common.Address{3}}}}}
+	receipts[0].Bloom = types.CreateBloom(receipts)
+	store.EvmStore().SetReceipts(2, receipts)
+	root, bloom, err := b.GetReceiptsDigest(ctx, 2)
+	require.NoError(err)
+	require.Equal(CalcReceiptsDigest(receipts), ReceiptsDigest{root, bloom})
+	require.Equal(&ReceiptsDigest{root, bloom}, store.GetReceiptsDigest(2))
+
+	// blocks without txs have no receipts
+	root, bloom, err = b.GetReceiptsDigest(ctx, rpc.LatestBlockNumber)
+	require.NoError(err)
+	require.Equal(types.EmptyRootHash, root)
+	require.Equal(types.Bloom{}, bloom)
+	require.Equal(&ReceiptsDigest{root, bloom}, store.GetReceiptsDigest(3))
+
+	_, _, err = b.GetReceiptsDigest(ctx, 4)
+	require.EqualError(err, "block not found")
+}
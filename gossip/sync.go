@@ -7,7 +7,10 @@ import (
 
 	"github.com/deamchain/deam-v2-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"go-galaxy/inter/ibr"
 )
 
 type syncStage uint32
@@ -29,6 +32,11 @@ const (
 	snapsyncMaxStartAge = 6 * time.Hour
 )
 
+var (
+	backfillLowestBlockGauge = metrics.GetOrRegisterGauge("chain/backfill/lowest", nil)
+	backfillRemainingGauge   = metrics.GetOrRegisterGauge("chain/backfill/remaining", nil)
+)
+
 func (ss *syncStatus) Is(s ...syncStage) bool {
 	self := &ss.stage
 	for _, v := range s {
@@ -230,7 +238,11 @@ func (h *handler) snapsyncStageTick() {
 	// resume events downloading if events sync is enabled
 	if h.syncStatus.Is(ssEvents) {
 		h.dagLeecher.Resume()
-		h.brLeecher.Pause()
+		if h.config.HistoryBackfill && h.hasHistoryGap() {
+			h.brLeecher.Resume()
+		} else {
+			h.brLeecher.Pause()
+		}
 	} else {
 		h.dagLeecher.Pause()
 		h.brLeecher.Resume()
@@ -290,3 +302,44 @@ func (h *handler) snapsyncStateLoop() {
 		}
 	}
 }
+
+// hasHistoryGap returns true if there are missing historical blocks, which may be back-filled from peers.
+func (h *handler) hasHistoryGap() bool {
+	lowest := h.store.GetLlrState().LowestBlockToFill
+	latest := h.store.GetLatestBlockIndex()
+	backfillLowestBlockGauge.Update(int64(lowest))
+	if lowest > latest {
+		backfillRemainingGauge.Update(0)
+		return false
+	}
+	backfillRemainingGauge.Update(int64(latest - lowest + 1))
+	return true
+}
+
+// acceptsBRs returns true if block records may be processed in the current sync stage.
+func (h *handler) acceptsBRs() bool {
+	return h.syncStatus.AcceptBlockRecords() || h.config.HistoryBackfill
+}
+
+// acceptedBRs returns the block records which may be processed in the current sync stage.
+// During events sync only the history may be back-filled, never race with events processing over the new blocks.
+func (h *handler) acceptedBRs(brs []ibr.LlrIdxFullBlockRecord) []ibr.LlrIdxFullBlockRecord {
+	if h.syncStatus.AcceptBlockRecords() {
+		return brs
+	}
+	if !h.config.HistoryBackfill {
+		return nil
+	}
+	return h.historyBRs(brs)
+}
+
+// historyBRs filters out the block records which aren't below the latest processed block.
+func (h *handler) historyBRs(brs []ibr.LlrIdxFullBlockRecord) []ibr.LlrIdxFullBlockRecord {
+	latest := h.store.GetLatestBlockIndex()
+	for i, br := range brs {
+		if br.Idx > latest {
+			return brs[:i]
+		}
+	}
+	return brs
+}
@@ -0,0 +1,83 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/galaxy"
+	"go-galaxy/inter/iblockproc"
+	"go-galaxy/inter/ibr"
+)
+
+func newBackfillTestHandler(latest, lowestToFill idx.Block) *handler {
+	store := NewMemStore()
+	store.SetBlockEpochState(iblockproc.BlockState{
+		LastBlock: iblockproc.BlockCtx{Idx: latest},
+	}, iblockproc.EpochState{
+		Rules: galaxy.FakeNetRules(),
+	})
+	store.setLlrState(LlrState{
+		LowestBlockToFill:   lowestToFill,
+		LowestBlockToDecide: latest + 1,
+	})
+	return &handler{
+		store: store,
+	}
+}
+
+func testBRs(from, to idx.Block) []ibr.LlrIdxFullBlockRecord {
+	brs := make([]ibr.LlrIdxFullBlockRecord, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		brs = append(brs, ibr.LlrIdxFullBlockRecord{Idx: i})
+	}
+	return brs
+}
+
+func brsIndexes(brs []ibr.LlrIdxFullBlockRecord) []idx.Block {
+	res := make([]idx.Block, 0, len(brs))
+	for _, br := range brs {
+		res = append(res, br.Idx)
+	}
+	return res
+}
+
+func TestHasHistoryGap(t *testing.T) {
+	require := require.New(t)
+
+	require.True(newBackfillTestHandler(100, 1).hasHistoryGap())
+	require.True(newBackfillTestHandler(100, 100).hasHistoryGap())
+	require.False(newBackfillTestHandler(100, 101).hasHistoryGap())
+}
+
+func TestHistoryBRs(t *testing.T) {
+	require := require.New(t)
+	h := newBackfillTestHandler(10, 1)
+
+	require.Equal([]idx.Block{5, 6, 7}, brsIndexes(h.historyBRs(testBRs(5, 7))))
+	require.Equal([]idx.Block{8, 9, 10}, brsIndexes(h.historyBRs(testBRs(8, 12))))
+	require.Empty(h.historyBRs(testBRs(11, 12)))
+	require.Empty(h.historyBRs(nil))
+}
+
+func TestAcceptedBRs(t *testing.T) {
+	require := require.New(t)
+	h := newBackfillTestHandler(10, 1)
+
+	// all BRs are accepted before events sync
+	h.syncStatus.Set(ssSnaps)
+	require.True(h.acceptsBRs())
+	require.Equal([]idx.Block{8, 9, 10, 11, 12}, brsIndexes(h.acceptedBRs(testBRs(8, 12))))
+
+	// no BRs are accepted during events sync without history backfill
+	h.syncStatus.Set(ssEvents)
+	require.False(h.acceptsBRs())
+	require.Empty(h.acceptedBRs(testBRs(8, 12)))
+
+	// only history BRs are accepted during events sync with history backfill
+	h.config.HistoryBackfill = true
+	require.True(h.acceptsBRs())
+	require.Equal([]idx.Block{8, 9, 10}, brsIndexes(h.acceptedBRs(testBRs(8, 12))))
+	require.Empty(h.acceptedBRs(testBRs(11, 12)))
+}
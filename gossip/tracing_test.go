@@ -0,0 +1,70 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-galaxy/logger"
+	"go-galaxy/tracing"
+	"go-galaxy/utils"
+)
+
+func TestBlockTracing(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	tracing.SetEnabled(true)
+	defer func() {
+		tracing.SetEnabled(false)
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	}()
+
+	env := newTestEnv(2, 3)
+	defer env.Close()
+
+	tx := env.Transfer(1, 2, utils.ToUnit(1))
+	tracing.StartTx(tx.Hash(), "test")
+	_, err := env.ApplyTxs(nextEpoch, tx)
+	require.NoError(err)
+
+	ended := func(name string) []sdktrace.ReadOnlySpan {
+		res := make([]sdktrace.ReadOnlySpan, 0)
+		for _, s := range recorder.Ended() {
+			if s.Name() == name {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	require.Eventually(func() bool {
+		return len(ended("lifecycle")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	blocks := make(map[trace.SpanID]bool)
+	for _, s := range ended("block") {
+		blocks[s.SpanContext().SpanID()] = true
+	}
+	require.NotEmpty(blocks)
+	for _, name := range []string{"lachesis.decision", "evm.execute", "commit"} {
+		spans := ended(name)
+		require.NotEmpty(spans, name)
+		for _, s := range spans {
+			require.True(blocks[s.Parent().SpanID()], name)
+		}
+	}
+
+	// tx lifecycle is linked to the block which includes it
+	confirmed := ended("confirmed")
+	require.Len(confirmed, 1)
+	require.Equal(ended("lifecycle")[0].SpanContext().SpanID(), confirmed[0].Parent().SpanID())
+	require.Len(confirmed[0].Links(), 1)
+	require.True(blocks[confirmed[0].Links()[0].SpanContext.SpanID()])
+}
@@ -0,0 +1,122 @@
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/inter"
+)
+
+const (
+	// TxTrackerBufferSize is the number of recent txs which are tracked for the tx status API
+	TxTrackerBufferSize = 20000
+)
+
+type seenTx struct {
+	tx   *types.Transaction
+	time time.Time
+}
+
+// txTracker memorizes the recent txs which were seen in the local txpool,
+// the events which carry the recent txs before they are confirmed,
+// and the recent confirmed events with txs which blocks are processed.
+// It isn't persisted, so only txs seen since the node start are tracked.
+type txTracker struct {
+	seen      *lru.Cache // tx hash -> seenTx
+	events    *lru.Cache // tx hash -> event ID
+	confirmed *lru.Cache // event ID -> struct{}
+
+	txpool TxPool
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newTxTracker(txpool TxPool) *txTracker {
+	seen, _ := lru.New(TxTrackerBufferSize)
+	events, _ := lru.New(TxTrackerBufferSize)
+	confirmed, _ := lru.New(TxTrackerBufferSize)
+	return &txTracker{
+		seen:      seen,
+		events:    events,
+		confirmed: confirmed,
+		txpool:    txpool,
+		quit:      make(chan struct{}),
+	}
+}
+
+func (t *txTracker) Start() {
+	newTxsCh := make(chan evmcore.NewTxsNotify, 128)
+	sub := t.txpool.SubscribeNewTxsNotify(newTxsCh)
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case txNotify := <-newTxsCh:
+				t.onNewTxs(txNotify.Txs)
+			case <-sub.Err():
+				return
+			case <-t.quit:
+				return
+			}
+		}
+	}()
+}
+
+func (t *txTracker) Stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+func (t *txTracker) onNewTxs(txs types.Transactions) {
+	now := time.Now()
+	for _, tx := range txs {
+		t.seen.ContainsOrAdd(tx.Hash(), seenTx{tx, now})
+	}
+}
+
+// onNewEvent is safe for concurrent use
+func (t *txTracker) onNewEvent(e inter.EventPayloadI) {
+	for _, tx := range e.Txs() {
+		t.events.ContainsOrAdd(tx.Hash(), e.ID())
+	}
+}
+
+// onEventsConfirmed is called after the block of the confirmed events is processed,
+// so txs of the events are either indexed or skipped. It's safe for concurrent use.
+func (t *txTracker) onEventsConfirmed(events hash.Events) {
+	for _, id := range events {
+		t.confirmed.Add(id, struct{}{})
+	}
+}
+
+// getSeenTx returns a tx which was seen in the txpool, and the time when it was first seen
+func (t *txTracker) getSeenTx(txHash common.Hash) (*types.Transaction, time.Time) {
+	v, ok := t.seen.Get(txHash)
+	if !ok {
+		return nil, time.Time{}
+	}
+	seen := v.(seenTx)
+	return seen.tx, seen.time
+}
+
+// getTxEvent returns the first seen event which carries a tx
+func (t *txTracker) getTxEvent(txHash common.Hash) hash.Event {
+	v, ok := t.events.Get(txHash)
+	if !ok {
+		return hash.ZeroEvent
+	}
+	return v.(hash.Event)
+}
+
+// isEventConfirmed returns true if a recent event was confirmed and its block is processed
+func (t *txTracker) isEventConfirmed(id hash.Event) bool {
+	return t.confirmed.Contains(id)
+}
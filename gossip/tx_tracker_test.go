@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/inter"
+)
+
+func TestTxTracker(t *testing.T) {
+	require := require.New(t)
+
+	txpool := &dummyTxPool{}
+	tracker := newTxTracker(txpool)
+	tracker.Start()
+	defer tracker.Stop()
+
+	tx1 := types.NewTransaction(1, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(2, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+
+	// unknown txs
+	seen, _ := tracker.getSeenTx(tx1.Hash())
+	require.Nil(seen)
+	require.Equal(hash.ZeroEvent, tracker.getTxEvent(tx1.Hash()))
+
+	// txs are memorized when they appear in the txpool
+	txpool.txFeed.Send(evmcore.NewTxsNotify{Txs: types.Transactions{tx1}})
+	require.Eventually(func() bool {
+		seen, _ := tracker.getSeenTx(tx1.Hash())
+		return seen != nil
+	}, time.Second, 10*time.Millisecond)
+	seen, seenTime := tracker.getSeenTx(tx1.Hash())
+	require.Equal(tx1.Hash(), seen.Hash())
+
+	// the first seen time isn't overwritten
+	tracker.onNewTxs(types.Transactions{tx1})
+	_, seenTime2 := tracker.getSeenTx(tx1.Hash())
+	require.Equal(seenTime, seenTime2)
+
+	// txs are memorized when they are included into an event
+	e := &inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(1)
+	e.SetCreator(1)
+	e.SetTxs(types.Transactions{tx1, tx2})
+	e.SetPayloadHash(inter.CalcPayloadHash(e))
+	event := e.Build()
+	tracker.onNewEvent(event)
+	require.Equal(event.ID(), tracker.getTxEvent(tx1.Hash()))
+	require.Equal(event.ID(), tracker.getTxEvent(tx2.Hash()))
+	seen, _ = tracker.getSeenTx(tx2.Hash())
+	require.Nil(seen)
+}
@@ -0,0 +1,288 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-galaxy/inter"
+	"go-galaxy/inter/iblockproc"
+)
+
+// Event represents a Lachesis event.
+type Event struct {
+	backend Backend
+	event   *inter.EventPayload
+}
+
+// newEvent fetches the event by its full or short ID. Returns nil if the event isn't found.
+func newEvent(ctx context.Context, backend Backend, id string) (*Event, error) {
+	event, err := backend.GetEventPayload(ctx, id)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return &Event{
+		backend: backend,
+		event:   event,
+	}, nil
+}
+
+func (e *Event) ID(ctx context.Context) common.Hash {
+	return common.Hash(e.event.ID())
+}
+
+func (e *Event) Epoch(ctx context.Context) *Epoch {
+	return &Epoch{
+		backend: e.backend,
+		number:  rpc.BlockNumber(e.event.Epoch()),
+	}
+}
+
+func (e *Event) Seq(ctx context.Context) Long {
+	return Long(e.event.Seq())
+}
+
+func (e *Event) Frame(ctx context.Context) Long {
+	return Long(e.event.Frame())
+}
+
+func (e *Event) Creator(ctx context.Context) Long {
+	return Long(e.event.Creator())
+}
+
+func (e *Event) Lamport(ctx context.Context) Long {
+	return Long(e.event.Lamport())
+}
+
+func (e *Event) CreationTime(ctx context.Context) Long {
+	return Long(e.event.CreationTime())
+}
+
+func (e *Event) MedianTime(ctx context.Context) Long {
+	return Long(e.event.MedianTime())
+}
+
+// Parents returns the parent events. Parents which aren't stored anymore are skipped.
+func (e *Event) Parents(ctx context.Context) ([]*Event, error) {
+	ret := make([]*Event, 0, len(e.event.Parents()))
+	for _, id := range e.event.Parents() {
+		parent, err := newEvent(ctx, e.backend, id.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if parent != nil {
+			ret = append(ret, parent)
+		}
+	}
+	return ret, nil
+}
+
+func (e *Event) ExtraData(ctx context.Context) hexutil.Bytes {
+	return e.event.Extra()
+}
+
+func (e *Event) PayloadHash(ctx context.Context) common.Hash {
+	return common.Hash(e.event.PayloadHash())
+}
+
+func (e *Event) GasPowerUsed(ctx context.Context) Long {
+	return Long(e.event.GasPowerUsed())
+}
+
+func (e *Event) TransactionCount(ctx context.Context) int32 {
+	return int32(e.event.Txs().Len())
+}
+
+func (e *Event) Transactions(ctx context.Context) []*Transaction {
+	ret := make([]*Transaction, 0, e.event.Txs().Len())
+	for _, tx := range e.event.Txs() {
+		ret = append(ret, &Transaction{
+			backend: e.backend,
+			hash:    tx.Hash(),
+			tx:      tx,
+		})
+	}
+	return ret
+}
+
+// Epoch represents a Lachesis epoch.
+// backend and number are mandatory; the epoch state will be fetched when required.
+type Epoch struct {
+	backend Backend
+	number  rpc.BlockNumber
+	state   *iblockproc.EpochState
+}
+
+// resolve returns the epoch state, fetching it if needed.
+func (e *Epoch) resolve(ctx context.Context) (*iblockproc.EpochState, error) {
+	if e.state != nil {
+		return e.state, nil
+	}
+	state, err := e.backend.GetEpochState(ctx, e.number)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, fmt.Errorf("epoch %d not found", e.number)
+	}
+	e.state = state
+	return e.state, nil
+}
+
+func (e *Epoch) Number(ctx context.Context) (Long, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(state.Epoch), nil
+}
+
+// StartTime returns the time of the epoch start in nanoseconds.
+func (e *Epoch) StartTime(ctx context.Context) (Long, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(state.EpochStart), nil
+}
+
+// EndTime returns the time of the epoch sealing in nanoseconds, or nil if the epoch isn't sealed yet.
+func (e *Epoch) EndTime(ctx context.Context) (*Long, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.Epoch >= e.backend.CurrentEpoch(ctx) {
+		return nil, nil
+	}
+	next, err := e.backend.GetEpochState(ctx, rpc.BlockNumber(state.Epoch+1))
+	if err != nil || next == nil {
+		return nil, err
+	}
+	end := Long(next.EpochStart)
+	return &end, nil
+}
+
+func (e *Epoch) TotalWeight(ctx context.Context) (Long, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(state.Validators.TotalWeight()), nil
+}
+
+func (e *Epoch) Validators(ctx context.Context) ([]*Validator, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Validator, 0, state.Validators.Len())
+	for _, id := range state.Validators.SortedIDs() {
+		ret = append(ret, newValidator(id, state))
+	}
+	return ret, nil
+}
+
+func (e *Epoch) Validator(ctx context.Context, args struct{ ID Long }) (*Validator, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := idx.ValidatorID(args.ID)
+	if !state.Validators.Exists(id) {
+		return nil, nil
+	}
+	return newValidator(id, state), nil
+}
+
+// Heads returns the epoch events with no descendants.
+// Heads are available only for the current epoch, otherwise nil is returned.
+func (e *Epoch) Heads(ctx context.Context) (*[]*Event, error) {
+	state, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.Epoch != e.backend.CurrentEpoch(ctx) {
+		return nil, nil
+	}
+	heads, err := e.backend.GetHeads(ctx, rpc.BlockNumber(state.Epoch))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Event, 0, len(heads))
+	for _, id := range heads {
+		head, err := newEvent(ctx, e.backend, id.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if head != nil {
+			ret = append(ret, head)
+		}
+	}
+	return &ret, nil
+}
+
+// Validator represents a validator of a Lachesis epoch.
+type Validator struct {
+	id              idx.ValidatorID
+	weight          hexutil.Big
+	consensusWeight Long
+	pubKey          hexutil.Bytes
+}
+
+func newValidator(id idx.ValidatorID, state *iblockproc.EpochState) *Validator {
+	v := &Validator{
+		id:              id,
+		consensusWeight: Long(state.Validators.Get(id)),
+	}
+	if profile, ok := state.ValidatorProfiles[id]; ok {
+		v.weight = hexutil.Big(*profile.Weight)
+		v.pubKey = profile.PubKey.Bytes()
+	}
+	return v
+}
+
+func (v *Validator) ID(ctx context.Context) Long {
+	return Long(v.id)
+}
+
+// Weight returns the validator's stake.
+func (v *Validator) Weight(ctx context.Context) hexutil.Big {
+	return v.weight
+}
+
+// ConsensusWeight returns the validator's weight in the consensus.
+func (v *Validator) ConsensusWeight(ctx context.Context) Long {
+	return v.consensusWeight
+}
+
+func (v *Validator) PubKey(ctx context.Context) hexutil.Bytes {
+	return v.pubKey
+}
+
+// Event returns a Lachesis event by its full or short ID.
+func (r *Resolver) Event(ctx context.Context, args struct{ ID string }) (*Event, error) {
+	return newEvent(ctx, r.backend, args.ID)
+}
+
+// Epoch returns a Lachesis epoch by its number. If the number isn't supplied, the current epoch is returned.
+func (r *Resolver) Epoch(ctx context.Context, args struct{ Number *Long }) (*Epoch, error) {
+	epoch := &Epoch{
+		backend: r.backend,
+		number:  rpc.PendingBlockNumber,
+	}
+	if args.Number != nil {
+		if *args.Number < 0 {
+			return nil, nil
+		}
+		epoch.number = rpc.BlockNumber(*args.Number)
+	}
+	if _, err := epoch.resolve(ctx); err != nil {
+		return nil, err
+	}
+	return epoch, nil
+}
@@ -0,0 +1,28 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaResolvers(t *testing.T) {
+	// every schema field must be implemented by the resolvers
+	_, err := graphql.ParseSchema(schema, &Resolver{})
+	require.NoError(t, err)
+}
+
+func TestLongUnmarshal(t *testing.T) {
+	require := require.New(t)
+
+	var v Long
+	require.NoError(v.UnmarshalGraphQL("123"))
+	require.Equal(Long(123), v)
+	require.NoError(v.UnmarshalGraphQL(int32(-5)))
+	require.Equal(Long(-5), v)
+	require.NoError(v.UnmarshalGraphQL(int64(1) << 40))
+	require.Equal(Long(1<<40), v)
+	require.Error(v.UnmarshalGraphQL("0x10"))
+	require.Error(v.UnmarshalGraphQL(1.5))
+}
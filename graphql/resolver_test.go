@@ -0,0 +1,329 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/deamchain/deam-v2-base/inter/idx"
+	"github.com/deamchain/deam-v2-base/inter/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+
+	"go-galaxy/evmcore"
+	"go-galaxy/galaxy"
+	"go-galaxy/gossip/evmstore"
+	"go-galaxy/gossip/filters"
+	"go-galaxy/inter"
+	"go-galaxy/inter/drivertype"
+	"go-galaxy/inter/iblockproc"
+	"go-galaxy/inter/validatorpk"
+)
+
+// testBackend serves the chain of empty blocks up to head, block 1 contains the only tx.
+// Methods which aren't used by the tested resolvers aren't implemented.
+type testBackend struct {
+	Backend
+
+	head     idx.Block
+	tx       *types.Transaction
+	receipt  *types.Receipt
+	event    *inter.EventPayload
+	epochs   map[idx.Epoch]*iblockproc.EpochState
+	current  idx.Epoch
+	chainCfg *params.ChainConfig
+}
+
+const testTxBlock = 1
+
+func newTestBackend(t *testing.T) *testBackend {
+	chainCfg := galaxy.FakeNetRules().EvmChainConfig()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil), types.LatestSigner(chainCfg), key)
+	require.NoError(t, err)
+
+	e := &inter.MutableEventPayload{}
+	e.SetVersion(1)
+	e.SetEpoch(3)
+	e.SetSeq(2)
+	e.SetCreator(1)
+	e.SetTxs(types.Transactions{tx})
+	e.SetPayloadHash(inter.CalcPayloadHash(e))
+
+	epochState := func(epoch idx.Epoch, start inter.Timestamp) *iblockproc.EpochState {
+		return &iblockproc.EpochState{
+			Epoch:      epoch,
+			EpochStart: start,
+			Validators: pos.ArrayToValidators([]idx.ValidatorID{1, 2}, []pos.Weight{10, 30}),
+			ValidatorProfiles: iblockproc.ValidatorProfiles{
+				1: drivertype.Validator{Weight: big.NewInt(1000), PubKey: validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{1}}},
+				2: drivertype.Validator{Weight: big.NewInt(3000), PubKey: validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{2}}},
+			},
+		}
+	}
+
+	return &testBackend{
+		head: 2,
+		tx:   tx,
+		receipt: &types.Receipt{
+			Status:  types.ReceiptStatusSuccessful,
+			GasUsed: 21000,
+			Logs: []*types.Log{{
+				Address: common.Address{2},
+				Topics:  []common.Hash{{3}},
+				Data:    []byte{4},
+				TxHash:  tx.Hash(),
+			}},
+		},
+		event: e.Build(),
+		epochs: map[idx.Epoch]*iblockproc.EpochState{
+			2: epochState(2, 1000),
+			3: epochState(3, 2000),
+		},
+		current:  3,
+		chainCfg: chainCfg,
+	}
+}
+
+func testBlockHash(n idx.Block) common.Hash {
+	return common.BigToHash(big.NewInt(int64(n) + 0x100))
+}
+
+func (b *testBackend) block(n idx.Block) *evmcore.EvmBlock {
+	if n > b.head {
+		return nil
+	}
+	block := &evmcore.EvmBlock{
+		EvmHeader: evmcore.EvmHeader{
+			Number:  big.NewInt(int64(n)),
+			Hash:    testBlockHash(n),
+			BaseFee: big.NewInt(0),
+		},
+		Transactions: types.Transactions{},
+	}
+	if n == testTxBlock {
+		block.Transactions = types.Transactions{b.tx}
+	}
+	return block
+}
+
+func (b *testBackend) CurrentBlock() *evmcore.EvmBlock {
+	return b.block(b.head)
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number < 0 {
+		return b.CurrentBlock(), nil
+	}
+	return b.block(idx.Block(number)), nil
+}
+
+func (b *testBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error) {
+	for n := idx.Block(0); n <= b.head; n++ {
+		if testBlockHash(n) == h {
+			return b.block(n), nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	block, _ := b.BlockByNumber(ctx, number)
+	return block.Header(), nil
+}
+
+func (b *testBackend) HeaderByHash(ctx context.Context, h common.Hash) (*evmcore.EvmHeader, error) {
+	block, _ := b.BlockByHash(ctx, h)
+	return block.Header(), nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return b.chainCfg
+}
+
+func (b *testBackend) ChainDb() ethdb.Database {
+	return rawdb.NewMemoryDatabase()
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	if txHash != b.tx.Hash() {
+		return nil, 0, 0, nil
+	}
+	return b.tx, testTxBlock, 0, nil
+}
+
+func (b *testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
+	return nil
+}
+
+func (b *testBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
+	if number == testTxBlock {
+		return types.Receipts{b.receipt}, nil
+	}
+	return types.Receipts{}, nil
+}
+
+func (b *testBackend) GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error) {
+	if blockHash == testBlockHash(testTxBlock) {
+		return [][]*types.Log{b.receipt.Logs}, nil
+	}
+	return [][]*types.Log{}, nil
+}
+
+func (b *testBackend) GetTxEvent(txHash common.Hash) hash.Event {
+	if txHash == b.tx.Hash() {
+		return b.event.ID()
+	}
+	return hash.Event{}
+}
+
+func (b *testBackend) GetTxPosition(txHash common.Hash) *evmstore.TxPosition {
+	return nil
+}
+
+func (b *testBackend) GetEventPayload(ctx context.Context, id string) (*inter.EventPayload, error) {
+	if id == b.event.ID().Hex() {
+		return b.event, nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
+	return b.current
+}
+
+func (b *testBackend) GetEpochState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.EpochState, error) {
+	switch epoch {
+	case rpc.PendingBlockNumber:
+		return b.epochs[b.current], nil
+	case rpc.LatestBlockNumber:
+		return b.epochs[b.current-1], nil
+	}
+	return b.epochs[idx.Epoch(epoch)], nil
+}
+
+func (b *testBackend) GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error) {
+	return hash.Events{b.event.ID()}, nil
+}
+
+func newTestSchema(t *testing.T, backend *testBackend) *graphql.Schema {
+	s, err := graphql.ParseSchema(schema, &Resolver{backend, filters.Config{
+		IndexedLogsBlockRangeLimit:   1000,
+		UnindexedLogsBlockRangeLimit: 100,
+	}})
+	require.NoError(t, err)
+	return s
+}
+
+// query executes the query and returns the response data or errors as JSON
+func query(t *testing.T, s *graphql.Schema, q string) string {
+	res := s.Exec(context.Background(), q, "", nil)
+	if len(res.Errors) > 0 {
+		return fmt.Sprintf(`{"errors":%q}`, res.Errors[0].Message)
+	}
+	return string(res.Data)
+}
+
+func TestBlockResolvers(t *testing.T) {
+	backend := newTestBackend(t)
+	s := newTestSchema(t, backend)
+	hash1 := testBlockHash(1).Hex()
+
+	require.JSONEq(t, fmt.Sprintf(`{"block":{"number":1,"hash":%q,"transactionCount":1,"transactions":[{"hash":%q,"index":0}]}}`, hash1, backend.tx.Hash().Hex()),
+		query(t, s, `{ block(number: 1) { number hash transactionCount transactions { hash index } } }`))
+	require.JSONEq(t, `{"block":{"number":1}}`, query(t, s, fmt.Sprintf(`{ block(hash: %q) { number } }`, hash1)))
+	require.JSONEq(t, `{"block":{"number":2}}`, query(t, s, `{ block { number } }`))
+	require.JSONEq(t, `{"block":null}`, query(t, s, `{ block(number: 3) { number } }`))
+
+	require.JSONEq(t, `{"blocks":[{"number":1},{"number":2}]}`, query(t, s, `{ blocks(from: 1) { number } }`))
+	require.JSONEq(t, `{"blocks":[{"number":0},{"number":1},{"number":2}]}`, query(t, s, `{ blocks(from: 0, to: 100) { number } }`))
+	require.JSONEq(t, `{"blocks":[]}`, query(t, s, `{ blocks(from: 2, to: 1) { number } }`))
+	require.JSONEq(t, `{"errors":"from block must be a non-negative number"}`, query(t, s, `{ blocks(from: -1) { number } }`))
+
+	// the range is cut to maxBlocksRange blocks
+	backend.head = 5000
+	var res struct {
+		Blocks []struct{ Number int64 }
+	}
+	require.NoError(t, json.Unmarshal([]byte(query(t, s, `{ blocks(from: 10, to: 4000) { number } }`)), &res))
+	require.Len(t, res.Blocks, maxBlocksRange)
+	require.Equal(t, int64(10), res.Blocks[0].Number)
+	require.Equal(t, int64(10+maxBlocksRange-1), res.Blocks[maxBlocksRange-1].Number)
+}
+
+func TestTransactionResolvers(t *testing.T) {
+	backend := newTestBackend(t)
+	s := newTestSchema(t, backend)
+	txHash := backend.tx.Hash().Hex()
+
+	require.JSONEq(t, fmt.Sprintf(`{"transaction":{"hash":%q,"index":0,"block":{"number":1},"status":1,"gasUsed":21000,"event":{"id":%q}}}`, txHash, backend.event.ID().Hex()),
+		query(t, s, fmt.Sprintf(`{ transaction(hash: %q) { hash index block { number } status gasUsed event { id } } }`, txHash)))
+	require.JSONEq(t, `{"transaction":null}`, query(t, s, fmt.Sprintf(`{ transaction(hash: %q) { hash } }`, common.Hash{1}.Hex())))
+}
+
+func TestLogsResolvers(t *testing.T) {
+	backend := newTestBackend(t)
+	s := newTestSchema(t, backend)
+
+	require.JSONEq(t, fmt.Sprintf(`{"logs":[{"data":"0x04","topics":[%q],"account":{"address":%q},"transaction":{"hash":%q}}]}`, common.Hash{3}.Hex(), strings.ToLower(common.Address{2}.Hex()), backend.tx.Hash().Hex()),
+		query(t, s, `{ logs(filter: {fromBlock: 0, toBlock: 2}) { data topics account { address } transaction { hash } } }`))
+	require.JSONEq(t, `{"block":{"logs":[]}}`, query(t, s, `{ block(number: 2) { logs(filter: {}) { data } } }`))
+	// the range is limited by the filter config
+	require.JSONEq(t, `{"errors":"too wide blocks range, the limit is 100"}`, query(t, s, `{ logs(filter: {fromBlock: 0, toBlock: 200}) { data } }`))
+}
+
+func TestDagResolvers(t *testing.T) {
+	backend := newTestBackend(t)
+	s := newTestSchema(t, backend)
+	eventID := backend.event.ID().Hex()
+
+	require.JSONEq(t, fmt.Sprintf(`{"event":{"id":%q,"epoch":{"number":3},"seq":2,"creator":1,"parents":[],"transactionCount":1,"transactions":[{"hash":%q}]}}`, eventID, backend.tx.Hash().Hex()),
+		query(t, s, fmt.Sprintf(`{ event(id: %q) { id epoch { number } seq creator parents { id } transactionCount transactions { hash } } }`, eventID)))
+	require.JSONEq(t, `{"event":null}`, query(t, s, fmt.Sprintf(`{ event(id: %q) { id } }`, common.Hash{1}.Hex())))
+
+	// the current epoch
+	require.JSONEq(t, fmt.Sprintf(`{"epoch":{"number":3,"startTime":2000,"endTime":null,"totalWeight":40,"heads":[{"id":%q}]}}`, eventID),
+		query(t, s, `{ epoch { number startTime endTime totalWeight heads { id } } }`))
+	// a sealed epoch
+	require.JSONEq(t, `{"epoch":{"number":2,"startTime":1000,"endTime":2000,"heads":null}}`,
+		query(t, s, `{ epoch(number: 2) { number startTime endTime heads { id } } }`))
+	require.JSONEq(t, `{"errors":"epoch 1 not found"}`, query(t, s, `{ epoch(number: 1) { number } }`))
+
+	// validators are sorted by their weight
+	require.JSONEq(t, `{"epoch":{"validators":[{"id":2,"weight":"0xbb8","consensusWeight":30,"pubKey":"0xc002"},{"id":1,"weight":"0x3e8","consensusWeight":10,"pubKey":"0xc001"}],"validator":{"id":2},"missing":null}}`,
+		query(t, s, `{ epoch { validators { id weight consensusWeight pubKey } validator(id: 2) { id } missing: validator(id: 3) { id } } }`))
+}
+
+func TestHandlerContentType(t *testing.T) {
+	h := handler{Schema: newTestSchema(t, newTestBackend(t))}
+
+	for _, tc := range []struct {
+		query  string
+		status int
+	}{
+		{`{ block { number } }`, http.StatusOK},
+		{`{ unknown }`, http.StatusBadRequest},
+	} {
+		body, err := json.Marshal(map[string]string{"query": tc.query})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+		require.Equal(t, tc.status, w.Code, tc.query)
+		require.Equal(t, "application/json", w.Header().Get("Content-Type"), tc.query)
+	}
+}
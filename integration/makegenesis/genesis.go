@@ -42,20 +42,31 @@ func FakeKey(n idx.ValidatorID) *ecdsa.PrivateKey {
 }
 
 func FakeGenesisStore(firstEpoch idx.Epoch, num idx.Validator, balance, stake *big.Int) *genesisstore.Store {
+	stakes := make([]*big.Int, num)
+	for i := range stakes {
+		stakes[i] = stake
+	}
+	return FakeGenesisStoreWithStakes(firstEpoch, FakeGenesisTime, balance, stakes)
+}
+
+// FakeGenesisStoreWithStakes makes fakenet genesis with a validator per stake.
+func FakeGenesisStoreWithStakes(firstEpoch idx.Epoch, genesisTime inter.Timestamp, balance *big.Int, stakes []*big.Int) *genesisstore.Store {
 	genStore := genesisstore.NewMemStore()
 	genStore.SetRules(galaxy.FakeNetRules())
 
+	num := idx.Validator(len(stakes))
 	validators := GetFakeValidators(num)
 
 	totalSupply := new(big.Int)
-	for _, val := range validators {
+	for i, val := range validators {
+		validators[i].CreationTime = genesisTime
 		genStore.SetEvmAccount(val.Address, genesis.Account{
 			Code:    []byte{},
 			Balance: balance,
 			Nonce:   0,
 		})
 		genStore.SetDelegation(val.Address, val.ID, genesis.Delegation{
-			Stake:              stake,
+			Stake:              stakes[i],
 			Rewards:            new(big.Int),
 			LockedStake:        new(big.Int),
 			LockupFromEpoch:    0,
@@ -74,14 +85,14 @@ func FakeGenesisStore(firstEpoch idx.Epoch, num idx.Validator, balance, stake *b
 	genStore.SetMetadata(genesisstore.Metadata{
 		Validators:    validators,
 		FirstEpoch:    firstEpoch,
-		Time:          FakeGenesisTime,
-		PrevEpochTime: FakeGenesisTime - inter.Timestamp(time.Hour),
+		Time:          genesisTime,
+		PrevEpochTime: genesisTime - inter.Timestamp(time.Hour),
 		ExtraData:     []byte("fake"),
 		DriverOwner:   owner,
 		TotalSupply:   totalSupply,
 	})
 	genStore.SetBlock(0, genesis.Block{
-		Time:        FakeGenesisTime - inter.Timestamp(time.Minute),
+		Time:        genesisTime - inter.Timestamp(time.Minute),
 		Atropos:     hash.Event{},
 		Txs:         types.Transactions{},
 		InternalTxs: types.Transactions{},
@@ -0,0 +1,44 @@
+package rpcauth
+
+import "strings"
+
+// acl decides which methods a client may call.
+type acl struct {
+	modules map[string]bool
+	allow   map[string]bool
+	deny    map[string]bool
+}
+
+func newACL(modules, allow, deny []string) acl {
+	return acl{
+		modules: toSet(modules),
+		allow:   toSet(allow),
+		deny:    toSet(deny),
+	}
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// allowed checks if the method may be called.
+// The method's namespace must be exposed, and the method must be allowed and not denied either by itself or by its namespace.
+// Subscriptions are checked by the subscribe/unsubscribe methods of the namespace.
+func (a acl) allowed(method string) bool {
+	sep := strings.IndexByte(method, '_')
+	if sep <= 0 {
+		return false
+	}
+	namespace := method[:sep]
+	if !a.modules[namespace] {
+		return false
+	}
+	if a.deny[namespace] || a.deny[method] {
+		return false
+	}
+	return a.allow["*"] || a.allow[namespace] || a.allow[method]
+}
@@ -0,0 +1,236 @@
+package rpcauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+
+	"go-galaxy/utils/rate"
+)
+
+const (
+	// jwtIatWindow is the allowed deviation of the issue time of tokens without expiration time.
+	jwtIatWindow = 60 * time.Second
+	// maxAnonymousClients is the max number of IP addresses whose rate limits are tracked.
+	maxAnonymousClients = 10000
+)
+
+var (
+	errNoCredentials      = errors.New("missing API key or JWT token")
+	errInvalidCredentials = errors.New("invalid API key or JWT token")
+)
+
+// client is an authenticated client with its permissions.
+type client struct {
+	name    string
+	acl     acl
+	limiter *rate.Limiter // nil if unlimited
+}
+
+// authenticator finds clients by their credentials.
+type authenticator struct {
+	byKey     map[[sha256.Size]byte]*client
+	byJWT     []jwtClient
+	anonymous *anonymousClients // nil if anonymous requests are rejected
+	now       func() time.Time
+}
+
+// anonymousClients are the clients without credentials identified by IP addresses.
+type anonymousClients struct {
+	cfg AnonymousConfig
+	acl acl
+
+	mu      sync.Mutex
+	clients *lru.Cache // IP -> *client
+}
+
+type jwtClient struct {
+	secret []byte
+	*client
+}
+
+func newAuthenticator(cfg Config) (*authenticator, error) {
+	a := &authenticator{
+		byKey: make(map[[sha256.Size]byte]*client),
+		now:   time.Now,
+	}
+	for _, cc := range cfg.Clients {
+		c := &client{
+			name:    cc.Name,
+			acl:     newACL(cfg.Modules, cc.Allow, cc.Deny),
+			limiter: newLimiter(cc.RateLimit, cc.RateBurst),
+		}
+		if cc.APIKey != "" {
+			a.byKey[sha256.Sum256([]byte(cc.APIKey))] = c
+		}
+		if cc.JWTSecretFile != "" {
+			secret, err := readJWTSecret(cc.JWTSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("RPC client %s: %v", cc.Name, err)
+			}
+			a.byJWT = append(a.byJWT, jwtClient{secret, c})
+		}
+	}
+	if len(cfg.Anonymous.Allow) != 0 {
+		clients, _ := lru.New(maxAnonymousClients)
+		a.anonymous = &anonymousClients{
+			cfg:     cfg.Anonymous,
+			acl:     newACL(cfg.Modules, cfg.Anonymous.Allow, cfg.Anonymous.Deny),
+			clients: clients,
+		}
+	}
+	return a, nil
+}
+
+// newLimiter returns nil if the rate is unlimited.
+func newLimiter(rateLimit, burst float64) *rate.Limiter {
+	if rateLimit == 0 {
+		return nil
+	}
+	if burst == 0 {
+		burst = rateLimit
+	}
+	return rate.NewLimiter(rateLimit, burst)
+}
+
+// get returns the client of the IP address, which has its own rate limit.
+func (a *anonymousClients) get(ip string) *client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if c, ok := a.clients.Get(ip); ok {
+		return c.(*client)
+	}
+	c := &client{
+		name:    "anonymous",
+		acl:     a.acl,
+		limiter: newLimiter(a.cfg.RateLimit, a.cfg.RateBurst),
+	}
+	a.clients.Add(ip, c)
+	return c
+}
+
+// readJWTSecret reads the hex-encoded 32 bytes secret.
+func readJWTSecret(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("JWT secret in %s must be 32 hex-encoded bytes", path)
+	}
+	return secret, nil
+}
+
+// authenticate returns the client which made the request.
+// Requests without credentials are made by anonymous clients if they're allowed.
+func (a *authenticator) authenticate(r *http.Request) (*client, error) {
+	var credential string
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return nil, errInvalidCredentials
+		}
+		credential = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	} else if key := r.Header.Get("X-API-Key"); key != "" {
+		credential = key
+	} else {
+		credential = r.URL.Query().Get("apikey")
+	}
+	if credential == "" {
+		if a.anonymous == nil {
+			return nil, errNoCredentials
+		}
+		return a.anonymous.get(remoteIP(r)), nil
+	}
+
+	if c := a.byKey[sha256.Sum256([]byte(credential))]; c != nil {
+		return c, nil
+	}
+	if strings.Count(credential, ".") == 2 {
+		for _, c := range a.byJWT {
+			if verifyJWT(credential, c.secret, a.now()) == nil {
+				return c.client, nil
+			}
+		}
+	}
+	return nil, errInvalidCredentials
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// verifyJWT checks the HS256 signature of the token and its time claims.
+// Tokens without expiration time must be issued within jwtIatWindow from now.
+func verifyJWT(token string, secret []byte, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Alg != "HS256" {
+		return fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return errors.New("invalid signature")
+	}
+
+	var claims struct {
+		Iat *int64 `json:"iat"`
+		Exp *int64 `json:"exp"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return err
+	}
+	switch {
+	case claims.Exp != nil:
+		if !now.Before(time.Unix(*claims.Exp, 0)) {
+			return errors.New("token is expired")
+		}
+	case claims.Iat != nil:
+		iat := time.Unix(*claims.Iat, 0)
+		if iat.Before(now.Add(-jwtIatWindow)) || iat.After(now.Add(jwtIatWindow)) {
+			return errors.New("token issue time is out of the allowed window")
+		}
+	default:
+		return errors.New("token has neither expiration nor issue time")
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,129 @@
+package rpcauth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultPort is the default TCP port of the authenticated RPC endpoint.
+const DefaultPort = 18890
+
+// Config is a configuration of the authenticated HTTP and WebSocket RPC endpoint.
+type Config struct {
+	// Host is the listening address of the endpoint. The endpoint is disabled if empty.
+	Host string
+	Port int
+	// Cors and VirtualHosts are applied to HTTP requests the same way as to the regular HTTP RPC endpoint.
+	Cors         []string
+	VirtualHosts []string
+	// WSOrigins are the allowed origins of WebSocket connections, "*" allows any origin.
+	WSOrigins []string
+	// Modules is a list of API namespaces which may be exposed to clients at all.
+	Modules []string
+	// Clients are the known clients. Requests with unknown credentials are rejected.
+	Clients []ClientConfig
+	// Anonymous is a configuration of clients without credentials, which are identified by IP addresses.
+	Anonymous AnonymousConfig
+
+	// Costs are the costs of calls by method, which are charged from the rate limits. Other calls cost 1.
+	Costs map[string]float64
+	// GasCost is an additional cost of eth_call and eth_estimateGas per 1M gas.
+	// Calls are charged for their gas limit capped by CallGasCap, calls without gas limit are charged for CallGasCap.
+	GasCost    float64
+	CallGasCap uint64
+}
+
+// AnonymousConfig is a configuration of clients without credentials.
+// Anonymous requests are rejected if no methods are allowed.
+type AnonymousConfig struct {
+	// Allow and Deny are the same as in ClientConfig.
+	Allow []string
+	Deny  []string
+	// RateLimit and RateBurst are the same as in ClientConfig, but they're applied to each IP address.
+	RateLimit float64
+	RateBurst float64
+}
+
+// ClientConfig is a configuration of a client of the authenticated RPC endpoint.
+// A client authenticates either with the static API key or with HS256 JWT tokens signed by its secret.
+type ClientConfig struct {
+	// Name identifies the client in logs.
+	Name string
+	// APIKey is passed as "Authorization: Bearer <key>", "X-API-Key: <key>" header or "apikey" URL parameter.
+	APIKey string
+	// JWTSecretFile is a file with the hex-encoded 32 bytes secret.
+	// Tokens are passed as "Authorization: Bearer <token>" header.
+	JWTSecretFile string
+	// Allow is a list of allowed namespaces (e.g. "eth") or methods (e.g. "eth_call"), "*" allows all of them.
+	Allow []string
+	// Deny is a list of denied namespaces or methods. Deny takes precedence over Allow.
+	Deny []string
+	// RateLimit is the max cost of calls per second, 0 means unlimited.
+	// RateBurst is the max cost of calls which may be made at once, defaults to RateLimit if 0.
+	// Calls which cost more than RateBurst are charged RateBurst.
+	RateLimit float64
+	RateBurst float64
+}
+
+// DefaultConfig returns the default configuration, which has the endpoint disabled.
+func DefaultConfig() Config {
+	return Config{
+		Port:         DefaultPort,
+		VirtualHosts: []string{"localhost"},
+		Modules:      []string{"eth", "deam", "dag", "abft", "net", "web3"},
+		Costs: map[string]float64{
+			"eth_getLogs":          10,
+			"eth_getEpochStats":    10,
+			"deam_getEpochStats":   10,
+			"eth_getBlockReceipts": 5,
+			"deam_simulateCalls":   5,
+		},
+		GasCost:    0.1,
+		CallGasCap: 50000000,
+	}
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	if c.Host == "" {
+		return nil
+	}
+	if len(c.Clients) == 0 && len(c.Anonymous.Allow) == 0 {
+		return errors.New("authenticated RPC endpoint has neither clients nor anonymous access")
+	}
+	if c.Anonymous.RateLimit < 0 || c.Anonymous.RateBurst < 0 {
+		return errors.New("anonymous RPC clients have a negative rate limit")
+	}
+	for method, cost := range c.Costs {
+		if cost < 0 {
+			return fmt.Errorf("RPC method %s has a negative cost", method)
+		}
+	}
+	if c.GasCost < 0 {
+		return errors.New("RPC gas cost is negative")
+	}
+	names := make(map[string]bool, len(c.Clients))
+	keys := make(map[string]bool, len(c.Clients))
+	for i, client := range c.Clients {
+		if client.Name == "" {
+			return fmt.Errorf("RPC client #%d has no name", i)
+		}
+		if names[client.Name] {
+			return fmt.Errorf("RPC client %s is duplicated", client.Name)
+		}
+		names[client.Name] = true
+		if (client.APIKey == "") == (client.JWTSecretFile == "") {
+			return fmt.Errorf("RPC client %s must have either an API key or a JWT secret file", client.Name)
+		}
+		if client.APIKey != "" {
+			if keys[client.APIKey] {
+				return fmt.Errorf("API key of RPC client %s is duplicated", client.Name)
+			}
+			keys[client.APIKey] = true
+		}
+		if client.RateLimit < 0 || client.RateBurst < 0 {
+			return fmt.Errorf("RPC client %s has a negative rate limit", client.Name)
+		}
+	}
+	return nil
+}
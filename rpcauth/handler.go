@@ -0,0 +1,304 @@
+package rpcauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxRequestContentLength = 1024 * 1024 * 5
+	wsMessageSizeLimit      = 15 * 1024 * 1024
+
+	// errcodeMethodNotAllowed and errcodeLimitExceeded are the codes of EIP-1474.
+	errcodeMethodNotAllowed = -32004
+	errcodeLimitExceeded    = -32005
+)
+
+// Handler serves JSON-RPC requests over HTTP and WebSocket for the authenticated clients.
+// Calls are checked against the client's permissions and charged from its rate limit before being passed to the RPC server.
+type Handler struct {
+	srv      *rpc.Server
+	auth     *authenticator
+	costs    *costs
+	meters   *methodMeters
+	upgrader websocket.Upgrader
+}
+
+func newHandler(srv *rpc.Server, auth *authenticator, cfg Config) *Handler {
+	return &Handler{
+		srv:    srv,
+		auth:   auth,
+		costs:  newCosts(cfg),
+		meters: newMethodMeters(),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     originChecker(cfg.WSOrigins),
+		},
+	}
+}
+
+// ServeHTTP authenticates the client and serves its JSON-RPC requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := h.auth.authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if isWebsocket(r) {
+		h.serveWebsocket(w, r, c)
+		return
+	}
+	h.serveHTTP(w, r, c)
+}
+
+func (h *Handler) serveHTTP(w http.ResponseWriter, r *http.Request, c *client) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodGet, http.MethodOptions:
+		// health-checks
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestContentLength+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxRequestContentLength {
+		http.Error(w, "content length too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	allowed, rejected, batch := h.filter(c, body)
+	if len(rejected) == 0 {
+		h.forward(w, r, body)
+		return
+	}
+	if allowed == nil {
+		writeJSON(w, http.StatusOK, rejectedResponse(rejected, batch))
+		return
+	}
+	// only batches may be partially rejected, merge the responses of allowed calls with the rejected ones
+	rec := newResponseRecorder()
+	h.forward(rec, r, allowed)
+	var responses []json.RawMessage
+	if rec.code != http.StatusOK || json.Unmarshal(rec.body.Bytes(), &responses) != nil {
+		rec.writeTo(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, append(responses, rejected...))
+}
+
+// forward passes the request with the given body to the RPC server.
+func (h *Handler) forward(w http.ResponseWriter, r *http.Request, body []byte) {
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	h.srv.ServeHTTP(w, r)
+}
+
+func (h *Handler) serveWebsocket(w http.ResponseWriter, r *http.Request, c *client) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("WebSocket upgrade failed", "client", c.name, "err", err)
+		return
+	}
+	conn.SetReadLimit(wsMessageSizeLimit)
+	// connection may outlive the read timeout of HTTP requests
+	_ = conn.SetReadDeadline(time.Time{})
+
+	var mu sync.Mutex
+	encode := func(v interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteJSON(v)
+	}
+	decode := func(v interface{}) error {
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return err
+			}
+			allowed, rejected, batch := h.filter(c, data)
+			if len(rejected) != 0 {
+				if err := encode(rejectedResponse(rejected, batch)); err != nil {
+					return err
+				}
+			}
+			if allowed != nil {
+				return json.Unmarshal(allowed, v)
+			}
+		}
+	}
+	h.srv.ServeCodec(rpc.NewFuncCodec(conn, encode, decode), 0)
+}
+
+// callHeader is the part of JSON-RPC call which is needed to check it.
+type callHeader struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type errorResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// filter removes the calls which aren't permitted to the client from the single call or batch.
+// Returns the permitted calls, or nil if there're none of them, and the error responses of rejected calls.
+// Malformed messages are permitted, so the RPC server responds to them.
+func (h *Handler) filter(c *client, data []byte) (allowed []byte, rejected []json.RawMessage, batch bool) {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	batch = len(data) != 0 && data[0] == '['
+
+	var msgs []json.RawMessage
+	if !batch {
+		msgs = []json.RawMessage{data}
+	} else if err := json.Unmarshal(data, &msgs); err != nil || len(msgs) == 0 {
+		return data, nil, batch
+	}
+
+	permitted := make([]json.RawMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		var call callHeader
+		if err := json.Unmarshal(msg, &call); err != nil || call.Method == "" {
+			permitted = append(permitted, msg)
+			continue
+		}
+		code, message := h.check(c, &call)
+		if code == 0 {
+			permitted = append(permitted, msg)
+			continue
+		}
+		log.Debug("Rejected RPC call", "client", c.name, "method", call.Method, "reason", message)
+		// notifications have no responses
+		if len(call.ID) != 0 {
+			rejected = append(rejected, newErrorResponse(call.ID, code, message))
+		}
+	}
+
+	switch {
+	case len(permitted) == len(msgs):
+		return data, nil, batch
+	case len(permitted) == 0:
+		return nil, rejected, batch
+	default:
+		allowed, _ = json.Marshal(permitted)
+		return allowed, rejected, batch
+	}
+}
+
+// check returns the error code and message if the call is rejected, or zero code otherwise.
+// Permitted calls are charged from the client's rate limit.
+func (h *Handler) check(c *client, call *callHeader) (int, string) {
+	if !c.acl.allowed(call.Method) {
+		deniedMeter.Mark(1)
+		return errcodeMethodNotAllowed, "the method " + call.Method + " is not allowed"
+	}
+	meter := h.meters.get(call.Method)
+	meter.calls.Mark(1)
+	cost := h.costs.cost(call.Method, call.Params)
+	if c.limiter != nil {
+		if cost > c.limiter.Burst() {
+			cost = c.limiter.Burst()
+		}
+		if !c.limiter.Allow(cost) {
+			meter.limited.Mark(1)
+			return errcodeLimitExceeded, "rate limit exceeded"
+		}
+	}
+	meter.cost.Mark(int64(math.Round(cost)))
+	return 0, ""
+}
+
+func newErrorResponse(id json.RawMessage, code int, message string) json.RawMessage {
+	resp := errorResponse{Version: "2.0", ID: id}
+	resp.Error.Code = code
+	resp.Error.Message = message
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+func rejectedResponse(rejected []json.RawMessage, batch bool) interface{} {
+	if !batch {
+		return rejected[0]
+	}
+	return rejected
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// responseRecorder buffers the response of the RPC server.
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.code = code
+}
+
+func (r *responseRecorder) writeTo(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.code)
+	_, _ = w.Write(r.body.Bytes())
+}
+
+func isWebsocket(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get("Upgrade")) == "websocket" &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+// originChecker allows WebSocket connections from the given origins and connections without origin.
+func originChecker(origins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.ToLower(origin)] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowed["*"] || allowed[strings.ToLower(origin)]
+	}
+}
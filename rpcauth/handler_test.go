@@ -0,0 +1,316 @@
+package rpcauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type testService struct{}
+
+func (testService) Echo(s string) string {
+	return s
+}
+
+func (testService) Secret() string {
+	return "secret"
+}
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestServer(t *testing.T) *httptest.Server {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("test", testService{}))
+	require.NoError(t, srv.RegisterName("admin", testService{}))
+
+	dir, err := ioutil.TempDir("", "rpcauth")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	secretFile := filepath.Join(dir, "jwt.hex")
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte(hexutil.Encode(testSecret)+"\n"), 0600))
+
+	cfg := Config{
+		Host:    "localhost",
+		Modules: []string{"test", "admin"},
+		Clients: []ClientConfig{
+			{
+				Name:   "full",
+				APIKey: "full-key",
+				Allow:  []string{"*"},
+				Deny:   []string{"admin", "test_secret"},
+			},
+			{
+				Name:      "limited",
+				APIKey:    "limited-key",
+				Allow:     []string{"test_echo"},
+				RateLimit: 0.001,
+				RateBurst: 2,
+			},
+			{
+				Name:          "jwt",
+				JWTSecretFile: secretFile,
+				Allow:         []string{"admin"},
+			},
+			{
+				Name:      "costly",
+				APIKey:    "costly-key",
+				Allow:     []string{"test"},
+				RateLimit: 0.001,
+				RateBurst: 10,
+			},
+		},
+		Anonymous: AnonymousConfig{
+			Allow:     []string{"test_echo"},
+			RateLimit: 0.001,
+			RateBurst: 1,
+		},
+		Costs: map[string]float64{
+			"test_secret": 4,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	auth, err := newAuthenticator(cfg)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(newHandler(srv, auth, cfg))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func makeJWT(secret []byte, claims string) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func post(t *testing.T, url string, header map[string]string, body string) (int, string) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+// results returns results or error codes of the responses by their IDs.
+func results(t *testing.T, body string) map[int]interface{} {
+	var responses []struct {
+		ID     int
+		Result interface{}
+		Error  *struct{ Code int }
+	}
+	if strings.HasPrefix(strings.TrimSpace(body), "{") {
+		body = "[" + body + "]"
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &responses), body)
+	res := make(map[int]interface{}, len(responses))
+	for _, r := range responses {
+		if r.Error != nil {
+			res[r.ID] = r.Error.Code
+		} else {
+			res[r.ID] = r.Result
+		}
+	}
+	return res
+}
+
+func TestAuthentication(t *testing.T) {
+	server := newTestServer(t)
+	call := `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hi"]}`
+	adminCall := `{"jsonrpc":"2.0","id":1,"method":"admin_echo","params":["hi"]}`
+
+	code, _ := post(t, server.URL, map[string]string{"X-API-Key": "wrong"}, call)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	for _, header := range []map[string]string{
+		{"X-API-Key": "full-key"},
+		{"Authorization": "Bearer full-key"},
+	} {
+		code, body := post(t, server.URL, header, call)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[int]interface{}{1: "hi"}, results(t, body))
+	}
+	code, body := post(t, server.URL+"?apikey=full-key", nil, call)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[int]interface{}{1: "hi"}, results(t, body))
+
+	now := time.Now().Unix()
+	for _, tc := range []struct {
+		claims string
+		ok     bool
+	}{
+		{`{"iat":0}`, false},
+		{`{"iat":` + jsonInt(now) + `}`, true},
+		{`{"iat":` + jsonInt(now-3600) + `}`, false},
+		{`{"exp":` + jsonInt(now+3600) + `}`, true},
+		{`{"exp":` + jsonInt(now-1) + `}`, false},
+		{`{"iat":` + jsonInt(now-3600) + `,"exp":` + jsonInt(now+3600) + `}`, true},
+		{`{}`, false},
+	} {
+		code, body := post(t, server.URL, map[string]string{"Authorization": "Bearer " + makeJWT(testSecret, tc.claims)}, adminCall)
+		if !tc.ok {
+			require.Equal(t, http.StatusUnauthorized, code, tc.claims)
+			continue
+		}
+		require.Equal(t, http.StatusOK, code, tc.claims)
+		require.Equal(t, map[int]interface{}{1: "hi"}, results(t, body))
+	}
+	wrongSecret := append([]byte{}, testSecret...)
+	wrongSecret[0]++
+	code, _ = post(t, server.URL, map[string]string{"Authorization": "Bearer " + makeJWT(wrongSecret, `{"iat":`+jsonInt(now)+`}`)}, adminCall)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func jsonInt(v int64) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func TestAccessControl(t *testing.T) {
+	require := require.New(t)
+	server := newTestServer(t)
+	full := map[string]string{"X-API-Key": "full-key"}
+
+	_, body := post(t, server.URL, full, `{"jsonrpc":"2.0","id":1,"method":"admin_echo","params":["hi"]}`)
+	require.Equal(map[int]interface{}{1: errcodeMethodNotAllowed}, results(t, body))
+
+	_, body = post(t, server.URL, full, `[
+		{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hi"]},
+		{"jsonrpc":"2.0","id":2,"method":"test_secret"},
+		{"jsonrpc":"2.0","method":"test_secret"},
+		{"jsonrpc":"2.0","id":3,"method":"rpc_modules"},
+		{"jsonrpc":"2.0","id":4,"method":"test_echo","params":["there"]}
+	]`)
+	require.Equal(map[int]interface{}{
+		1: "hi",
+		2: errcodeMethodNotAllowed,
+		3: errcodeMethodNotAllowed,
+		4: "there",
+	}, results(t, body))
+
+	_, body = post(t, server.URL, full, `[{"jsonrpc":"2.0","id":1,"method":"admin_echo"},{"jsonrpc":"2.0","id":2,"method":"test_secret"}]`)
+	require.Equal(map[int]interface{}{1: errcodeMethodNotAllowed, 2: errcodeMethodNotAllowed}, results(t, body))
+
+	// malformed requests are handled by the RPC server
+	code, body := post(t, server.URL, full, `{"jsonrpc":"2.0","id":1,"method":`)
+	require.Equal(http.StatusOK, code)
+	require.Contains(body, "parse error")
+}
+
+func TestRateLimit(t *testing.T) {
+	require := require.New(t)
+	server := newTestServer(t)
+	limited := map[string]string{"X-API-Key": "limited-key"}
+
+	_, body := post(t, server.URL, limited, `[
+		{"jsonrpc":"2.0","id":1,"method":"test_secret"},
+		{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["a"]},
+		{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["b"]},
+		{"jsonrpc":"2.0","id":4,"method":"test_echo","params":["c"]}
+	]`)
+	require.Equal(map[int]interface{}{
+		1: errcodeMethodNotAllowed,
+		2: "a",
+		3: "b",
+		4: errcodeLimitExceeded,
+	}, results(t, body))
+
+	// limits are per client
+	_, body = post(t, server.URL, map[string]string{"X-API-Key": "full-key"}, `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["d"]}`)
+	require.Equal(map[int]interface{}{1: "d"}, results(t, body))
+}
+
+func TestWebsocket(t *testing.T) {
+	require := require.New(t)
+	server := newTestServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, err := rpc.DialWebsocket(context.Background(), url+"?apikey=wrong", "")
+	require.Error(err)
+
+	client, err := rpc.DialWebsocket(context.Background(), url+"?apikey=full-key", "")
+	require.NoError(err)
+	defer client.Close()
+
+	var res string
+	require.NoError(client.Call(&res, "test_echo", "hi"))
+	require.Equal("hi", res)
+	err = client.Call(&res, "test_secret")
+	require.Error(err)
+	require.Equal(errcodeMethodNotAllowed, err.(rpc.Error).ErrorCode())
+
+	batch := []rpc.BatchElem{
+		{Method: "test_echo", Args: []interface{}{"a"}, Result: new(string)},
+		{Method: "admin_echo", Args: []interface{}{"b"}, Result: new(string)},
+	}
+	require.NoError(client.BatchCall(batch))
+	require.NoError(batch[0].Error)
+	require.Equal("a", *batch[0].Result.(*string))
+	require.Error(batch[1].Error)
+}
+
+func TestCosts(t *testing.T) {
+	require := require.New(t)
+	server := newTestServer(t)
+	costly := map[string]string{"X-API-Key": "costly-key"}
+
+	// 4 + 4 + 1 + 1 of 10
+	_, body := post(t, server.URL, costly, `[
+		{"jsonrpc":"2.0","id":1,"method":"test_secret"},
+		{"jsonrpc":"2.0","id":2,"method":"test_secret"},
+		{"jsonrpc":"2.0","id":3,"method":"test_secret"},
+		{"jsonrpc":"2.0","id":4,"method":"test_echo","params":["a"]},
+		{"jsonrpc":"2.0","id":5,"method":"test_echo","params":["b"]},
+		{"jsonrpc":"2.0","id":6,"method":"test_echo","params":["c"]}
+	]`)
+	require.Equal(map[int]interface{}{
+		1: "secret",
+		2: "secret",
+		3: errcodeLimitExceeded,
+		4: "a",
+		5: "b",
+		6: errcodeLimitExceeded,
+	}, results(t, body))
+}
+
+func TestAnonymous(t *testing.T) {
+	require := require.New(t)
+	server := newTestServer(t)
+
+	_, body := post(t, server.URL, nil, `[
+		{"jsonrpc":"2.0","id":1,"method":"test_secret"},
+		{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["a"]},
+		{"jsonrpc":"2.0","id":3,"method":"test_echo","params":["b"]}
+	]`)
+	require.Equal(map[int]interface{}{
+		1: errcodeMethodNotAllowed,
+		2: "a",
+		3: errcodeLimitExceeded,
+	}, results(t, body))
+
+	// the limit is tracked per IP address
+	_, body = post(t, server.URL, nil, `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["c"]}`)
+	require.Equal(map[int]interface{}{1: errcodeLimitExceeded}, results(t, body))
+}
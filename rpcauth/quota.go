@@ -0,0 +1,112 @@
+package rpcauth
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// maxMeteredMethods is the max number of methods with their own meters.
+// Method names come from clients, so calls of other methods are metered together.
+const maxMeteredMethods = 256
+
+var (
+	deniedMeter = metrics.GetOrRegisterMeter("rpc/auth/denied", nil)
+
+	// gasMethods are charged for the gas in addition to their costs.
+	gasMethods = map[string]bool{
+		"eth_call":         true,
+		"eth_estimateGas":  true,
+		"deam_call":        true,
+		"deam_estimateGas": true,
+	}
+)
+
+// costs computes the costs of calls, which are charged from the rate limits.
+type costs struct {
+	table      map[string]float64
+	gasCost    float64
+	callGasCap uint64
+}
+
+func newCosts(cfg Config) *costs {
+	return &costs{
+		table:      cfg.Costs,
+		gasCost:    cfg.GasCost,
+		callGasCap: cfg.CallGasCap,
+	}
+}
+
+// cost returns the cost of the method call with the given params.
+func (c *costs) cost(method string, params json.RawMessage) float64 {
+	cost, ok := c.table[method]
+	if !ok {
+		cost = 1
+	}
+	if c.gasCost != 0 && gasMethods[method] {
+		cost += c.gasCost * float64(c.callGas(params)) / 1e6
+	}
+	return cost
+}
+
+// callGas returns the gas limit of the call capped by callGasCap.
+func (c *costs) callGas(params json.RawMessage) uint64 {
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 {
+		return c.callGasCap
+	}
+	var call struct {
+		Gas *hexutil.Uint64 `json:"gas"`
+	}
+	if err := json.Unmarshal(args[0], &call); err != nil || call.Gas == nil {
+		return c.callGasCap
+	}
+	if c.callGasCap != 0 && uint64(*call.Gas) > c.callGasCap {
+		return c.callGasCap
+	}
+	return uint64(*call.Gas)
+}
+
+// methodMeters are the meters of calls by method.
+type methodMeters struct {
+	mu     sync.Mutex
+	meters map[string]*methodMeter
+}
+
+type methodMeter struct {
+	calls   metrics.Meter
+	cost    metrics.Meter
+	limited metrics.Meter
+}
+
+func newMethodMeters() *methodMeters {
+	return &methodMeters{
+		meters: make(map[string]*methodMeter),
+	}
+}
+
+// get returns the meters of the method.
+func (m *methodMeters) get(method string) *methodMeter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if mm := m.meters[method]; mm != nil {
+		return mm
+	}
+	if len(m.meters) >= maxMeteredMethods {
+		method = "other"
+		if mm := m.meters[method]; mm != nil {
+			return mm
+		}
+	}
+	prefix := "rpc/auth/" + method + "/"
+	mm := &methodMeter{
+		calls:   metrics.GetOrRegisterMeter(prefix+"calls", nil),
+		cost:    metrics.GetOrRegisterMeter(prefix+"cost", nil),
+		limited: metrics.GetOrRegisterMeter(prefix+"limited", nil),
+	}
+	m.meters[method] = mm
+	return mm
+}
@@ -0,0 +1,51 @@
+package rpcauth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallCosts(t *testing.T) {
+	require := require.New(t)
+	c := newCosts(Config{
+		Costs:      map[string]float64{"eth_getLogs": 10, "eth_call": 2},
+		GasCost:    0.5,
+		CallGasCap: 4000000,
+	})
+
+	for _, tc := range []struct {
+		method string
+		params string
+		cost   float64
+	}{
+		{"eth_blockNumber", ``, 1},
+		{"eth_getLogs", `[{}]`, 10},
+		{"eth_call", `[{"to":"0x0000000000000000000000000000000000000001","gas":"0x1e8480"},"latest"]`, 2 + 1},
+		{"eth_call", `[{"to":"0x0000000000000000000000000000000000000001"},"latest"]`, 2 + 2},
+		{"eth_call", `[{"gas":"0xffffffff"}]`, 2 + 2},
+		{"eth_call", `"malformed"`, 2 + 2},
+		{"eth_estimateGas", `[{"gas":"0x0"}]`, 1},
+		{"deam_estimateGas", `[{"gas":"0xf4240"}]`, 1.5},
+	} {
+		require.Equal(tc.cost, c.cost(tc.method, json.RawMessage(tc.params)), tc.method+tc.params)
+	}
+}
+
+func TestMethodMeters(t *testing.T) {
+	require := require.New(t)
+	m := newMethodMeters()
+
+	call := m.get("eth_call")
+	require.True(call == m.get("eth_call"))
+	for i := 0; len(m.meters) < maxMeteredMethods; i++ {
+		m.get(string(rune('a'+i%26)) + "_" + string(rune('a'+i/26)))
+	}
+	// methods over the limit are metered together
+	other := m.get("eth_unknown")
+	require.True(other == m.get("eth_unknown2"))
+	require.True(call == m.get("eth_call"))
+	require.Len(m.meters, maxMeteredMethods+1)
+	require.Contains(m.meters, "other")
+}
@@ -0,0 +1,81 @@
+package rpcauth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Service serves the authenticated RPC endpoint while the node is running.
+// The endpoint exposes the node's APIs, which is why it must be started after the node's RPC.
+type Service struct {
+	cfg   Config
+	auth  *authenticator
+	stack *node.Node
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// New registers the authenticated RPC endpoint on the node.
+func New(stack *node.Node, cfg Config) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{
+		cfg:   cfg,
+		auth:  auth,
+		stack: stack,
+	}
+	stack.RegisterLifecycle(s)
+	return s, nil
+}
+
+// Start is called after all services have been constructed and the networking
+// layer was also initialized to spawn any goroutines required by the service.
+func (s *Service) Start() error {
+	srv, err := s.stack.RPCHandler()
+	if err != nil {
+		return err
+	}
+	handler := newHandler(srv, s.auth, s.cfg)
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port)))
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:      node.NewHTTPHandlerStack(handler, s.cfg.Cors, s.cfg.VirtualHosts),
+		ReadTimeout:  rpc.DefaultHTTPTimeouts.ReadTimeout,
+		WriteTimeout: rpc.DefaultHTTPTimeouts.WriteTimeout,
+		IdleTimeout:  rpc.DefaultHTTPTimeouts.IdleTimeout,
+	}
+	go s.server.Serve(listener)
+
+	log.Info("Authenticated RPC endpoint opened", "url", fmt.Sprintf("http://%v", listener.Addr()),
+		"clients", len(s.cfg.Clients), "modules", strings.Join(s.cfg.Modules, ","))
+	return nil
+}
+
+// Stop method invoked when the node terminates the service.
+// Open WebSocket connections are closed along with the node's RPC server.
+func (s *Service) Stop() error {
+	if s.server == nil {
+		return nil
+	}
+	err := s.server.Shutdown(context.Background())
+	log.Info("Authenticated RPC endpoint closed", "url", fmt.Sprintf("http://%v", s.listener.Addr()))
+	return err
+}
@@ -0,0 +1,181 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// eventSpan is a root span of an incoming event, which is shared by all the copies of the event received from peers
+type eventSpan struct {
+	span   trace.Span
+	stages map[string]trace.Span
+	refs   int
+}
+
+var (
+	eventSpans   = make(map[hash.Event]*eventSpan)
+	eventSpansMu sync.RWMutex
+)
+
+// StartEvent starts the root span of the event received from the peer.
+// Every StartEvent call must be paired with a FinishEvent call.
+func StartEvent(id hash.Event, peer string) {
+	if !enabled {
+		return
+	}
+
+	eventSpansMu.Lock()
+	defer eventSpansMu.Unlock()
+
+	if es, ok := eventSpans[id]; ok {
+		es.refs++
+		return
+	}
+
+	_, span := tracer.Start(context.Background(), "event", trace.WithAttributes(
+		attribute.String("event", id.String()),
+		attribute.String("peer", peer),
+		attribute.Int64("epoch", int64(id.Epoch())),
+		attribute.Int64("lamport", int64(id.Lamport())),
+	))
+	eventSpans[id] = &eventSpan{
+		span:   span,
+		stages: make(map[string]trace.Span),
+		refs:   1,
+	}
+}
+
+// FinishEvent finishes the root span of the event once all its copies are released.
+func FinishEvent(id hash.Event, err error) {
+	if !enabled {
+		return
+	}
+
+	eventSpansMu.Lock()
+	defer eventSpansMu.Unlock()
+
+	es, ok := eventSpans[id]
+	if !ok {
+		return
+	}
+	es.refs--
+	if err != nil {
+		es.span.RecordError(err)
+	}
+	if es.refs > 0 {
+		return
+	}
+	for _, stage := range es.stages {
+		stage.End()
+	}
+	if err != nil {
+		es.span.SetStatus(codes.Error, err.Error())
+	}
+	es.span.End()
+	delete(eventSpans, id)
+}
+
+// StartEventStage starts a child span of the event root span.
+// It returns a noop span if the event isn't traced.
+func StartEventStage(id hash.Event, stage string) trace.Span {
+	if !enabled {
+		return noopSpan
+	}
+
+	eventSpansMu.RLock()
+	defer eventSpansMu.RUnlock()
+
+	es, ok := eventSpans[id]
+	if !ok {
+		return noopSpan
+	}
+	_, span := tracer.Start(trace.ContextWithSpan(context.Background(), es.span), stage)
+	return span
+}
+
+// OpenEventStage starts a child span of the event root span, which is finished by CloseEventStage.
+// It's used for the stages which start and finish in different places, e.g. waiting in a queue.
+func OpenEventStage(id hash.Event, stage string) {
+	if !enabled {
+		return
+	}
+
+	eventSpansMu.Lock()
+	defer eventSpansMu.Unlock()
+
+	es, ok := eventSpans[id]
+	if !ok {
+		return
+	}
+	if _, ok := es.stages[stage]; ok {
+		return
+	}
+	_, span := tracer.Start(trace.ContextWithSpan(context.Background(), es.span), stage)
+	es.stages[stage] = span
+}
+
+// CloseEventStage finishes the event stage started by OpenEventStage.
+func CloseEventStage(id hash.Event, stage string) {
+	if !enabled {
+		return
+	}
+
+	eventSpansMu.Lock()
+	defer eventSpansMu.Unlock()
+
+	es, ok := eventSpans[id]
+	if !ok {
+		return
+	}
+	if span, ok := es.stages[stage]; ok {
+		span.End()
+		delete(es.stages, stage)
+	}
+}
+
+// EventLink returns a link to the event root span, if it's traced.
+func EventLink(id hash.Event) (trace.Link, bool) {
+	if !enabled {
+		return trace.Link{}, false
+	}
+
+	eventSpansMu.RLock()
+	defer eventSpansMu.RUnlock()
+
+	es, ok := eventSpans[id]
+	if !ok {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: es.span.SpanContext()}, true
+}
+
+// StartBlock starts the root span of the block decided by the Atropos.
+// The span is linked to the Atropos span if the Atropos is traced.
+func StartBlock(atropos hash.Event) trace.Span {
+	if !enabled {
+		return noopSpan
+	}
+
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(attribute.String("atropos", atropos.String())),
+	}
+	if link, ok := EventLink(atropos); ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+	_, span := tracer.Start(context.Background(), "block", opts...)
+	return span
+}
+
+// StartChild starts a child span of the parent span.
+func StartChild(parent trace.Span, name string, attrs ...attribute.KeyValue) trace.Span {
+	if !enabled {
+		return noopSpan
+	}
+	_, span := tracer.Start(trace.ContextWithSpan(context.Background(), parent), name, trace.WithAttributes(attrs...))
+	return span
+}
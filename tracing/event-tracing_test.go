@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/deamchain/deam-v2-base/hash"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEventTracing(t *testing.T) {
+	require := require.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	SetEnabled(true)
+	defer func() {
+		SetEnabled(false)
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	}()
+
+	id := hash.FakeEvent()
+
+	// the event is received from two peers
+	StartEvent(id, "peer1")
+	OpenEventStage(id, "dagprocessor")
+	StartEvent(id, "peer2")
+	OpenEventStage(id, "dagprocessor")
+
+	StartEventStage(id, "heavycheck").End()
+	CloseEventStage(id, "dagprocessor")
+	StartEventStage(id, "processEvent").End()
+
+	FinishEvent(id, errors.New("duplicate"))
+	require.Len(recorder.Ended(), 3)
+	FinishEvent(id, nil)
+	require.Len(recorder.Ended(), 4)
+
+	root := recorder.Ended()[3]
+	require.Equal("event", root.Name())
+	require.Equal(codes.Unset, root.Status().Code)
+	require.Len(root.Events(), 1) // recorded error of the duplicate
+	for _, s := range recorder.Ended()[:3] {
+		require.Equal(root.SpanContext().SpanID(), s.Parent().SpanID())
+	}
+
+	// block is linked to the Atropos
+	StartEvent(id, "peer1")
+	block := StartBlock(id)
+	StartChild(block, "commit").End()
+	block.End()
+	FinishEvent(id, errors.New("failed"))
+
+	spans := recorder.Ended()
+	require.Len(spans, 7)
+	require.Equal("commit", spans[4].Name())
+	require.Equal("block", spans[5].Name())
+	require.Equal(spans[5].SpanContext().SpanID(), spans[4].Parent().SpanID())
+	require.Len(spans[5].Links(), 1)
+	require.Equal(spans[6].SpanContext().SpanID(), spans[5].Links()[0].SpanContext.SpanID())
+	require.Equal(codes.Error, spans[6].Status().Code)
+
+	// unknown events aren't traced
+	StartEventStage(hash.FakeEvent(), "processEvent").End()
+	require.Len(recorder.Ended(), 7)
+}
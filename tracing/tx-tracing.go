@@ -1,18 +1,23 @@
 package tracing
 
 import (
+	"context"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
 	enabled   bool
-	txSpans   = make(map[common.Hash]opentracing.Span)
+	txSpans   = make(map[common.Hash]trace.Span)
 	txSpansMu sync.RWMutex
 
-	noopSpan = opentracing.NoopTracer{}.StartSpan("")
+	tracer = otel.Tracer("go-galaxy")
+
+	noopSpan = trace.SpanFromContext(context.Background())
 )
 
 func SetEnabled(val bool) {
@@ -35,9 +40,10 @@ func StartTx(tx common.Hash, galaxytion string) {
 		return
 	}
 
-	span := opentracing.StartSpan("lifecycle")
-	span.SetTag("txhash", tx.String())
-	span.SetTag("enter", galaxytion)
+	_, span := tracer.Start(context.Background(), "lifecycle", trace.WithAttributes(
+		attribute.String("txhash", tx.String()),
+		attribute.String("enter", galaxytion),
+	))
 	txSpans[tx] = span
 }
 
@@ -54,12 +60,12 @@ func FinishTx(tx common.Hash, galaxytion string) {
 		return
 	}
 
-	span.SetTag("exit", galaxytion)
-	span.Finish()
+	span.SetAttributes(attribute.String("exit", galaxytion))
+	span.End()
 	delete(txSpans, tx)
 }
 
-func CheckTx(tx common.Hash, galaxytion string) opentracing.Span {
+func CheckTx(tx common.Hash, galaxytion string) trace.Span {
 	if !enabled {
 		return noopSpan
 	}
@@ -73,8 +79,28 @@ func CheckTx(tx common.Hash, galaxytion string) opentracing.Span {
 		return noopSpan
 	}
 
-	return opentracing.GlobalTracer().StartSpan(
-		galaxytion,
-		opentracing.ChildOf(span.Context()),
-	)
+	_, child := tracer.Start(trace.ContextWithSpan(context.Background(), span), galaxytion)
+	return child
+}
+
+// ConfirmTx finishes the tx lifecycle span, the tx is linked to the span of the block which includes it.
+func ConfirmTx(tx common.Hash, block trace.Span) {
+	if !enabled {
+		return
+	}
+
+	txSpansMu.Lock()
+	defer txSpansMu.Unlock()
+
+	span, ok := txSpans[tx]
+	if !ok {
+		return
+	}
+
+	_, child := tracer.Start(trace.ContextWithSpan(context.Background(), span), "confirmed",
+		trace.WithLinks(trace.Link{SpanContext: block.SpanContext()}))
+	child.End()
+	span.SetAttributes(attribute.String("exit", "block"))
+	span.End()
+	delete(txSpans, tx)
 }
@@ -0,0 +1,48 @@
+package freezer
+
+// Freezer is a set of flat-file tables in one directory.
+type Freezer struct {
+	tables map[string]*Table
+}
+
+// Open opens or creates the freezer tables in the directory.
+func Open(dir string, names ...string) (*Freezer, error) {
+	f := &Freezer{
+		tables: make(map[string]*Table, len(names)),
+	}
+	for _, name := range names {
+		t, err := OpenTable(dir, name)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		f.tables[name] = t
+	}
+	return f, nil
+}
+
+// Table returns the table by name, or nil if it isn't opened.
+func (f *Freezer) Table(name string) *Table {
+	return f.tables[name]
+}
+
+// Sync flushes all the tables to disk.
+func (f *Freezer) Sync() error {
+	for _, t := range f.tables {
+		if err := t.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the tables.
+func (f *Freezer) Close() error {
+	var err error
+	for _, t := range f.tables {
+		if e := t.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
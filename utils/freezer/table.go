@@ -0,0 +1,266 @@
+package freezer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/deamchain/deam-v2-base/common/bigendian"
+)
+
+const (
+	// indexEntrySize is the size of an index entry, which is the end offset of an item in the data file
+	indexEntrySize = 8
+	// indexHeaderSize is the size of the index file header, which is the number of the first item
+	indexHeaderSize = 8
+)
+
+var (
+	// ErrOutOfBounds is returned if the item isn't in the table
+	ErrOutOfBounds = errors.New("out of bounds")
+	// ErrNotSequential is returned if the appended item doesn't follow the last one
+	ErrNotSequential = errors.New("appended item isn't sequential")
+	errClosed        = errors.New("closed")
+)
+
+// Table is an append-only flat-file table of sequentially numbered items.
+// Items are stored back to back in the data file (.dat), and the index file (.idx)
+// contains the number of the first item followed by the end offset of every item.
+type Table struct {
+	name  string
+	index *os.File
+	data  *os.File
+
+	first uint64 // number of the first item
+	items uint64 // number of items
+	size  uint64 // size of the data file
+
+	mu sync.RWMutex
+}
+
+// OpenTable opens or creates the table in the directory.
+// Incomplete items written before a crash are truncated.
+func OpenTable(dir, name string) (*Table, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		_ = index.Close()
+		return nil, err
+	}
+	t := &Table{
+		name:  name,
+		index: index,
+		data:  data,
+	}
+	if err := t.repair(); err != nil {
+		_ = t.Close()
+		return nil, fmt.Errorf("failed to open %s freezer table: %v", name, err)
+	}
+	return t, nil
+}
+
+// repair reads the table state and truncates the items which weren't completely written
+func (t *Table) repair() error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+	indexSize := uint64(indexStat.Size())
+	if indexSize < indexHeaderSize {
+		// new table
+		if err := t.index.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := t.index.WriteAt(bigendian.Uint64ToBytes(0), 0); err != nil {
+			return err
+		}
+		return t.data.Truncate(0)
+	}
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], 0); err != nil {
+		return err
+	}
+	t.first = bigendian.BytesToUint64(buf[:])
+	t.items = (indexSize - indexHeaderSize) / indexEntrySize
+
+	// drop the items which aren't written into data file
+	dataSize := uint64(dataStat.Size())
+	for ; t.items > 0; t.items-- {
+		end, err := t.readOffset(t.items - 1)
+		if err != nil {
+			return err
+		}
+		if end <= dataSize {
+			t.size = end
+			break
+		}
+	}
+	if err := t.index.Truncate(int64(indexHeaderSize + t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	return t.data.Truncate(int64(t.size))
+}
+
+// readOffset returns the end offset of the i-th item of the table
+func (t *Table) readOffset(i uint64) (uint64, error) {
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64(indexHeaderSize+i*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return bigendian.BytesToUint64(buf[:]), nil
+}
+
+// First returns the number of the first item.
+func (t *Table) First() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.first
+}
+
+// Head returns the number of the next item to append.
+func (t *Table) Head() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.first + t.items
+}
+
+// Has returns true if the item is in the table.
+func (t *Table) Has(n uint64) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return n >= t.first && n < t.first+t.items
+}
+
+// Append writes the item to the end of the table.
+// The first item of an empty table may have any number, the next ones must be sequential.
+func (t *Table) Append(n uint64, blob []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return errClosed
+	}
+	if t.items == 0 && t.first != n {
+		if _, err := t.index.WriteAt(bigendian.Uint64ToBytes(n), 0); err != nil {
+			return err
+		}
+		t.first = n
+	}
+	if n != t.first+t.items {
+		return ErrNotSequential
+	}
+	if _, err := t.data.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+	end := t.size + uint64(len(blob))
+	if _, err := t.index.WriteAt(bigendian.Uint64ToBytes(end), int64(indexHeaderSize+t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.size = end
+	t.items++
+	return nil
+}
+
+// Retrieve returns the item.
+func (t *Table) Retrieve(n uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.index == nil {
+		return nil, errClosed
+	}
+	if n < t.first || n >= t.first+t.items {
+		return nil, ErrOutOfBounds
+	}
+	i := n - t.first
+	var start uint64
+	if i > 0 {
+		var err error
+		start, err = t.readOffset(i - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.readOffset(i)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// Truncate drops all the items starting from the head.
+func (t *Table) Truncate(head uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return errClosed
+	}
+	if head >= t.first+t.items {
+		return nil
+	}
+	items := uint64(0)
+	if head > t.first {
+		items = head - t.first
+	}
+	size := uint64(0)
+	if items > 0 {
+		var err error
+		size, err = t.readOffset(items - 1)
+		if err != nil {
+			return err
+		}
+	}
+	if err := t.index.Truncate(int64(indexHeaderSize + items*indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.items = items
+	t.size = size
+	return nil
+}
+
+// Sync flushes the table files to disk.
+func (t *Table) Sync() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return errClosed
+	}
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the table files.
+func (t *Table) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return nil
+	}
+	errIndex := t.index.Close()
+	errData := t.data.Close()
+	t.index, t.data = nil, nil
+	if errIndex != nil {
+		return errIndex
+	}
+	return errData
+}
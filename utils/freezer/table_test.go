@@ -0,0 +1,89 @@
+package freezer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	table, err := OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(0), table.Head())
+
+	// the first item may have any number
+	for n := uint64(10); n < 20; n++ {
+		require.NoError(table.Append(n, make([]byte, n%4)))
+	}
+	require.Equal(ErrNotSequential, table.Append(25, []byte{1}))
+	require.Equal(uint64(10), table.First())
+	require.Equal(uint64(20), table.Head())
+	require.False(table.Has(9))
+	require.True(table.Has(19))
+
+	_, err = table.Retrieve(9)
+	require.Equal(ErrOutOfBounds, err)
+	for n := uint64(10); n < 20; n++ {
+		blob, err := table.Retrieve(n)
+		require.NoError(err)
+		require.Equal(make([]byte, n%4), blob)
+	}
+
+	// reopen
+	require.NoError(table.Close())
+	table, err = OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(10), table.First())
+	require.Equal(uint64(20), table.Head())
+	blob, err := table.Retrieve(19)
+	require.NoError(err)
+	require.Equal([]byte{0, 0, 0}, blob)
+
+	// truncate
+	require.NoError(table.Truncate(15))
+	require.Equal(uint64(15), table.Head())
+	require.NoError(table.Append(15, []byte{1, 2, 3}))
+	blob, err = table.Retrieve(15)
+	require.NoError(err)
+	require.Equal([]byte{1, 2, 3}, blob)
+	require.NoError(table.Close())
+}
+
+func TestTableRepair(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	table, err := OpenTable(dir, "test")
+	require.NoError(err)
+	for n := uint64(0); n < 5; n++ {
+		require.NoError(table.Append(n, []byte{byte(n), byte(n)}))
+	}
+	require.NoError(table.Close())
+
+	// the data of the last item is lost
+	require.NoError(os.Truncate(filepath.Join(dir, "test.dat"), 9))
+	table, err = OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(4), table.Head())
+	require.NoError(table.Append(4, []byte{5}))
+	require.NoError(table.Close())
+
+	// the index entry is written partially
+	f, err := os.OpenFile(filepath.Join(dir, "test.idx"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(err)
+	_, err = f.Write([]byte{0, 0, 1})
+	require.NoError(err)
+	require.NoError(f.Close())
+	table, err = OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(5), table.Head())
+	blob, err := table.Retrieve(4)
+	require.NoError(err)
+	require.Equal([]byte{5}, blob)
+	require.NoError(table.Close())
+}
@@ -0,0 +1,69 @@
+package rate
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a thread-safe token bucket.
+// The bucket is refilled with the given rate of tokens per second and holds at most burst tokens.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter constructs a new Limiter with a full bucket.
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+	}
+}
+
+// Allow takes cost tokens from the bucket if it holds enough of them.
+func (l *Limiter) Allow(cost float64) bool {
+	return l.AllowAt(time.Now(), cost)
+}
+
+// AllowAt takes cost tokens from the bucket at the given time if it holds enough of them.
+func (l *Limiter) AllowAt(now time.Time, cost float64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if l.tokens < cost {
+		return false
+	}
+	l.tokens -= cost
+	return true
+}
+
+// Burst returns the max number of tokens in the bucket.
+func (l *Limiter) Burst() float64 {
+	return l.burst
+}
+
+// Tokens returns the number of tokens in the bucket at the given time.
+func (l *Limiter) Tokens(now time.Time) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	return l.tokens
+}
+
+func (l *Limiter) refill(now time.Time) {
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+}
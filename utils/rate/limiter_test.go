@@ -0,0 +1,35 @@
+package rate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	require := require.New(t)
+	l := NewLimiter(2, 4)
+	now := time.Unix(1000, 0)
+
+	require.True(l.AllowAt(now, 3))
+	require.False(l.AllowAt(now, 2))
+	require.True(l.AllowAt(now, 1))
+	require.False(l.AllowAt(now, 1))
+
+	// refilled with 2 tokens per second
+	now = now.Add(500 * time.Millisecond)
+	require.Equal(1.0, l.Tokens(now))
+	require.False(l.AllowAt(now, 2))
+	now = now.Add(500 * time.Millisecond)
+	require.True(l.AllowAt(now, 2))
+
+	// bucket doesn't exceed burst
+	now = now.Add(time.Hour)
+	require.Equal(4.0, l.Tokens(now))
+
+	// time going backwards doesn't refill the bucket
+	require.True(l.AllowAt(now, 4))
+	require.False(l.AllowAt(now.Add(-time.Second), 1))
+	require.Equal(0.0, l.Tokens(now))
+}